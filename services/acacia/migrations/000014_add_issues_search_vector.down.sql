@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_issues_updated_at;
+
+DROP INDEX IF EXISTS idx_issues_created_at;
+
+DROP INDEX IF EXISTS idx_issues_search_vector;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,12 @@
+-- Full-text search over issue name (weight A) and description (weight B)
+ALTER TABLE issues
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_issues_search_vector ON issues USING GIN (search_vector);
+
+-- Supports date range filters and date ordering on search results
+CREATE INDEX idx_issues_created_at ON issues (created_at);
+CREATE INDEX idx_issues_updated_at ON issues (updated_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_project_reports_project_id_created_at;
+DROP TABLE IF EXISTS project_reports;
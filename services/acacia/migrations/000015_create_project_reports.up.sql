@@ -0,0 +1,17 @@
+CREATE TABLE project_reports (
+    id bigserial PRIMARY KEY,
+    project_id bigint NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    created_by bigint REFERENCES users (id) ON DELETE SET NULL,
+    provider varchar(50) NOT NULL,
+    model varchar(100) NOT NULL,
+    period_start timestamp NOT NULL,
+    period_end timestamp NOT NULL,
+    summary text NOT NULL,
+    done text NOT NULL,
+    in_progress text NOT NULL,
+    blocked text NOT NULL,
+    risks text NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_project_reports_project_id_created_at ON project_reports (project_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_issue_links_linked_issue_id;
+DROP TABLE IF EXISTS issue_links;
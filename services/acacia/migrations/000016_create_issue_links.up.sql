@@ -0,0 +1,12 @@
+CREATE TABLE issue_links (
+    id bigserial PRIMARY KEY,
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    linked_issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    link_type varchar(50) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    CONSTRAINT issue_links_link_type_check CHECK (link_type IN ('duplicates')),
+    CONSTRAINT issue_links_not_self CHECK (issue_id <> linked_issue_id),
+    UNIQUE (issue_id, linked_issue_id, link_type)
+);
+
+CREATE INDEX idx_issue_links_linked_issue_id ON issue_links (linked_issue_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_issue_assignees_user_id;
+DROP TABLE IF EXISTS issue_assignees;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS reporter_id;
@@ -0,0 +1,11 @@
+ALTER TABLE issues
+    ADD COLUMN reporter_id bigint REFERENCES users (id) ON DELETE SET NULL;
+
+CREATE TABLE issue_assignees (
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issue_id, user_id)
+);
+
+CREATE INDEX idx_issue_assignees_user_id ON issue_assignees (user_id);
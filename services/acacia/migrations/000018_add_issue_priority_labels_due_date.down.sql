@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_issue_labels_label_id;
+DROP TABLE IF EXISTS issue_labels;
+DROP TABLE IF EXISTS labels;
+
+DROP INDEX IF EXISTS idx_issues_due_date;
+
+ALTER TABLE issues
+    DROP CONSTRAINT IF EXISTS issues_priority_check,
+    DROP COLUMN IF EXISTS due_date,
+    DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,25 @@
+ALTER TABLE issues
+    ADD COLUMN priority varchar(20) NOT NULL DEFAULT 'none',
+    ADD COLUMN due_date date,
+    ADD CONSTRAINT issues_priority_check CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent'));
+
+CREATE INDEX idx_issues_due_date ON issues (due_date);
+
+CREATE TABLE labels (
+    id bigserial PRIMARY KEY,
+    team_id bigint NOT NULL REFERENCES teams (id) ON DELETE CASCADE,
+    name varchar(100) NOT NULL,
+    color varchar(9) NOT NULL,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW(),
+    CONSTRAINT labels_team_id_name_key UNIQUE (team_id, name)
+);
+
+CREATE TABLE issue_labels (
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    label_id bigint NOT NULL REFERENCES labels (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issue_id, label_id)
+);
+
+CREATE INDEX idx_issue_labels_label_id ON issue_labels (label_id);
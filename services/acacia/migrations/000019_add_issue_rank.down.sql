@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_issues_column_id_rank;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS rank;
@@ -0,0 +1,23 @@
+-- rank orders issues within a column, lowest first. It is a fractional numeric
+-- so moving an issue only rewrites that issue's rank.
+ALTER TABLE issues
+    ADD COLUMN rank numeric NOT NULL DEFAULT 0;
+
+-- Preserve the previous newest-first ordering
+UPDATE
+    issues i
+SET
+    rank = ordered.position
+FROM (
+    SELECT
+        id,
+        row_number() OVER (PARTITION BY column_id ORDER BY created_at DESC, id DESC) AS position
+    FROM
+        issues) ordered
+WHERE
+    ordered.id = i.id;
+
+ALTER TABLE issues
+    ALTER COLUMN rank DROP DEFAULT;
+
+CREATE INDEX idx_issues_column_id_rank ON issues (column_id, rank);
@@ -0,0 +1,7 @@
+ALTER TABLE projects
+    DROP CONSTRAINT IF EXISTS projects_wip_limit_mode_check,
+    DROP COLUMN IF EXISTS wip_limit_mode;
+
+ALTER TABLE project_status_columns
+    DROP CONSTRAINT IF EXISTS project_status_columns_wip_limit_check,
+    DROP COLUMN IF EXISTS wip_limit;
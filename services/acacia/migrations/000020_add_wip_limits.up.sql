@@ -0,0 +1,10 @@
+-- wip_limit caps the number of issues in a column; NULL means unlimited
+ALTER TABLE project_status_columns
+    ADD COLUMN wip_limit integer,
+    ADD CONSTRAINT project_status_columns_wip_limit_check CHECK (wip_limit IS NULL OR wip_limit > 0);
+
+-- wip_limit_mode decides whether exceeding a column's limit is rejected ('hard')
+-- or only reported back to the client ('soft')
+ALTER TABLE projects
+    ADD COLUMN wip_limit_mode varchar(10) NOT NULL DEFAULT 'soft',
+    ADD CONSTRAINT projects_wip_limit_mode_check CHECK (wip_limit_mode IN ('soft', 'hard'));
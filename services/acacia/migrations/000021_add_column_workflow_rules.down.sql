@@ -0,0 +1,6 @@
+ALTER TABLE project_status_columns
+    DROP CONSTRAINT IF EXISTS project_status_columns_required_fields_check,
+    DROP COLUMN IF EXISTS required_fields;
+
+DROP INDEX IF EXISTS idx_column_transitions_to_column_id;
+DROP TABLE IF EXISTS column_transitions;
@@ -0,0 +1,15 @@
+-- Allowed moves out of a column. A column without rows here lets issues move to any column.
+CREATE TABLE column_transitions (
+    from_column_id bigint NOT NULL REFERENCES project_status_columns (id) ON DELETE CASCADE,
+    to_column_id bigint NOT NULL REFERENCES project_status_columns (id) ON DELETE CASCADE,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (from_column_id, to_column_id),
+    CONSTRAINT column_transitions_distinct_check CHECK (from_column_id <> to_column_id)
+);
+
+CREATE INDEX idx_column_transitions_to_column_id ON column_transitions (to_column_id);
+
+-- Fields an issue must have before it can enter the column
+ALTER TABLE project_status_columns
+    ADD COLUMN required_fields text[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT project_status_columns_required_fields_check CHECK (required_fields <@ ARRAY['assignee', 'description', 'due_date', 'labels', 'priority']::text[]);
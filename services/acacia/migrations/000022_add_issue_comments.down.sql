@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_issue_comment_mentions_user_id;
+DROP TABLE IF EXISTS issue_comment_mentions;
+
+DROP INDEX IF EXISTS idx_issue_comments_parent_id;
+DROP INDEX IF EXISTS idx_issue_comments_issue_id;
+DROP TABLE IF EXISTS issue_comments;
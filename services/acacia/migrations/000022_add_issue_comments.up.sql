@@ -0,0 +1,24 @@
+-- parent_id threads a reply under a top-level comment; replies cannot be replied to.
+-- has_serialized_body records whether a rich-text body was stored in S3 next to the issue description.
+CREATE TABLE issue_comments (
+    id bigserial PRIMARY KEY,
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    parent_id bigint REFERENCES issue_comments (id) ON DELETE CASCADE,
+    author_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    body text NOT NULL,
+    has_serialized_body boolean NOT NULL DEFAULT FALSE,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_issue_comments_issue_id ON issue_comments (issue_id, created_at);
+
+CREATE INDEX idx_issue_comments_parent_id ON issue_comments (parent_id);
+
+CREATE TABLE issue_comment_mentions (
+    comment_id bigint NOT NULL REFERENCES issue_comments (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE INDEX idx_issue_comment_mentions_user_id ON issue_comment_mentions (user_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_issue_activity_issue_id;
+DROP TABLE IF EXISTS issue_activity;
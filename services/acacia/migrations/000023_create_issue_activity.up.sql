@@ -0,0 +1,15 @@
+-- Append-only history of issue changes. issue_id has no foreign key so the history,
+-- including the deletion itself, outlives the issue.
+-- field names the changed issue column for updates and moves; values are stored as text.
+CREATE TABLE issue_activity (
+    id bigserial PRIMARY KEY,
+    issue_id bigint NOT NULL,
+    actor_id bigint REFERENCES users (id) ON DELETE SET NULL,
+    action varchar(20) NOT NULL CHECK (action IN ('created', 'updated', 'moved', 'deleted')),
+    field varchar(50),
+    old_value text,
+    new_value text,
+    created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_issue_activity_issue_id ON issue_activity (issue_id, created_at);
@@ -0,0 +1,8 @@
+ALTER TABLE project_status_columns
+    DROP COLUMN IF EXISTS is_done;
+
+DROP INDEX IF EXISTS idx_issues_parent_id;
+
+ALTER TABLE issues
+    DROP CONSTRAINT IF EXISTS issues_parent_id_check,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,10 @@
+-- Sub-issues. The parent must be in the same project and the API rejects cycles.
+ALTER TABLE issues
+    ADD COLUMN parent_id bigint REFERENCES issues (id) ON DELETE SET NULL,
+    ADD CONSTRAINT issues_parent_id_check CHECK (parent_id <> id);
+
+CREATE INDEX idx_issues_parent_id ON issues (parent_id);
+
+-- Issues in a done column count as finished when rolling up sub-issue progress
+ALTER TABLE project_status_columns
+    ADD COLUMN is_done boolean NOT NULL DEFAULT FALSE;
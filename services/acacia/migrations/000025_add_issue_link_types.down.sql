@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_issue_links_relates_pair;
+
+DELETE FROM issue_links
+WHERE link_type <> 'duplicates';
+
+ALTER TABLE issue_links
+    DROP CONSTRAINT issue_links_link_type_check,
+    ADD CONSTRAINT issue_links_link_type_check CHECK (link_type IN ('duplicates'));
@@ -0,0 +1,9 @@
+-- issue_id blocks, relates to, duplicates or clones linked_issue_id. Links may cross projects of the same team.
+ALTER TABLE issue_links
+    DROP CONSTRAINT issue_links_link_type_check,
+    ADD CONSTRAINT issue_links_link_type_check CHECK (link_type IN ('blocks', 'clones', 'duplicates', 'relates'));
+
+-- relates has no direction, so a pair can only be related once
+CREATE UNIQUE INDEX idx_issue_links_relates_pair ON issue_links (LEAST(issue_id, linked_issue_id), GREATEST(issue_id, linked_issue_id))
+WHERE
+    link_type = 'relates';
@@ -0,0 +1,8 @@
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS number;
+
+ALTER TABLE projects
+    DROP CONSTRAINT IF EXISTS projects_key_prefix_key,
+    DROP CONSTRAINT IF EXISTS projects_key_prefix_check,
+    DROP COLUMN IF EXISTS next_issue_number,
+    DROP COLUMN IF EXISTS key_prefix;
@@ -0,0 +1,46 @@
+-- Issues are addressed as <key_prefix>-<number>, e.g. ACA-142. next_issue_number is the number the
+-- project's next issue gets; allocating it locks the project row so concurrent creates never share a number.
+ALTER TABLE projects
+    ADD COLUMN key_prefix varchar(10),
+    ADD COLUMN next_issue_number integer NOT NULL DEFAULT 1;
+
+UPDATE
+    projects
+SET
+    key_prefix = 'P' || id;
+
+ALTER TABLE projects
+    ALTER COLUMN key_prefix SET NOT NULL,
+    ADD CONSTRAINT projects_key_prefix_check CHECK (key_prefix ~ '^[A-Z][A-Z0-9]{1,9}$'),
+    ADD CONSTRAINT projects_key_prefix_key UNIQUE (key_prefix);
+
+ALTER TABLE issues
+    ADD COLUMN number integer;
+
+UPDATE
+    issues
+SET
+    number = numbered.number
+FROM (
+    SELECT
+        i.id,
+        ROW_NUMBER() OVER (PARTITION BY c.project_id ORDER BY i.created_at, i.id) AS number
+    FROM
+        issues i
+        JOIN project_status_columns c ON c.id = i.column_id) numbered
+WHERE
+    numbered.id = issues.id;
+
+UPDATE
+    projects p
+SET
+    next_issue_number = COALESCE((
+        SELECT
+            MAX(i.number)
+        FROM issues i
+        JOIN project_status_columns c ON c.id = i.column_id
+        WHERE
+            c.project_id = p.id), 0) + 1;
+
+ALTER TABLE issues
+    ALTER COLUMN number SET NOT NULL;
@@ -0,0 +1,17 @@
+UPDATE
+    issue_activity
+SET
+    field = left(field, 50)
+WHERE
+    length(field) > 50;
+
+ALTER TABLE issue_activity
+    ALTER COLUMN field TYPE varchar(50);
+
+DROP INDEX IF EXISTS idx_issue_custom_field_values_options;
+DROP INDEX IF EXISTS idx_issue_custom_field_values_user_id;
+DROP INDEX IF EXISTS idx_issue_custom_field_values_date;
+DROP INDEX IF EXISTS idx_issue_custom_field_values_number;
+DROP INDEX IF EXISTS idx_issue_custom_field_values_text;
+DROP TABLE IF EXISTS issue_custom_field_values;
+DROP TABLE IF EXISTS custom_fields;
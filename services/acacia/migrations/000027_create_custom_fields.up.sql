@@ -0,0 +1,38 @@
+-- Custom fields are defined per project; options lists the choices of select and multi_select fields.
+CREATE TABLE custom_fields (
+    id bigserial PRIMARY KEY,
+    project_id bigint NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name varchar(50) NOT NULL,
+    field_type varchar(20) NOT NULL CHECK (field_type IN ('text', 'number', 'select', 'multi_select', 'date', 'user')),
+    options text[] NOT NULL DEFAULT '{}',
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW(),
+    CONSTRAINT custom_fields_project_id_name_key UNIQUE (project_id, name)
+);
+
+-- A value is stored in the column matching its field's type so issues can be filtered by it.
+-- select and multi_select values hold the chosen option names in option_values.
+CREATE TABLE issue_custom_field_values (
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    field_id bigint NOT NULL REFERENCES custom_fields (id) ON DELETE CASCADE,
+    text_value text,
+    number_value double precision,
+    date_value date,
+    user_id bigint REFERENCES users (id) ON DELETE CASCADE,
+    option_values text[],
+    PRIMARY KEY (issue_id, field_id)
+);
+
+CREATE INDEX idx_issue_custom_field_values_text ON issue_custom_field_values (field_id, lower(text_value));
+
+CREATE INDEX idx_issue_custom_field_values_number ON issue_custom_field_values (field_id, number_value);
+
+CREATE INDEX idx_issue_custom_field_values_date ON issue_custom_field_values (field_id, date_value);
+
+CREATE INDEX idx_issue_custom_field_values_user_id ON issue_custom_field_values (user_id);
+
+CREATE INDEX idx_issue_custom_field_values_options ON issue_custom_field_values USING GIN (option_values);
+
+-- Custom field changes are recorded in the issue's activity as custom:<field name>
+ALTER TABLE issue_activity
+    ALTER COLUMN field TYPE varchar(100);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_time_entries_running_timer;
+DROP INDEX IF EXISTS idx_time_entries_user_id_started_at;
+DROP INDEX IF EXISTS idx_time_entries_issue_id;
+DROP TABLE IF EXISTS time_entries;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS estimate_minutes,
+    DROP COLUMN IF EXISTS estimate_points;
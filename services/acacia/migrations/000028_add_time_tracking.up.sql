@@ -0,0 +1,27 @@
+-- Issues can be estimated in story points, in minutes of work, or both.
+ALTER TABLE issues
+    ADD COLUMN estimate_points integer CHECK (estimate_points >= 0),
+    ADD COLUMN estimate_minutes integer CHECK (estimate_minutes >= 0);
+
+-- Time is logged per user and issue. A running timer has no ended_at and no minutes yet;
+-- manual entries are written as finished entries ending minutes after they started.
+CREATE TABLE time_entries (
+    id bigserial PRIMARY KEY,
+    issue_id bigint NOT NULL REFERENCES issues (id) ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    started_at timestamp NOT NULL,
+    ended_at timestamp,
+    minutes integer CHECK (minutes >= 0),
+    note varchar(500),
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    CHECK ((ended_at IS NULL) = (minutes IS NULL))
+);
+
+CREATE INDEX idx_time_entries_issue_id ON time_entries (issue_id);
+
+CREATE INDEX idx_time_entries_user_id_started_at ON time_entries (user_id, started_at);
+
+-- A user runs at most one timer at a time
+CREATE UNIQUE INDEX idx_time_entries_running_timer ON time_entries (user_id)
+WHERE
+    ended_at IS NULL;
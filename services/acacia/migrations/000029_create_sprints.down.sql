@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_issues_sprint_id;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS sprint_id;
+
+DROP INDEX IF EXISTS idx_sprints_active_sprint;
+DROP INDEX IF EXISTS idx_sprints_project_id;
+DROP TABLE IF EXISTS sprints;
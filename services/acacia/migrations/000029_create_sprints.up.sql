@@ -0,0 +1,28 @@
+-- Sprints time-box a project's work. A sprint is planned, then active, then completed;
+-- a project runs at most one active sprint at a time.
+CREATE TABLE sprints (
+    id bigserial PRIMARY KEY,
+    project_id bigint NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name varchar(100) NOT NULL,
+    goal text,
+    start_date date NOT NULL,
+    end_date date NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'completed')),
+    started_at timestamp,
+    completed_at timestamp,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_sprints_project_id ON sprints (project_id, start_date);
+
+CREATE UNIQUE INDEX idx_sprints_active_sprint ON sprints (project_id)
+WHERE
+    status = 'active';
+
+-- Issues without a sprint are in the project's backlog
+ALTER TABLE issues
+    ADD COLUMN sprint_id bigint REFERENCES sprints (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_sprint_id ON issues (sprint_id);
@@ -0,0 +1,16 @@
+-- Milestones group a project's issues towards a release
+CREATE TABLE milestones (
+    id bigserial PRIMARY KEY,
+    project_id bigint NOT NULL REFERENCES projects (id) ON DELETE CASCADE,
+    name varchar(100) NOT NULL,
+    description text,
+    target_date date,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    updated_at timestamp NOT NULL DEFAULT NOW(),
+    UNIQUE (project_id, name)
+);
+
+ALTER TABLE issues
+    ADD COLUMN milestone_id bigint REFERENCES milestones (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_issues_milestone_id ON issues (milestone_id);
@@ -0,0 +1,19 @@
+DELETE FROM issue_activity
+WHERE action = 'restored';
+
+ALTER TABLE issue_activity
+    DROP CONSTRAINT issue_activity_action_check,
+    ADD CONSTRAINT issue_activity_action_check CHECK (action IN ('created', 'updated', 'moved', 'deleted'));
+
+DROP INDEX IF EXISTS idx_issues_deleted_at;
+DROP INDEX IF EXISTS idx_project_status_columns_deleted_at;
+DROP INDEX IF EXISTS idx_projects_deleted_at;
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE project_status_columns
+    DROP COLUMN IF EXISTS deleted_at;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS deleted_at;
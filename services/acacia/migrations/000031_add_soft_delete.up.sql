@@ -0,0 +1,26 @@
+-- Deleted projects, columns and issues stay in the team's trash until they are restored or purged.
+-- A deleted column keeps the position it had so a restore can put it back in place.
+ALTER TABLE projects
+    ADD COLUMN deleted_at timestamp;
+
+ALTER TABLE project_status_columns
+    ADD COLUMN deleted_at timestamp;
+
+ALTER TABLE issues
+    ADD COLUMN deleted_at timestamp;
+
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+CREATE INDEX idx_project_status_columns_deleted_at ON project_status_columns (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+CREATE INDEX idx_issues_deleted_at ON issues (deleted_at)
+WHERE
+    deleted_at IS NOT NULL;
+
+ALTER TABLE issue_activity
+    DROP CONSTRAINT issue_activity_action_check,
+    ADD CONSTRAINT issue_activity_action_check CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored'));
@@ -0,0 +1,12 @@
+DELETE FROM issue_activity
+WHERE action IN ('archived', 'unarchived');
+
+ALTER TABLE issue_activity
+    DROP CONSTRAINT issue_activity_action_check,
+    ADD CONSTRAINT issue_activity_action_check CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored'));
+
+ALTER TABLE issues
+    DROP COLUMN IF EXISTS archived_at;
+
+ALTER TABLE projects
+    DROP COLUMN IF EXISTS archived_at;
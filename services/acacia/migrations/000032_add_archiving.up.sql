@@ -0,0 +1,10 @@
+-- Archived projects and issues are hidden from default listings; archived projects are read-only.
+ALTER TABLE projects
+    ADD COLUMN archived_at timestamp;
+
+ALTER TABLE issues
+    ADD COLUMN archived_at timestamp;
+
+ALTER TABLE issue_activity
+    DROP CONSTRAINT issue_activity_action_check,
+    ADD CONSTRAINT issue_activity_action_check CHECK (action IN ('created', 'updated', 'moved', 'deleted', 'restored', 'archived', 'unarchived'));
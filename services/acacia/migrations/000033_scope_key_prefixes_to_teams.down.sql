@@ -0,0 +1,48 @@
+-- Projects of different teams may share a key prefix. All but the oldest of them get a free P<n> prefix,
+-- the form 000026 gave existing projects, so the prefix can be made globally unique again.
+DO $$
+DECLARE
+    project record;
+    candidate integer;
+BEGIN
+    FOR project IN
+        SELECT
+            p.id
+        FROM
+            projects p
+        WHERE
+            EXISTS (
+                SELECT
+                    1
+                FROM
+                    projects other
+                WHERE
+                    other.key_prefix = p.key_prefix
+                    AND other.id < p.id)
+        ORDER BY
+            p.id
+    LOOP
+        candidate := project.id;
+        WHILE EXISTS (
+            SELECT
+                1
+            FROM
+                projects
+            WHERE
+                key_prefix = 'P' || candidate)
+        LOOP
+            candidate := candidate + 1;
+        END LOOP;
+        UPDATE
+            projects
+        SET
+            key_prefix = 'P' || candidate
+        WHERE
+            id = project.id;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE projects
+    DROP CONSTRAINT IF EXISTS projects_team_id_key_prefix_key,
+    ADD CONSTRAINT projects_key_prefix_key UNIQUE (key_prefix);
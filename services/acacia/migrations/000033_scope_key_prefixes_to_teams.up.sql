@@ -0,0 +1,5 @@
+-- Key prefixes only need to be unique within a team, so choosing one reveals nothing about other teams.
+-- Issue keys are resolved among the projects of the teams the user belongs to.
+ALTER TABLE projects
+    DROP CONSTRAINT projects_key_prefix_key,
+    ADD CONSTRAINT projects_team_id_key_prefix_key UNIQUE (team_id, key_prefix);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_issues_number;
+
+DROP TRIGGER IF EXISTS issues_project_number_unique ON issues;
+
+DROP FUNCTION IF EXISTS check_issue_number_unique ();
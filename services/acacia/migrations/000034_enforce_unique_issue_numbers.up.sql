@@ -0,0 +1,57 @@
+-- Issue numbers are unique within a project, but issues only reference their column, so a plain unique
+-- constraint cannot cover them. The trigger rejects a number another issue of the project already has,
+-- deleted issues included. It locks the project row like number allocation does, so concurrent writes
+-- cannot both pass the check.
+CREATE FUNCTION check_issue_number_unique ()
+    RETURNS TRIGGER
+    AS $$
+DECLARE
+    issue_project_id bigint;
+BEGIN
+    SELECT
+        project_id INTO issue_project_id
+    FROM
+        project_status_columns
+    WHERE
+        id = NEW.column_id;
+    IF TG_OP = 'UPDATE' AND NEW.number = OLD.number AND issue_project_id = (
+        SELECT
+            project_id
+        FROM
+            project_status_columns
+        WHERE
+            id = OLD.column_id) THEN
+        RETURN NEW;
+    END IF;
+    PERFORM
+        1
+    FROM
+        projects
+    WHERE
+        id = issue_project_id
+    FOR NO KEY UPDATE;
+    IF EXISTS (
+        SELECT
+            1
+        FROM
+            issues i
+            JOIN project_status_columns c ON c.id = i.column_id
+        WHERE
+            c.project_id = issue_project_id
+            AND i.number = NEW.number
+            AND i.id <> NEW.id) THEN
+        RAISE EXCEPTION 'issue number % is already used in project %', NEW.number, issue_project_id
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'issues_project_number_key';
+    END IF;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE TRIGGER issues_project_number_unique
+    BEFORE INSERT OR UPDATE OF number,
+    column_id ON issues
+    FOR EACH ROW
+    EXECUTE FUNCTION check_issue_number_unique ();
+
+CREATE INDEX idx_issues_number ON issues (number);
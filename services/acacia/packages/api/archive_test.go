@@ -0,0 +1,207 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should hide archived projects and issues and keep archived projects read-only", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		kept, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Kept", ColumnID: column.ID})
+		require.NoError(t, err)
+		archived, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Archived", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		post := func(url string, body interface{}) *http.Response {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			resp, err := client.Post(setup.Server.GetURL()+url, "application/json", bytes.NewBuffer(payload))
+			require.NoError(t, err)
+			return resp
+		}
+		details := func(query string) schemas.GetProjectDetailsResponse {
+			resp, err := client.Get(fmt.Sprintf("%s/projects/%d/details%s", setup.Server.GetURL(), project.ID, query))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var details schemas.GetProjectDetailsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+			return details
+		}
+		projects := func(query string) []db.Project {
+			resp, err := client.Get(setup.Server.GetURL() + "/projects" + query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var projects []db.Project
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&projects))
+			return projects
+		}
+
+		// An archived issue leaves the default listing and the WIP count and is recorded in its history
+		resp := post(fmt.Sprintf("/issues/%d/archive", archived.ID), nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var archivedIssue db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&archivedIssue))
+		assert.True(t, archivedIssue.ArchivedAt.Valid)
+
+		listed := details("")
+		require.Len(t, listed.Issues, 1)
+		assert.Equal(t, kept.ID, listed.Issues[0].ID)
+		require.Len(t, listed.Columns, 1)
+		assert.Equal(t, int64(1), listed.Columns[0].IssueCount)
+		assert.Len(t, details("?include_archived=true").Issues, 2)
+
+		activity, err := setup.Queries.GetIssueActivity(ctx, archived.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, activity)
+		assert.Equal(t, schemas.IssueActivityArchived, activity[len(activity)-1].Action)
+
+		// An archived project is hidden from the default project list and rejects changes
+		resp = post(fmt.Sprintf("/projects/%d/archive", project.ID), nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		assert.Empty(t, projects(""))
+		assert.Len(t, projects("?include_archived=true"), 1)
+
+		resp = post("/issues", map[string]interface{}{"name": "New", "column_id": column.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = post(fmt.Sprintf("/issues/%d/move", kept.ID), map[string]interface{}{"column_id": column.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = post(fmt.Sprintf("/issues/%d/unarchive", archived.ID), nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// Reads still work
+		resp, err = client.Get(fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), kept.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// Unarchiving the project makes it writable again
+		resp = post(fmt.Sprintf("/projects/%d/unarchive", project.ID), nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, projects(""), 1)
+
+		resp = post(fmt.Sprintf("/issues/%d/unarchive", archived.ID), nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Len(t, details("").Issues, 2)
+	})
+
+	t.Run("should not unarchive an issue into a column at its WIP limit", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		_, err = setup.Queries.UpdateProject(ctx, db.UpdateProjectParams{
+			ID:           project.ID,
+			Name:         project.Name,
+			WipLimitMode: null.StringFrom(schemas.WIPLimitModeHard),
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Doing",
+			WipLimit:  null.IntFrom(1),
+		})
+		require.NoError(t, err)
+		archived, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Archived", ColumnID: column.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.ArchiveIssue(ctx, archived.ID)
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Taking the slot", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		unarchive := func() *http.Response {
+			resp, err := client.Post(fmt.Sprintf("%s/issues/%d/unarchive", setup.Server.GetURL(), archived.ID), "application/json", nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		assert.Equal(t, http.StatusConflict, unarchive().StatusCode)
+		issue, err := setup.Queries.GetIssueByID(ctx, archived.ID)
+		require.NoError(t, err)
+		assert.True(t, issue.ArchivedAt.Valid)
+
+		// Soft limits unarchive the issue with a warning
+		_, err = setup.Queries.UpdateProject(ctx, db.UpdateProjectParams{
+			ID:           project.ID,
+			Name:         project.Name,
+			WipLimitMode: null.StringFrom(schemas.WIPLimitModeSoft),
+		})
+		require.NoError(t, err)
+		resp := unarchive()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var unarchived schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&unarchived))
+		assert.False(t, unarchived.ArchivedAt.Valid)
+		require.NotNil(t, unarchived.WIPWarning)
+		assert.Equal(t, int64(2), unarchived.WIPWarning.IssueCount)
+	})
+
+	t.Run("should not let outsiders archive a project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+
+		outsider := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		resp, err := outsider.Post(fmt.Sprintf("%s/projects/%d/archive", setup.Server.GetURL(), project.ID), "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
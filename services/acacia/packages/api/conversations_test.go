@@ -0,0 +1,160 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFakeConversation sets up a user, team, project and a conversation using the scripted provider
+func createFakeConversation(t *testing.T, ctx context.Context, setup *testutils.IntegrationTestSetup) (*http.Client, db.Project, schemas.ConversationResponse) {
+	client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+	user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+	require.NoError(t, err)
+	teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+	project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+		Name:      "Apollo",
+		TeamID:    teamID,
+		KeyPrefix: "APO",
+	})
+	require.NoError(t, err)
+
+	testutils.CreateTeamLLMAPIKey(t, setup, client, teamID, testutils.FakeLLMProviderName)
+
+	reqBody, err := json.Marshal(schemas.CreateConversationInput{
+		Provider:       testutils.FakeLLMProviderName,
+		Model:          "fake-model",
+		InitialMessage: "Hello",
+		ProjectID:      project.ID,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Post(fmt.Sprintf("%s/conversations", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var conversation schemas.ConversationResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&conversation))
+
+	return client, project, conversation
+}
+
+// sendConversationMessage posts a message and returns the raw server-sent event stream
+func sendConversationMessage(t *testing.T, setup *testutils.IntegrationTestSetup, client *http.Client, conversationID int64, content string) string {
+	reqBody, err := json.Marshal(schemas.SendMessageInput{
+		ConversationID: conversationID,
+		Content:        content,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.Post(fmt.Sprintf("%s/conversations/messages", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestConversationMessages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should stream the reply and persist both messages", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, _, conversation := createFakeConversation(t, ctx, setup)
+		setup.LLM.Script(testutils.FakeLLMTurn{TextDeltas: []string{"Hello", " there"}})
+
+		events := sendConversationMessage(t, setup, client, conversation.ID, "Hi!")
+		assert.Contains(t, events, "event: message\ndata: Hello\n\n")
+		assert.Contains(t, events, "event: message\ndata:  there\n\n")
+		assert.Contains(t, events, "event: done")
+
+		requests := setup.LLM.Requests()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "test-api-key-fake", requests[0].APIKey)
+		assert.Equal(t, "fake-model", requests[0].Model)
+		assert.True(t, requests[0].WithTools)
+		require.NotEmpty(t, requests[0].Messages)
+		assert.Equal(t, "Hi!", requests[0].Messages[len(requests[0].Messages)-1].Content)
+
+		// The assistant message is saved after the final chunk is forwarded
+		require.Eventually(t, func() bool {
+			messages, err := setup.Queries.GetMessagesByConversationID(ctx, conversation.ID)
+			return err == nil && len(messages) == 2 && messages[1].Content == "Hello there"
+		}, 2*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("should execute real tools with the user's context", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, _, conversation := createFakeConversation(t, ctx, setup)
+		setup.LLM.Script(
+			testutils.FakeLLMTurn{ToolCalls: []testutils.FakeToolCall{{Name: "get_user_projects", Args: map[string]any{}}}},
+			testutils.FakeLLMTurn{TextDeltas: []string{"You have one project."}},
+		)
+
+		events := sendConversationMessage(t, setup, client, conversation.ID, "Which projects do I have?")
+		assert.Contains(t, events, "data: You have one project.")
+		assert.NotContains(t, events, "event: error")
+
+		requests := setup.LLM.Requests()
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0].ToolResults, 1)
+		require.NoError(t, requests[0].ToolResults[0].Err)
+		assert.Contains(t, requests[0].ToolResults[0].Result, "Apollo")
+	})
+
+	t.Run("should not let tools read projects of other teams", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, _, conversation := createFakeConversation(t, ctx, setup)
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		team2ID := testutils.CreateTeamAndAddUser(t, ctx, setup, user2.ID, "Team 2")
+		otherProject, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Secret",
+			TeamID:    team2ID,
+			KeyPrefix: "SEC",
+		})
+		require.NoError(t, err)
+
+		setup.LLM.Script(testutils.FakeLLMTurn{ToolCalls: []testutils.FakeToolCall{{
+			Name: "get_project_details",
+			Args: map[string]any{"project_id": otherProject.ID},
+		}}})
+
+		events := sendConversationMessage(t, setup, client, conversation.ID, "Show me the secret project")
+		assert.Contains(t, events, "event: error")
+		assert.NotContains(t, events, "Secret")
+
+		requests := setup.LLM.Requests()
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0].ToolResults, 1)
+		assert.Error(t, requests[0].ToolResults[0].Err)
+	})
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type CustomFieldsController struct {
+	queries      *db.Queries
+	logger       *logrus.Logger
+	validator    *validator.Validate
+	fieldService *services.CustomFieldService
+}
+
+func NewCustomFieldsController(queries *db.Queries, logger *logrus.Logger, database *sql.DB) *CustomFieldsController {
+	return &CustomFieldsController{
+		queries:      queries,
+		logger:       logger,
+		validator:    validator.New(),
+		fieldService: services.NewCustomFieldService(queries, database),
+	}
+}
+
+// GetCustomFields returns the project's custom fields ordered by name
+func (c *CustomFieldsController) GetCustomFields(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	fields, err := c.fieldService.List(r.Context(), projectID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get custom fields")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(fields)
+	return nil
+}
+
+// CreateCustomField defines a custom field on the project's issues
+func (c *CustomFieldsController) CreateCustomField(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CreateCustomFieldInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleCustomFieldValidationErrors(err), http.StatusBadRequest)
+	}
+
+	field, err := c.fieldService.Create(r.Context(), projectID, req)
+	if err != nil {
+		if err := customFieldError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to create custom field")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(field)
+	return nil
+}
+
+// UpdateCustomField renames one of the project's custom fields and replaces its options
+func (c *CustomFieldsController) UpdateCustomField(w http.ResponseWriter, r *http.Request) error {
+	projectID, fieldID, err := parseCustomFieldURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	var req schemas.UpdateCustomFieldInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleCustomFieldValidationErrors(err), http.StatusBadRequest)
+	}
+
+	field, err := c.fieldService.Update(r.Context(), projectID, fieldID, req)
+	if err != nil {
+		if err := customFieldError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to update custom field")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(field)
+	return nil
+}
+
+// DeleteCustomField deletes one of the project's custom fields together with its values on every issue
+func (c *CustomFieldsController) DeleteCustomField(w http.ResponseWriter, r *http.Request) error {
+	projectID, fieldID, err := parseCustomFieldURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	if err := c.fieldService.Delete(r.Context(), projectID, fieldID); err != nil {
+		if err := customFieldError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to delete custom field")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func parseCustomFieldURLParams(r *http.Request) (int64, int64, error) {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+	fieldID, err := strconv.ParseInt(chi.URLParam(r, "field_id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid custom field ID"), http.StatusBadRequest)
+	}
+	return projectID, fieldID, nil
+}
+
+// customFieldError maps the custom field service's errors to their HTTP error
+func customFieldError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrCustomFieldNotFound):
+		return httperr.WithStatus(errors.New("Custom field not found"), http.StatusNotFound)
+	case errors.Is(err, services.ErrCustomFieldOptionsRequired):
+		return httperr.WithStatus(errors.New("Select and multi_select fields need at least one option"), http.StatusBadRequest)
+	case errors.Is(err, services.ErrCustomFieldOptionsNotAllowed):
+		return httperr.WithStatus(errors.New("Only select and multi_select fields have options"), http.StatusBadRequest)
+	case isUniqueViolation(err):
+		return httperr.WithStatus(errors.New("A custom field with this name already exists"), http.StatusConflict)
+	}
+	return nil
+}
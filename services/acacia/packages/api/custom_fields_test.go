@@ -0,0 +1,223 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomFields(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should define fields, validate and filter issue values", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "outsider@example.com", "Outsider", "password123")
+		outsider, err := setup.Queries.GetUserByEmail(ctx, "outsider@example.com")
+		require.NoError(t, err)
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		fieldsURL := fmt.Sprintf("%s/projects/%d/custom-fields", setup.Server.GetURL(), project.ID)
+
+		createField := func(input schemas.CreateCustomFieldInput) (*http.Response, db.CustomField) {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := client.Post(fieldsURL, "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var field db.CustomField
+			if resp.StatusCode == http.StatusCreated {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&field))
+			}
+			return resp, field
+		}
+
+		resp, severity := createField(schemas.CreateCustomFieldInput{Name: "Severity", FieldType: schemas.CustomFieldSelect, Options: []string{"S1", "S2", "S3"}})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp, points := createField(schemas.CreateCustomFieldInput{Name: "Points", FieldType: schemas.CustomFieldNumber})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp, platforms := createField(schemas.CreateCustomFieldInput{Name: "Platforms", FieldType: schemas.CustomFieldMultiSelect, Options: []string{"ios", "android", "web"}})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp, reviewer := createField(schemas.CreateCustomFieldInput{Name: "Reviewer", FieldType: schemas.CustomFieldUser})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// Names are unique per project and only select fields have options
+		resp, _ = createField(schemas.CreateCustomFieldInput{Name: "Points", FieldType: schemas.CustomFieldText})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		resp, _ = createField(schemas.CreateCustomFieldInput{Name: "Team", FieldType: schemas.CustomFieldSelect})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = createField(schemas.CreateCustomFieldInput{Name: "Notes", FieldType: schemas.CustomFieldText, Options: []string{"a"}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp, _ = createField(schemas.CreateCustomFieldInput{Name: "Color", FieldType: "colour"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		createIssue := func(name string, customFields string) (*http.Response, schemas.IssueWithWIPWarning) {
+			body := fmt.Sprintf(`{"name": %q, "description": "", "column_id": %d, "custom_fields": %s}`, name, column.ID, customFields)
+			resp, err := client.Post(setup.Server.GetURL()+"/issues", "application/json", bytes.NewBufferString(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var issue schemas.IssueWithWIPWarning
+			if resp.StatusCode == http.StatusCreated {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+			}
+			return resp, issue
+		}
+
+		resp, crash := createIssue("Crash", fmt.Sprintf(`{"%d": "S1", "%d": 3, "%d": ["ios", "web"], "%d": %d}`,
+			severity.ID, points.ID, platforms.ID, reviewer.ID, user.ID))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Len(t, crash.CustomFields, 4)
+		assert.Equal(t, "Platforms", crash.CustomFields[0].Name)
+		assert.Equal(t, []interface{}{"ios", "web"}, crash.CustomFields[0].Value)
+		assert.Equal(t, float64(3), crash.CustomFields[1].Value)
+		assert.Equal(t, "S1", crash.CustomFields[3].Value)
+
+		resp, _ = createIssue("Typo", fmt.Sprintf(`{"%d": "S2"}`, severity.ID))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// Values are checked against the field's type
+		invalid := []string{
+			fmt.Sprintf(`{"%d": "S9"}`, severity.ID),
+			fmt.Sprintf(`{"%d": "three"}`, points.ID),
+			fmt.Sprintf(`{"%d": ["desktop"]}`, platforms.ID),
+			fmt.Sprintf(`{"%d": %d}`, reviewer.ID, outsider.ID),
+			`{"999999": "x"}`,
+		}
+		for _, values := range invalid {
+			resp, _ = createIssue("Invalid", values)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, values)
+		}
+
+		// Board issues can be filtered by a field's value
+		filterIssues := func(fieldID int64, value string) (*http.Response, []string) {
+			resp, err := client.Get(fmt.Sprintf("%s/projects/%d/details?custom_field_id=%d&custom_field_value=%s",
+				setup.Server.GetURL(), project.ID, fieldID, value))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var names []string
+			if resp.StatusCode == http.StatusOK {
+				var details schemas.GetProjectDetailsResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+				for _, issue := range details.Issues {
+					names = append(names, issue.Name)
+				}
+			}
+			return resp, names
+		}
+
+		resp, names := filterIssues(severity.ID, "S2")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Typo"}, names)
+		resp, names = filterIssues(platforms.ID, "web")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Crash"}, names)
+		resp, names = filterIssues(points.ID, "3")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Crash"}, names)
+		resp, _ = filterIssues(points.ID, "many")
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Updates change only the given fields, null clears one, and changes are recorded as activity
+		body := fmt.Sprintf(`{"id": %d, "name": "Crash", "column_id": %d, "custom_fields": {"%d": null, "%d": 5}}`,
+			crash.ID, column.ID, severity.ID, points.ID)
+		req, err := http.NewRequest(http.MethodPut, setup.Server.GetURL()+"/issues", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var updated schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+		require.Len(t, updated.CustomFields, 3)
+		assert.Equal(t, float64(5), updated.CustomFields[1].Value)
+
+		activity, err := setup.Queries.GetIssueActivity(ctx, crash.ID)
+		require.NoError(t, err)
+		var fields []string
+		for _, entry := range activity {
+			if entry.Field.Valid {
+				fields = append(fields, entry.Field.String)
+			}
+		}
+		assert.Contains(t, fields, "custom:Points")
+		assert.Contains(t, fields, "custom:Severity")
+
+		// Removing an option removes it from the values using it
+		body = `{"name": "Platforms", "options": ["ios", "android"]}`
+		req, err = http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", fieldsURL, platforms.ID), bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, names = filterIssues(platforms.ID, "ios")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"Crash"}, names)
+		resp, names = filterIssues(platforms.ID, "web")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, names)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", fieldsURL, points.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err = client.Get(fieldsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var fieldList []db.CustomField
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&fieldList))
+		assert.Len(t, fieldList, 3)
+	})
+
+	t.Run("should return 403 for users outside the team", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "owner@example.com", "Owner", "password123")
+		owner, err := setup.Queries.GetUserByEmail(ctx, "owner@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, owner.ID, "Team 1")
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+
+		outsider := testutils.CreateAuthenticatedClient(t, setup, "outsider@example.com", "Outsider", "password123")
+
+		resp, err := outsider.Get(fmt.Sprintf("%s/projects/%d/custom-fields", setup.Server.GetURL(), project.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// etag is the entity tag of a resource last changed at updatedAt, its timestamp in microseconds as stored
+func etag(updatedAt time.Time) string {
+	return `"` + strconv.FormatInt(updatedAt.UnixMicro(), 10) + `"`
+}
+
+func setETag(w http.ResponseWriter, updatedAt time.Time) {
+	w.Header().Set("ETag", etag(updatedAt))
+}
+
+// ifMatch returns the updated_at values the request's If-Match header accepts, one per listed tag;
+// the resource must have one of them. It is nil when the header is absent or "*", so the change applies
+// unconditionally. Tags this API cannot have issued, such as weak ones, are skipped, so a header with
+// only such tags gives an empty list and the precondition fails.
+func ifMatch(r *http.Request) []time.Time {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	accepted := []time.Time{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		micros, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		accepted = append(accepted, time.UnixMicro(micros).UTC())
+	}
+	return accepted
+}
+
+// writePreconditionFailed answers a change whose If-Match no longer matches with the current
+// representation of the resource and its ETag, so the client can merge and retry
+func writePreconditionFailed(w http.ResponseWriter, current interface{}, updatedAt time.Time) {
+	setETag(w, updatedAt)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(current)
+}
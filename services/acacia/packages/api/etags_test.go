@@ -0,0 +1,154 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should refuse changes to issues, projects and columns changed since the client read them", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+		})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		get := func(url string) string {
+			resp, err := client.Get(setup.Server.GetURL() + url)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			tag := resp.Header.Get("ETag")
+			require.NotEmpty(t, tag)
+			return tag
+		}
+		send := func(method string, url string, tag string, body interface{}) *http.Response {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(method, setup.Server.GetURL()+url, bytes.NewBuffer(payload))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			if tag != "" {
+				req.Header.Set("If-Match", tag)
+			}
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			return resp
+		}
+
+		// Issues: the first of two writers holding the same ETag wins, the second gets the current issue
+		issueURL := fmt.Sprintf("/issues/%d", issue.ID)
+		tag := get(issueURL)
+		assert.Equal(t, tag, get(fmt.Sprintf("/issues/key/PRJ-%d", issue.Number)))
+
+		resp := send(http.MethodPut, "/issues", tag, schemas.UpdateIssueInput{ID: issue.ID, Name: "First", ColumnId: column.ID})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		newTag := resp.Header.Get("ETag")
+		assert.NotEqual(t, tag, newTag)
+		assert.Equal(t, newTag, get(issueURL))
+
+		resp = send(http.MethodPut, "/issues", tag, schemas.UpdateIssueInput{ID: issue.ID, Name: "Second", ColumnId: column.ID})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		assert.Equal(t, newTag, resp.Header.Get("ETag"))
+		var current schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&current))
+		assert.Equal(t, "First", current.Name)
+
+		// Assignees, labels and links are part of the issue, so changing them changes its ETag
+		resp = send(http.MethodPost, issueURL+"/assignees", "", schemas.AssignIssueInput{UserID: user.ID})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assignedTag := get(issueURL)
+		assert.NotEqual(t, newTag, assignedTag)
+
+		other, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Other", ColumnID: column.ID})
+		require.NoError(t, err)
+		otherTag := get(fmt.Sprintf("/issues/%d", other.ID))
+		resp = send(http.MethodPost, fmt.Sprintf("/issues/%d/links", other.ID), "", schemas.CreateIssueLinkInput{LinkedIssueID: issue.ID, LinkType: schemas.IssueLinkRelates})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.NotEqual(t, otherTag, get(fmt.Sprintf("/issues/%d", other.ID)))
+		linkedTag := get(issueURL)
+		assert.NotEqual(t, assignedTag, linkedTag)
+
+		// Any of the listed tags may match, weak ones never do
+		resp = send(http.MethodDelete, issueURL, tag, nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		resp = send(http.MethodDelete, issueURL, `W/`+linkedTag, nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		resp = send(http.MethodDelete, issueURL, tag+`, W/`+linkedTag+`, `+linkedTag, nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		// Projects
+		projectURL := fmt.Sprintf("/projects/%d", project.ID)
+		tag = get(projectURL)
+		resp = send(http.MethodPut, projectURL, tag, schemas.UpdateProjectInput{Name: "Renamed"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = send(http.MethodPut, projectURL, tag, schemas.UpdateProjectInput{Name: "Stale"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		var currentProject db.Project
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&currentProject))
+		assert.Equal(t, "Renamed", currentProject.Name)
+
+		// Columns; requests without If-Match still apply unconditionally
+		columnURL := fmt.Sprintf("/project-columns/%d", column.ID)
+		tag = get(columnURL)
+		resp = send(http.MethodPut, columnURL, "", schemas.UpdateProjectStatusColumnInput{Name: "Backlog"})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = send(http.MethodDelete, columnURL, tag, nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+		var currentColumn db.ProjectStatusColumn
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&currentColumn))
+		assert.Equal(t, "Backlog", currentColumn.Name)
+
+		_, err = setup.Queries.GetProjectStatusColumnByID(ctx, column.ID)
+		require.NoError(t, err)
+
+		resp = send(http.MethodDelete, columnURL, "*", nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	})
+}
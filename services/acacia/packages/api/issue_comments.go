@@ -0,0 +1,232 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type IssueCommentsController struct {
+	queries        *db.Queries
+	logger         *logrus.Logger
+	storage        CommentStorage
+	validator      *validator.Validate
+	commentService *services.IssueCommentService
+}
+
+// CommentStorage stores serialized comment bodies next to the issue's serialized description
+type CommentStorage interface {
+	UploadCommentBody(ctx context.Context, issueID int64, commentID int64, content string) error
+	GetCommentBody(ctx context.Context, issueID int64, commentID int64) (string, error)
+	DeleteCommentBody(ctx context.Context, issueID int64, commentID int64) error
+}
+
+func NewIssueCommentsController(queries *db.Queries, logger *logrus.Logger, storage CommentStorage, database *sql.DB) *IssueCommentsController {
+	return &IssueCommentsController{
+		queries:        queries,
+		logger:         logger,
+		storage:        storage,
+		validator:      validator.New(),
+		commentService: services.NewIssueCommentService(queries, database),
+	}
+}
+
+// GetIssueComments returns the issue's comments oldest first with their replies nested
+func (c *IssueCommentsController) GetIssueComments(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	comments, err := c.commentService.List(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue comments")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	for i := range comments {
+		c.loadSerializedBody(r.Context(), &comments[i])
+		for j := range comments[i].Replies {
+			c.loadSerializedBody(r.Context(), &comments[i].Replies[j])
+		}
+	}
+
+	json.NewEncoder(w).Encode(comments)
+	return nil
+}
+
+// CreateIssueComment adds a comment, or a reply when parent_id is set, written by the current user
+func (c *IssueCommentsController) CreateIssueComment(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CreateCommentInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleCommentValidationErrors(err), http.StatusBadRequest)
+	}
+
+	comment, err := c.commentService.Create(r.Context(), issueID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCommentParentNotFound):
+			return httperr.WithStatus(errors.New("Parent comment not found on this issue"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrCommentReplyToReply):
+			return httperr.WithStatus(errors.New("Replies cannot be replied to"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to create comment")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	if comment.HasSerializedBody {
+		if err := c.storage.UploadCommentBody(r.Context(), issueID, comment.ID, *req.BodySerialized); err != nil {
+			c.queries.DeleteIssueComment(r.Context(), comment.ID)
+			c.logger.WithError(err).Error("Failed to upload comment body to S3, rolled back comment creation")
+			return httperr.WithStatus(errors.New("Failed to save comment body"), http.StatusInternalServerError)
+		}
+		comment.BodySerialized = req.BodySerialized
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(comment)
+	return nil
+}
+
+// UpdateIssueComment replaces the body of one of the current user's comments
+func (c *IssueCommentsController) UpdateIssueComment(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, commentID, err := parseCommentURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	var req schemas.UpdateCommentInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleCommentValidationErrors(err), http.StatusBadRequest)
+	}
+
+	hasSerializedBody := req.BodySerialized != nil && *req.BodySerialized != ""
+	comment, hadSerializedBody, err := c.commentService.Update(r.Context(), issueID, commentID, userID, req.Body, hasSerializedBody)
+	if err != nil {
+		if err := commentAccessError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to update comment")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	if hasSerializedBody {
+		if err := c.storage.UploadCommentBody(r.Context(), issueID, commentID, *req.BodySerialized); err != nil {
+			c.logger.WithError(err).Error("Failed to upload comment body to S3 during update")
+			return httperr.WithStatus(errors.New("Failed to save comment body"), http.StatusInternalServerError)
+		}
+		comment.BodySerialized = req.BodySerialized
+	} else if hadSerializedBody {
+		// The comment is updated either way; a leftover body is only logged
+		if err := c.storage.DeleteCommentBody(r.Context(), issueID, commentID); err != nil {
+			c.logger.WithError(err).Warn("Failed to delete comment body from S3")
+		}
+	}
+
+	json.NewEncoder(w).Encode(comment)
+	return nil
+}
+
+// DeleteIssueComment deletes one of the current user's comments together with its replies
+func (c *IssueCommentsController) DeleteIssueComment(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, commentID, err := parseCommentURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	serialized, err := c.commentService.Delete(r.Context(), issueID, commentID, userID)
+	if err != nil {
+		if err := commentAccessError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to delete comment")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	// The comments are gone either way; a leftover body is only logged
+	for _, id := range serialized {
+		if err := c.storage.DeleteCommentBody(r.Context(), issueID, id); err != nil {
+			c.logger.WithError(err).Warn("Failed to delete comment body from S3")
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// loadSerializedBody fetches the comment's rich-text body; a failed fetch leaves it empty
+func (c *IssueCommentsController) loadSerializedBody(ctx context.Context, comment *schemas.IssueComment) {
+	if !comment.HasSerializedBody {
+		return
+	}
+	serialized, err := c.storage.GetCommentBody(ctx, comment.IssueID, comment.ID)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to fetch serialized comment body from S3")
+		return
+	}
+	comment.BodySerialized = &serialized
+}
+
+func parseCommentURLParams(r *http.Request) (int64, int64, error) {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "comment_id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid comment ID"), http.StatusBadRequest)
+	}
+	return issueID, commentID, nil
+}
+
+// commentAccessError maps a missing comment or a comment written by someone else to its HTTP error
+func commentAccessError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrCommentNotFound):
+		return httperr.WithStatus(errors.New("Comment not found"), http.StatusNotFound)
+	case errors.Is(err, services.ErrCommentNotAuthor):
+		return httperr.WithStatus(errors.New("Only the author can change a comment"), http.StatusForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueComments(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should thread replies, resolve mentions and restrict edits to the author", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		alice := testutils.CreateAuthenticatedClient(t, setup, "alice@example.com", "Alice", "password123")
+		aliceUser, err := setup.Queries.GetUserByEmail(ctx, "alice@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, aliceUser.ID, "Team 1")
+
+		bob := testutils.CreateAuthenticatedClient(t, setup, "bob@example.com", "Bob", "password123")
+		bobUser, err := setup.Queries.GetUserByEmail(ctx, "bob@example.com")
+		require.NoError(t, err)
+		_, err = setup.Queries.AddTeamMember(ctx, db.AddTeamMemberParams{TeamID: teamID, UserID: bobUser.ID})
+		require.NoError(t, err)
+
+		// Carol exists but is not on the team, so mentioning her does nothing
+		_ = testutils.CreateAuthenticatedClient(t, setup, "carol@example.com", "Carol", "password123")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Discuss me", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		commentsURL := fmt.Sprintf("%s/issues/%d/comments", setup.Server.GetURL(), issue.ID)
+
+		post := func(client *http.Client, input schemas.CreateCommentInput) *http.Response {
+			body, _ := json.Marshal(input)
+			resp, err := client.Post(commentsURL, "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp := post(alice, schemas.CreateCommentInput{Body: "Can you take a look, @bob? cc @carol"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var comment schemas.IssueComment
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&comment))
+		require.NotNil(t, comment.Author)
+		assert.Equal(t, aliceUser.ID, comment.Author.ID)
+		require.Len(t, comment.Mentions, 1)
+		assert.Equal(t, bobUser.ID, comment.Mentions[0].ID)
+
+		resp = post(bob, schemas.CreateCommentInput{Body: "On it, @alice@example.com", ParentID: null.IntFrom(comment.ID)})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var reply schemas.IssueComment
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+		require.Len(t, reply.Mentions, 1)
+		assert.Equal(t, aliceUser.ID, reply.Mentions[0].ID)
+
+		// Only one level of threading
+		resp = post(alice, schemas.CreateCommentInput{Body: "Thanks", ParentID: null.IntFrom(reply.ID)})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = bob.Get(commentsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var comments []schemas.IssueComment
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&comments))
+		require.Len(t, comments, 1)
+		require.Len(t, comments[0].Replies, 1)
+		assert.Equal(t, reply.ID, comments[0].Replies[0].ID)
+
+		put := func(client *http.Client, commentID int64, input schemas.UpdateCommentInput) *http.Response {
+			body, _ := json.Marshal(input)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", commentsURL, commentID), bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp = put(bob, comment.ID, schemas.UpdateCommentInput{Body: "Hijacked"})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = put(alice, comment.ID, schemas.UpdateCommentInput{Body: "Never mind"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var edited schemas.IssueComment
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&edited))
+		assert.Equal(t, "Never mind", edited.Body)
+		assert.Empty(t, edited.Mentions)
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", commentsURL, comment.ID), nil)
+		require.NoError(t, err)
+		resp, err = alice.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		// Replies go with their comment
+		resp, err = alice.Get(commentsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&comments))
+		assert.Empty(t, comments)
+	})
+
+	t.Run("should return 403 for users outside the team", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "owner@example.com", "Owner", "password123")
+		owner, err := setup.Queries.GetUserByEmail(ctx, "owner@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, owner.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Private", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		outsider := testutils.CreateAuthenticatedClient(t, setup, "outsider@example.com", "Outsider", "password123")
+		resp, err := outsider.Get(fmt.Sprintf("%s/issues/%d/comments", setup.Server.GetURL(), issue.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/llm"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type IssueDraftsController struct {
+	queries          *db.Queries
+	logger           *logrus.Logger
+	validator        *validator.Validate
+	breakdownService *services.IssueBreakdownService
+}
+
+func NewIssueDraftsController(
+	queries *db.Queries,
+	logger *logrus.Logger,
+	database *sql.DB,
+	providers *services.TeamProviderResolver,
+) *IssueDraftsController {
+	return &IssueDraftsController{
+		queries:          queries,
+		logger:           logger,
+		validator:        validator.New(),
+		breakdownService: services.NewIssueBreakdownService(queries, database, providers, logger),
+	}
+}
+
+// GenerateIssueDrafts asks the team's LLM to break free text down into draft issues.
+// Drafts are only returned, not saved.
+func (c *IssueDraftsController) GenerateIssueDrafts(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.GenerateIssueDraftsInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleIssueDraftValidationErrors(err), http.StatusBadRequest)
+	}
+
+	drafts, err := c.breakdownService.GenerateDrafts(r.Context(), projectID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrProjectHasNoColumns):
+			return httperr.WithStatus(errors.New("Project has no columns"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrAPIKeyNotFound):
+			return httperr.WithStatus(errors.New("No API key configured for this provider"), http.StatusBadRequest)
+		case errors.Is(err, llm.ErrProviderNotSupported), errors.Is(err, llm.ErrStructuredOutputNotSupported):
+			return httperr.WithStatus(errors.New("Provider does not support issue breakdown"), http.StatusBadRequest)
+		case errors.Is(err, llm.ErrInvalidAPIKey):
+			return httperr.WithStatus(errors.New("The team's API key was rejected by the provider"), http.StatusBadGateway)
+		case errors.Is(err, llm.ErrRateLimitExceeded):
+			return httperr.WithStatus(errors.New("Provider rate limit exceeded"), http.StatusTooManyRequests)
+		case errors.Is(err, services.ErrInvalidModelResponse):
+			return httperr.WithStatus(errors.New("The model returned an invalid response"), http.StatusBadGateway)
+		}
+		c.logger.WithError(err).Error("Failed to generate issue drafts")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(schemas.IssueDraftsResponse{Drafts: drafts})
+	return nil
+}
+
+// CommitIssueDrafts creates the accepted drafts as issues in one transaction
+func (c *IssueDraftsController) CommitIssueDrafts(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CommitIssueDraftsInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleIssueDraftValidationErrors(err), http.StatusBadRequest)
+	}
+
+	issues, err := c.breakdownService.CommitDrafts(r.Context(), projectID, userID, req.Drafts)
+	if err != nil {
+		if errors.Is(err, services.ErrColumnNotInProject) {
+			return httperr.WithStatus(errors.New("Column does not belong to this project"), http.StatusBadRequest)
+		}
+		var violation *services.WorkflowViolation
+		if errors.As(err, &violation) {
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		}
+		if errors.Is(err, services.ErrWIPLimitExceeded) {
+			return httperr.WithStatus(errors.New("Drafts would take a column over its WIP limit"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to commit issue drafts")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(issues)
+	return nil
+}
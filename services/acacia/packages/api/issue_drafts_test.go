@@ -0,0 +1,210 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueDrafts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should create all committed drafts", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		body, _ := json.Marshal(schemas.CommitIssueDraftsInput{
+			Drafts: []schemas.IssueDraftInput{
+				{Name: "Design login page", Description: "Mockups for the login page", ColumnID: column.ID},
+				{Name: "Implement login API", ColumnID: column.ID},
+			},
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/issue-drafts/commit", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var issues []db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issues))
+		require.Len(t, issues, 2)
+		assert.Equal(t, "Design login page", issues[0].Name)
+		assert.Equal(t, column.ID, issues[1].ColumnID)
+	})
+
+	t.Run("should reject drafts targeting a column of another project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Project A",
+			TeamID:    teamID,
+			KeyPrefix: "PA",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		otherProject, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Project B",
+			TeamID:    teamID,
+			KeyPrefix: "PB",
+		})
+		require.NoError(t, err)
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(otherProject.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		body, _ := json.Marshal(schemas.CommitIssueDraftsInput{
+			Drafts: []schemas.IssueDraftInput{{Name: "Misplaced issue", ColumnID: otherColumn.ID}},
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/issue-drafts/commit", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		issues, err := setup.Queries.GetIssuesByColumnId(ctx, otherColumn.ID)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+
+	t.Run("should return 400 when team has no API key for the provider", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		body, _ := json.Marshal(schemas.GenerateIssueDraftsInput{
+			Text:     "Build a login page with email and password",
+			Provider: "openai",
+			Model:    "gpt-4o-mini",
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/issue-drafts", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should map generated drafts to project columns", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+		testutils.CreateTeamLLMAPIKey(t, setup, client, teamID, testutils.FakeLLMProviderName)
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		doing, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Doing",
+		})
+		require.NoError(t, err)
+
+		setup.LLM.ScriptStructured(`{"issues": [
+			{"title": "Design login page", "description": "Mockups", "column": "doing"},
+			{"title": "Implement login API", "description": "Endpoint", "column": "Unknown"}
+		]}`)
+
+		body, _ := json.Marshal(schemas.GenerateIssueDraftsInput{
+			Text:     "Build a login page with email and password",
+			Provider: testutils.FakeLLMProviderName,
+			Model:    "fake-model",
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/issue-drafts", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result schemas.IssueDraftsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Drafts, 2)
+		assert.Equal(t, doing.ID, result.Drafts[0].ColumnID)
+		assert.Equal(t, todo.ID, result.Drafts[1].ColumnID)
+
+		requests := setup.LLM.Requests()
+		require.Len(t, requests, 1)
+		require.NotNil(t, requests[0].Schema)
+		assert.Equal(t, "Build a login page with email and password", requests[0].Messages[len(requests[0].Messages)-1].Content)
+
+		issues, err := setup.Queries.GetIssuesByColumnId(ctx, todo.ID)
+		require.NoError(t, err)
+		assert.Empty(t, issues)
+	})
+}
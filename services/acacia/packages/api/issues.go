@@ -7,21 +7,37 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
+	"acacia/packages/auth"
 	"acacia/packages/db"
 	"acacia/packages/httperr"
 	"acacia/packages/schemas"
+	"acacia/packages/services"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
 	"github.com/guregu/null"
 	"github.com/sirupsen/logrus"
 )
 
 type IssuesController struct {
-	queries *db.Queries
-	logger  *logrus.Logger
-	storage S3Storage
+	queries          *db.Queries
+	logger           *logrus.Logger
+	storage          S3Storage
+	validator        *validator.Validate
+	issueService     *services.IssueService
+	assigneeService  *services.IssueAssigneeService
+	labelService     *services.IssueLabelService
+	searchService    *services.IssueSearchService
+	duplicateService *services.IssueDuplicateService
+	activityService  *services.IssueActivityService
+	hierarchyService *services.IssueHierarchyService
+	linkService      *services.IssueLinkService
+	keyService       *services.IssueKeyService
+	fieldService     *services.CustomFieldService
 }
 
 type S3Storage interface {
@@ -29,11 +45,28 @@ type S3Storage interface {
 	GetDescription(ctx context.Context, issueID int64) (string, error)
 }
 
-func NewIssuesController(queries *db.Queries, logger *logrus.Logger, storage S3Storage) *IssuesController {
+func NewIssuesController(
+	queries *db.Queries,
+	logger *logrus.Logger,
+	storage S3Storage,
+	database *sql.DB,
+	providers *services.TeamProviderResolver,
+) *IssuesController {
 	return &IssuesController{
-		queries: queries,
-		logger:  logger,
-		storage: storage,
+		queries:          queries,
+		logger:           logger,
+		storage:          storage,
+		validator:        validator.New(),
+		issueService:     services.NewIssueService(queries, database),
+		assigneeService:  services.NewIssueAssigneeService(queries),
+		labelService:     services.NewIssueLabelService(queries),
+		searchService:    services.NewIssueSearchService(queries),
+		duplicateService: services.NewIssueDuplicateService(queries, providers, logger),
+		activityService:  services.NewIssueActivityService(queries),
+		hierarchyService: services.NewIssueHierarchyService(queries, database),
+		linkService:      services.NewIssueLinkService(queries, database),
+		keyService:       services.NewIssueKeyService(queries),
+		fieldService:     services.NewCustomFieldService(queries, database),
 	}
 }
 
@@ -53,6 +86,75 @@ func (c *IssuesController) GetIssuesByColumnId(w http.ResponseWriter, r *http.Re
 	return nil
 }
 
+// SearchIssues runs a full-text search over all issues the user can access
+func (c *IssuesController) SearchIssues(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	query := r.URL.Query()
+	input := schemas.SearchIssuesInput{
+		Query:  query.Get("q"),
+		Sort:   query.Get("sort"),
+		Order:  query.Get("order"),
+		Cursor: query.Get("cursor"),
+	}
+
+	var err error
+	if input.ProjectID, err = parseOptionalIntParam(query.Get("project_id")); err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+	if input.ColumnID, err = parseOptionalIntParam(query.Get("column_id")); err != nil {
+		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
+	}
+
+	dateParams := map[string]*null.Time{
+		"created_after":  &input.CreatedAfter,
+		"created_before": &input.CreatedBefore,
+		"updated_after":  &input.UpdatedAfter,
+		"updated_before": &input.UpdatedBefore,
+	}
+	for name, target := range dateParams {
+		if *target, err = parseOptionalTimeParam(query.Get(name)); err != nil {
+			return httperr.WithStatus(fmt.Errorf("Invalid %s: expected RFC 3339 timestamp", name), http.StatusBadRequest)
+		}
+	}
+
+	if input.IssueFilter, err = parseIssueFilter(query); err != nil {
+		return err
+	}
+
+	if assignedToMe := query.Get("assigned_to_me"); assignedToMe != "" {
+		if input.AssignedToMe, err = strconv.ParseBool(assignedToMe); err != nil {
+			return httperr.WithStatus(errors.New("Invalid assigned_to_me: expected true or false"), http.StatusBadRequest)
+		}
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		input.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || input.Limit < 1 {
+			return httperr.WithStatus(errors.New("Limit must be between 1 and 100"), http.StatusBadRequest)
+		}
+	}
+
+	if err := c.validator.Struct(&input); err != nil {
+		return httperr.WithStatus(schemas.HandleIssueSearchValidationErrors(err), http.StatusBadRequest)
+	}
+
+	result, err := c.searchService.Search(r.Context(), userID, input)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearchCursor) {
+			return httperr.WithStatus(errors.New("Invalid cursor"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to search issues")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(result)
+	return nil
+}
+
 func (c *IssuesController) GetIssueByID(w http.ResponseWriter, r *http.Request) error {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -60,6 +162,40 @@ func (c *IssuesController) GetIssueByID(w http.ResponseWriter, r *http.Request)
 		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
 	}
 
+	return c.writeIssueDetails(w, r, id)
+}
+
+// GetIssueByKey returns the same details as GetIssueByID for an issue addressed by its key, e.g. ACA-142
+func (c *IssuesController) GetIssueByKey(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	id, err := c.keyService.Resolve(r.Context(), userID, chi.URLParam(r, "key"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidIssueKey):
+			return httperr.WithStatus(errors.New("Invalid issue key"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrIssueKeyNotFound):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrIssueKeyAmbiguous):
+			return httperr.WithStatus(errors.New("Issue key matches issues in more than one of your teams, use the issue ID"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to resolve issue key")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	// The route cannot check access before the key is resolved
+	if err := auth.CheckIssueAccess(r.Context(), c.queries, id); err != nil {
+		return httperr.WithStatus(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	return c.writeIssueDetails(w, r, id)
+}
+
+// writeIssueDetails writes the issue with its serialized description, people, labels, sub-issues and links
+func (c *IssuesController) writeIssueDetails(w http.ResponseWriter, r *http.Request, id int64) error {
 	issue, err := c.queries.GetIssueByID(r.Context(), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -79,23 +215,86 @@ func (c *IssuesController) GetIssueByID(w http.ResponseWriter, r *http.Request)
 		descriptionSerialized = &serialized
 	}
 
+	reporter, assignees, err := c.assigneeService.GetPeople(r.Context(), issue)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue reporter and assignees")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	labels, err := c.labelService.Get(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue labels")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	children, err := c.hierarchyService.GetChildren(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get sub-issues")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	links, err := c.linkService.List(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue links")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	blocked, err := c.linkService.IsBlocked(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to check whether the issue is blocked")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	key, err := c.keyService.Get(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue key")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	customFields, err := c.fieldService.GetValues(r.Context(), issue.ID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue custom fields")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
 	// Create response with serialized description
 	response := map[string]interface{}{
-		"id":                      issue.ID,
-		"name":                    issue.Name,
-		"description":             issue.Description,
-		"column_id":               issue.ColumnID,
-		"created_at":              issue.CreatedAt,
-		"updated_at":              issue.UpdatedAt,
-		"description_serialized":  descriptionSerialized,
+		"id":                     issue.ID,
+		"key":                    key,
+		"number":                 issue.Number,
+		"name":                   issue.Name,
+		"description":            issue.Description,
+		"column_id":              issue.ColumnID,
+		"priority":               issue.Priority,
+		"due_date":               issue.DueDate,
+		"rank":                   issue.Rank,
+		"created_at":             issue.CreatedAt,
+		"updated_at":             issue.UpdatedAt,
+		"description_serialized": descriptionSerialized,
+		"reporter_id":            issue.ReporterID,
+		"reporter":               reporter,
+		"assignees":              assignees,
+		"labels":                 labels,
+		"custom_fields":          customFields,
+		"parent_id":              issue.ParentID,
+		"children":               children.Children,
+		"progress":               children.Progress,
+		"links":                  links,
+		"blocked":                blocked,
 	}
 
+	setETag(w, issue.UpdatedAt)
 	json.NewEncoder(w).Encode(response)
 
 	return nil
 }
 
 func (c *IssuesController) CreateIssue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
 	var req schemas.CreateIssueInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
@@ -105,53 +304,157 @@ func (c *IssuesController) CreateIssue(w http.ResponseWriter, r *http.Request) e
 		return httperr.WithStatus(errors.New("Name is required"), http.StatusBadRequest)
 	}
 
-	params := db.CreateIssueParams{
-		Name:        req.Name,
-		ColumnID:    req.ColumnId,
-		Description: null.NewString(*req.Description, true),
+	if req.Priority != "" && !schemas.IsValidIssuePriority(req.Priority) {
+		return httperr.WithStatus(errors.New("Priority must be one of: none, low, medium, high, urgent"), http.StatusBadRequest)
 	}
 
-	issue, err := c.queries.CreateIssue(r.Context(), params)
-	if err != nil {
-		c.logger.WithError(err).Error("Failed to create issue")
-		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	if err := checkEstimates(req.EstimatePoints, req.EstimateMinutes); err != nil {
+		return err
 	}
 
-	// Upload serialized description to S3 if provided
+	// Linking as a duplicate means the user has already seen the candidates
+	if req.CheckDuplicates && !req.Force && !req.DuplicateOf.Valid {
+		candidates, err := c.duplicateService.FindCandidates(r.Context(), req.ColumnId, req.Name, *req.Description)
+		if err != nil {
+			c.logger.WithError(err).Error("Failed to check for duplicate issues")
+			return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+		}
+
+		if len(candidates) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(schemas.DuplicateIssuesResponse{
+				Message:    "Possible duplicate issues found",
+				Candidates: candidates,
+			})
+			return nil
+		}
+	}
+
+	params := db.CreateIssueParams{
+		Name:            req.Name,
+		ColumnID:        req.ColumnId,
+		Description:     null.NewString(*req.Description, true),
+		ReporterID:      null.IntFrom(userID),
+		Priority:        null.NewString(req.Priority, req.Priority != ""),
+		DueDate:         null.Time(req.DueDate),
+		ParentID:        req.ParentID,
+		EstimatePoints:  req.EstimatePoints,
+		EstimateMinutes: req.EstimateMinutes,
+	}
+
+	// Upload serialized description to S3 before the issue is committed, so a failed upload leaves nothing behind
+	var uploadErr error
+	var uploadDescription func(issue *db.Issue) error
 	if req.DescriptionSerialized != nil && *req.DescriptionSerialized != "" {
-		if err := c.storage.UploadDescription(r.Context(), issue.ID, *req.DescriptionSerialized); err != nil {
-			// S3 upload failed - should we rollback? For now, we'll delete the issue
-			c.queries.DeleteIssue(r.Context(), issue.ID)
-			c.logger.WithError(err).Error("Failed to upload description to S3, rolled back issue creation")
+		uploadDescription = func(issue *db.Issue) error {
+			uploadErr = c.storage.UploadDescription(r.Context(), issue.ID, *req.DescriptionSerialized)
+			return uploadErr
+		}
+	}
+
+	issue, warning, err := c.issueService.Create(r.Context(), params, req.DuplicateOf, req.AssigneeIDs, req.LabelIDs, req.CustomFields, uploadDescription)
+	if err != nil {
+		if uploadErr != nil {
+			c.logger.WithError(err).Error("Failed to upload description to S3, issue was not created")
 			return httperr.WithStatus(errors.New("Failed to save issue description"), http.StatusInternalServerError)
 		}
+		var violation *services.WorkflowViolation
+		var fieldErr *services.CustomFieldError
+		switch {
+		case errors.As(err, &violation):
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		case errors.As(err, &fieldErr):
+			return httperr.WithStatus(fieldErr, http.StatusBadRequest)
+		case errors.Is(err, services.ErrWIPLimitExceeded):
+			return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+		case errors.Is(err, services.ErrLabelNotFound):
+			return httperr.WithStatus(errors.New("Label not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrLabelNotInTeam):
+			return httperr.WithStatus(errors.New("Labels must belong to the project's team"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrAssigneeNotTeamMember):
+			return httperr.WithStatus(errors.New("Assignees must be members of the project's team"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrTooManyAssignees):
+			return httperr.WithStatus(errors.New("An issue can have at most 20 assignees"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrDuplicateTargetNotFound):
+			return httperr.WithStatus(errors.New("Duplicate target issue not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrDuplicateTargetOtherProject):
+			return httperr.WithStatus(errors.New("Duplicate target issue must be in the same project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrParentNotFound):
+			return httperr.WithStatus(errors.New("Parent issue not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrParentOtherProject):
+			return httperr.WithStatus(errors.New("Parent issue must be in the same project"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to create issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(issue)
+	json.NewEncoder(w).Encode(schemas.IssueWithWIPWarning{
+		Issue:        *issue,
+		CustomFields: c.customFieldValues(r.Context(), issue.ID),
+		WIPWarning:   warning,
+	})
 	return nil
 }
 
 func (c *IssuesController) UpdateIssue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
 	var req schemas.UpdateIssueInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
 	}
 	fmt.Println(req)
 
+	if req.Priority != "" && !schemas.IsValidIssuePriority(req.Priority) {
+		return httperr.WithStatus(errors.New("Priority must be one of: none, low, medium, high, urgent"), http.StatusBadRequest)
+	}
+
+	if err := checkEstimates(req.EstimatePoints, req.EstimateMinutes); err != nil {
+		return err
+	}
+
 	params := db.UpdateIssueParams{
-		ID:          req.ID,
-		Name:        req.Name,
-		Description: null.NewString(req.Description, req.Description != ""),
-		ColumnID:    req.ColumnId,
+		ID:              req.ID,
+		Name:            req.Name,
+		Description:     null.NewString(req.Description, req.Description != ""),
+		ColumnID:        req.ColumnId,
+		Priority:        null.NewString(req.Priority, req.Priority != ""),
+		DueDate:         null.Time(req.DueDate),
+		ClearDueDate:    req.ClearDueDate,
+		EstimatePoints:  req.EstimatePoints,
+		EstimateMinutes: req.EstimateMinutes,
+		ClearEstimate:   req.ClearEstimate,
+		IfUpdatedAt:     ifMatch(r),
 	}
 	fmt.Println(params)
 
-	issue, err := c.queries.UpdateIssue(r.Context(), params)
+	issue, warning, err := c.issueService.Update(r.Context(), userID, params, req.CustomFields)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if params.IfUpdatedAt != nil && c.writeIssueChanged(w, r, req.ID) {
+				return nil
+			}
 			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
 		}
+		var violation *services.WorkflowViolation
+		if errors.As(err, &violation) {
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		}
+		var fieldErr *services.CustomFieldError
+		if errors.As(err, &fieldErr) {
+			return httperr.WithStatus(fieldErr, http.StatusBadRequest)
+		}
+		if errors.Is(err, services.ErrWIPLimitExceeded) {
+			return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+		}
+		if errors.Is(err, services.ErrColumnNotInProject) {
+			return httperr.WithStatus(errors.New("Column must belong to the issue's project; move the issue to another project instead"), http.StatusBadRequest)
+		}
 		c.logger.WithError(err).Error("Failed to update issue")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
@@ -164,20 +467,108 @@ func (c *IssuesController) UpdateIssue(w http.ResponseWriter, r *http.Request) e
 		}
 	}
 
-	json.NewEncoder(w).Encode(issue)
+	setETag(w, issue.UpdatedAt)
+	json.NewEncoder(w).Encode(schemas.IssueWithWIPWarning{
+		Issue:        *issue,
+		CustomFields: c.customFieldValues(r.Context(), issue.ID),
+		WIPWarning:   warning,
+	})
+	return nil
+}
+
+// writeIssueChanged answers a change refused by its If-Match with the issue as it is now.
+// It reports false, writing nothing, when the issue does not exist.
+func (c *IssuesController) writeIssueChanged(w http.ResponseWriter, r *http.Request, issueID int64) bool {
+	issue, err := c.queries.GetIssueByID(r.Context(), issueID)
+	if err != nil {
+		return false
+	}
+
+	writePreconditionFailed(w, schemas.IssueWithWIPWarning{
+		Issue:        issue,
+		CustomFields: c.customFieldValues(r.Context(), issue.ID),
+	}, issue.UpdatedAt)
+	return true
+}
+
+// customFieldValues loads the issue's custom field values for a response; a failed load leaves them out
+func (c *IssuesController) customFieldValues(ctx context.Context, issueID int64) []schemas.CustomFieldValue {
+	values, err := c.fieldService.GetValues(ctx, issueID)
+	if err != nil {
+		c.logger.WithError(err).Warn("Failed to load custom field values")
+		return nil
+	}
+	return values
+}
+
+// MoveIssue moves the issue to a column position between two neighbours
+func (c *IssuesController) MoveIssue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.MoveIssueInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("Column ID is required"), http.StatusBadRequest)
+	}
+
+	issue, warning, err := c.issueService.Move(r.Context(), issueID, userID, req)
+	if err != nil {
+		var violation *services.WorkflowViolation
+		switch {
+		case errors.As(err, &violation):
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		case errors.Is(err, services.ErrWIPLimitExceeded):
+			return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrColumnNotInProject):
+			return httperr.WithStatus(errors.New("Target column must belong to the issue's project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrMoveNeighbourNotInColumn):
+			return httperr.WithStatus(errors.New("Neighbour issues must be in the target column"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrInvalidMoveNeighbours):
+			return httperr.WithStatus(errors.New("before_id must be ranked above after_id and neither can be the moved issue"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to move issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(schemas.IssueWithWIPWarning{
+		Issue:      *issue,
+		WIPWarning: warning,
+	})
 	return nil
 }
 
 func (c *IssuesController) DeleteIssue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
 	}
 
-	err = c.queries.DeleteIssue(r.Context(), id)
+	ifUpdatedAt := ifMatch(r)
+	err = c.issueService.Delete(r.Context(), id, userID, ifUpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if ifUpdatedAt != nil && c.writeIssueChanged(w, r, id) {
+				return nil
+			}
 			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
 		}
 		c.logger.WithError(err).Error("Failed to delete issue")
@@ -187,3 +578,587 @@ func (c *IssuesController) DeleteIssue(w http.ResponseWriter, r *http.Request) e
 	w.WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+// ArchiveIssue hides the issue from default listings without deleting it
+func (c *IssuesController) ArchiveIssue(w http.ResponseWriter, r *http.Request) error {
+	return c.setIssueArchived(w, r, true)
+}
+
+// UnarchiveIssue returns an archived issue to the default listings, warning when its column goes over a
+// soft WIP limit
+func (c *IssuesController) UnarchiveIssue(w http.ResponseWriter, r *http.Request) error {
+	return c.setIssueArchived(w, r, false)
+}
+
+func (c *IssuesController) setIssueArchived(w http.ResponseWriter, r *http.Request, archived bool) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	issue, err := c.issueService.SetArchived(r.Context(), id, userID, archived)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrWIPLimitExceeded):
+			return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to set issue archived state")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(issue)
+	return nil
+}
+
+// MoveIssueToProject moves the issue into another project the user has access to
+func (c *IssuesController) MoveIssueToProject(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.MoveIssueToProjectInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("Project ID is required"), http.StatusBadRequest)
+	}
+
+	// The route only checks the issue's own project
+	if err := auth.CheckProjectAccess(r.Context(), c.queries, req.ProjectID); err != nil {
+		return httperr.WithStatus(auth.ErrForbidden, http.StatusForbidden)
+	}
+	if err := auth.CheckProjectWritable(r.Context(), c.queries, req.ProjectID); err != nil {
+		if errors.Is(err, auth.ErrProjectArchived) {
+			return httperr.WithStatus(auth.ErrProjectArchived, http.StatusForbidden)
+		}
+		c.logger.WithError(err).Error("Failed to check whether the project is archived")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	issue, warning, err := c.issueService.MoveToProject(r.Context(), issueID, userID, req)
+	if err != nil {
+		var violation *services.WorkflowViolation
+		switch {
+		case errors.As(err, &violation):
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		case errors.Is(err, services.ErrWIPLimitExceeded):
+			return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrIssueAlreadyInProject):
+			return httperr.WithStatus(errors.New("Issue already belongs to this project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrColumnNotInProject):
+			return httperr.WithStatus(errors.New("Target column must belong to the target project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrProjectHasNoColumns):
+			return httperr.WithStatus(errors.New("Target project has no columns"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to move issue to project")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(schemas.IssueWithWIPWarning{
+		Issue:      *issue,
+		WIPWarning: warning,
+	})
+	return nil
+}
+
+// BulkIssues applies one operation to a list of issues and reports the outcome for each of them
+func (c *IssuesController) BulkIssues(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	var req schemas.BulkIssueInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleBulkIssueValidationErrors(err), http.StatusBadRequest)
+	}
+
+	outcomes, err := c.issueService.Bulk(r.Context(), userID, req)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to apply bulk issue operation")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	resp := schemas.BulkIssueResponse{Results: make([]schemas.BulkIssueResult, 0, len(outcomes))}
+	for _, outcome := range outcomes {
+		result := schemas.BulkIssueResult{
+			IssueID:    outcome.IssueID,
+			Status:     schemas.BulkIssueSucceeded,
+			WIPWarning: outcome.WIPWarning,
+		}
+		switch {
+		case outcome.Err != nil:
+			result.Status = schemas.BulkIssueFailed
+			result.Error = c.bulkIssueError(outcome.Err)
+			resp.Failed++
+		case outcome.RolledBack:
+			result.Status = schemas.BulkIssueRolledBack
+			resp.RolledBack = true
+		default:
+			resp.Succeeded++
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+	return nil
+}
+
+// bulkIssueError turns the failure of one issue of a bulk request into the message reported for it
+func (c *IssuesController) bulkIssueError(err error) string {
+	var violation *services.WorkflowViolation
+	switch {
+	case errors.As(err, &violation):
+		return violation.Message
+	case errors.Is(err, services.ErrBulkIssueNotAccessible), errors.Is(err, sql.ErrNoRows):
+		return "Issue not found"
+	case errors.Is(err, auth.ErrProjectArchived):
+		return auth.ErrProjectArchived.Error()
+	case errors.Is(err, services.ErrWIPLimitExceeded):
+		return "Column has reached its WIP limit"
+	case errors.Is(err, services.ErrColumnNotInProject):
+		return "Target column must belong to the issue's project"
+	case errors.Is(err, services.ErrAssigneeNotTeamMember):
+		return "Assignees must be members of the project's team"
+	case errors.Is(err, services.ErrLabelNotFound):
+		return "Label not found"
+	case errors.Is(err, services.ErrLabelNotInTeam):
+		return "Labels must belong to the project's team"
+	}
+	c.logger.WithError(err).Error("Failed to apply bulk issue operation to an issue")
+	return "Internal server error"
+}
+
+// SetIssueParent nests the issue under another issue of the same project, or detaches it when parent_id is null
+func (c *IssuesController) SetIssueParent(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.SetIssueParentInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	issue, err := c.hierarchyService.SetParent(r.Context(), issueID, userID, req.ParentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrParentNotFound):
+			return httperr.WithStatus(errors.New("Parent issue not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrParentOtherProject):
+			return httperr.WithStatus(errors.New("Parent issue must be in the same project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrParentCycle):
+			return httperr.WithStatus(errors.New("An issue cannot be nested under itself or one of its sub-issues"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to set issue parent")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(issue)
+	return nil
+}
+
+// GetIssueChildren returns the issue's direct sub-issues and their progress
+func (c *IssuesController) GetIssueChildren(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	children, err := c.hierarchyService.GetChildren(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get sub-issues")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(children)
+	return nil
+}
+
+// GetIssueLinks returns the issue's links in both directions
+func (c *IssuesController) GetIssueLinks(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	links, err := c.linkService.List(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue links")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(links)
+	return nil
+}
+
+// CreateIssueLink links the issue to another issue of the same team. The user must have access to both issues.
+func (c *IssuesController) CreateIssueLink(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CreateIssueLinkInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("linked_issue_id and a link_type of blocks, blocked_by, relates, duplicates, duplicated_by, clones or cloned_by are required"), http.StatusBadRequest)
+	}
+
+	// The route only checks the issue in the URL
+	if err := auth.CheckIssueAccess(r.Context(), c.queries, req.LinkedIssueID); err != nil {
+		return httperr.WithStatus(auth.ErrForbidden, http.StatusForbidden)
+	}
+
+	link, err := c.linkService.Create(r.Context(), issueID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrLinkToSelf):
+			return httperr.WithStatus(errors.New("An issue cannot be linked to itself"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrLinkTargetNotFound):
+			return httperr.WithStatus(errors.New("Linked issue not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrLinkOtherTeam):
+			return httperr.WithStatus(errors.New("Linked issues must belong to the same team"), http.StatusBadRequest)
+		case isUniqueViolation(err):
+			return httperr.WithStatus(errors.New("Issues are already linked this way"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to create issue link")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(link)
+	return nil
+}
+
+// DeleteIssueLink removes a link the issue is on either end of
+func (c *IssuesController) DeleteIssueLink(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	linkID, err := strconv.ParseInt(chi.URLParam(r, "link_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid link ID"), http.StatusBadRequest)
+	}
+
+	if err := c.linkService.Delete(r.Context(), issueID, linkID); err != nil {
+		if errors.Is(err, services.ErrIssueLinkNotFound) {
+			return httperr.WithStatus(errors.New("Link not found on this issue"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to delete issue link")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GetIssueActivity returns the issue's history of changes oldest first
+func (c *IssuesController) GetIssueActivity(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	activity, err := c.activityService.List(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue activity")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(activity)
+	return nil
+}
+
+// parseOptionalIntParam parses an optional numeric query parameter
+func parseOptionalIntParam(value string) (null.Int, error) {
+	if value == "" {
+		return null.Int{}, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return null.Int{}, err
+	}
+	return null.IntFrom(parsed), nil
+}
+
+// parseOptionalDateParam parses an optional YYYY-MM-DD query parameter
+func parseOptionalDateParam(value string) (null.Time, error) {
+	if value == "" {
+		return null.Time{}, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return null.Time{}, err
+	}
+	return null.TimeFrom(parsed), nil
+}
+
+// parseIssueFilter reads the priority, label_id, due_after, due_before and include_archived query parameters
+// shared by issue listings
+func parseIssueFilter(query url.Values) (schemas.IssueFilter, error) {
+	filter := schemas.IssueFilter{Priority: query.Get("priority")}
+
+	if filter.Priority != "" && !schemas.IsValidIssuePriority(filter.Priority) {
+		return filter, httperr.WithStatus(errors.New("Priority must be one of: none, low, medium, high, urgent"), http.StatusBadRequest)
+	}
+
+	var err error
+	if filter.LabelID, err = parseOptionalIntParam(query.Get("label_id")); err != nil {
+		return filter, httperr.WithStatus(errors.New("Invalid label ID"), http.StatusBadRequest)
+	}
+
+	dateParams := map[string]*null.Time{
+		"due_after":  &filter.DueAfter,
+		"due_before": &filter.DueBefore,
+	}
+	for name, target := range dateParams {
+		if *target, err = parseOptionalDateParam(query.Get(name)); err != nil {
+			return filter, httperr.WithStatus(fmt.Errorf("Invalid %s: expected YYYY-MM-DD date", name), http.StatusBadRequest)
+		}
+	}
+
+	if filter.IncludeArchived, err = parseIncludeArchived(query); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseIncludeArchived reads the optional include_archived query parameter
+func parseIncludeArchived(query url.Values) (bool, error) {
+	value := query.Get("include_archived")
+	if value == "" {
+		return false, nil
+	}
+	includeArchived, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, httperr.WithStatus(errors.New("Invalid include_archived: expected true or false"), http.StatusBadRequest)
+	}
+	return includeArchived, nil
+}
+
+// maxEstimate keeps estimates within the integer columns they are stored in
+const maxEstimate = 1_000_000
+
+// checkEstimates rejects negative and oversized estimates
+func checkEstimates(points null.Int, minutes null.Int) error {
+	for _, estimate := range []null.Int{points, minutes} {
+		if estimate.Valid && (estimate.Int64 < 0 || estimate.Int64 > maxEstimate) {
+			return httperr.WithStatus(errors.New("Estimates must be between 0 and 1000000"), http.StatusBadRequest)
+		}
+	}
+	return nil
+}
+
+// parseOptionalTimeParam parses an optional RFC 3339 query parameter
+func parseOptionalTimeParam(value string) (null.Time, error) {
+	if value == "" {
+		return null.Time{}, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return null.Time{}, err
+	}
+	return null.TimeFrom(parsed.UTC()), nil
+}
+
+func (c *IssuesController) AssignIssue(w http.ResponseWriter, r *http.Request) error {
+	actorID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.AssignIssueInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("User ID is required"), http.StatusBadRequest)
+	}
+
+	if err := c.issueService.Assign(r.Context(), issueID, actorID, req.UserID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrAssigneeNotTeamMember):
+			return httperr.WithStatus(errors.New("Assignees must be members of the project's team"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to assign issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	return c.writeAssignees(w, r, issueID, http.StatusCreated)
+}
+
+func (c *IssuesController) UnassignIssue(w http.ResponseWriter, r *http.Request) error {
+	actorID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	userID, err := strconv.ParseInt(chi.URLParam(r, "user_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid user ID"), http.StatusBadRequest)
+	}
+
+	if err := c.issueService.Unassign(r.Context(), issueID, actorID, userID); err != nil {
+		if errors.Is(err, services.ErrAssigneeNotFound) {
+			return httperr.WithStatus(errors.New("User is not assigned to this issue"), http.StatusNotFound)
+		}
+		var violation *services.WorkflowViolation
+		if errors.As(err, &violation) {
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		}
+		c.logger.WithError(err).Error("Failed to unassign issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	return c.writeAssignees(w, r, issueID, http.StatusOK)
+}
+
+// writeAssignees responds with the issue's current assignees
+func (c *IssuesController) writeAssignees(w http.ResponseWriter, r *http.Request, issueID int64, status int) error {
+	issue, err := c.queries.GetIssueByID(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	_, assignees, err := c.assigneeService.GetPeople(r.Context(), issue)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue assignees")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(assignees)
+	return nil
+}
+
+func (c *IssuesController) AddIssueLabel(w http.ResponseWriter, r *http.Request) error {
+	actorID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.AddIssueLabelInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleLabelValidationErrors(err), http.StatusBadRequest)
+	}
+
+	if err := c.issueService.AddLabel(r.Context(), issueID, actorID, req.LabelID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrLabelNotFound):
+			return httperr.WithStatus(errors.New("Label not found"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrLabelNotInTeam):
+			return httperr.WithStatus(errors.New("Labels must belong to the project's team"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to label issue")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	return c.writeLabels(w, r, issueID, http.StatusCreated)
+}
+
+func (c *IssuesController) RemoveIssueLabel(w http.ResponseWriter, r *http.Request) error {
+	actorID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	labelID, err := strconv.ParseInt(chi.URLParam(r, "label_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid label ID"), http.StatusBadRequest)
+	}
+
+	if err := c.issueService.RemoveLabel(r.Context(), issueID, actorID, labelID); err != nil {
+		if errors.Is(err, services.ErrIssueLabelNotFound) {
+			return httperr.WithStatus(errors.New("Label is not applied to this issue"), http.StatusNotFound)
+		}
+		var violation *services.WorkflowViolation
+		if errors.As(err, &violation) {
+			return httperr.WithStatus(violation, http.StatusUnprocessableEntity)
+		}
+		c.logger.WithError(err).Error("Failed to remove issue label")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	return c.writeLabels(w, r, issueID, http.StatusOK)
+}
+
+// writeLabels responds with the issue's current labels
+func (c *IssuesController) writeLabels(w http.ResponseWriter, r *http.Request, issueID int64, status int) error {
+	labels, err := c.labelService.Get(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue labels")
+		return httperr.WithStatus(errors.New("packages server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(labels)
+	return nil
+}
@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"acacia/packages/db"
 	"acacia/packages/schemas"
 	"acacia/packages/testutils"
 
 	"github.com/guregu/null"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,8 +38,9 @@ func TestIssueAuthorization(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -81,8 +86,9 @@ func TestIssueAuthorization(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -127,8 +133,9 @@ func TestIssueAuthorization(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -191,8 +198,9 @@ func TestIssueAuthorization(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -245,8 +253,9 @@ func TestIssueAuthorization(t *testing.T) {
 
 		// Create a project
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: teamID,
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -287,3 +296,1711 @@ func TestIssueAuthorization(t *testing.T) {
 		assert.Equal(t, column.ID, issue.ColumnID)
 	})
 }
+
+func TestSearchIssues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should return matching issues with highlights and paginate with cursor", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		for _, name := range []string{"Database migration fails", "Slow database queries", "Update <img src=x onerror=alert(1)> landing page"} {
+			_, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+				Name:        name,
+				ColumnID:    column.ID,
+				Description: null.StringFrom("Details about " + name),
+			})
+			require.NoError(t, err)
+		}
+
+		url := fmt.Sprintf("%s/issues/search?q=database&limit=1", setup.Server.GetURL())
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var firstPage schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&firstPage))
+		require.Len(t, firstPage.Issues, 1)
+		require.NotNil(t, firstPage.NextCursor)
+		assert.Contains(t, firstPage.Issues[0].NameHighlight, "<mark>")
+
+		// Highlights are HTML, so markup in the issue's text comes back escaped
+		resp, err = client.Get(fmt.Sprintf("%s/issues/search?q=landing", setup.Server.GetURL()))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var landing schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&landing))
+		require.Len(t, landing.Issues, 1)
+		assert.NotContains(t, landing.Issues[0].NameHighlight, "<img")
+		assert.Contains(t, landing.Issues[0].NameHighlight, "&lt;img")
+		assert.Contains(t, landing.Issues[0].NameHighlight, "<mark>landing</mark>")
+		assert.NotContains(t, landing.Issues[0].Snippet, "<img")
+
+		url = fmt.Sprintf("%s/issues/search?q=database&limit=1&cursor=%s", setup.Server.GetURL(), *firstPage.NextCursor)
+		resp, err = client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var secondPage schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&secondPage))
+		require.Len(t, secondPage.Issues, 1)
+		assert.Nil(t, secondPage.NextCursor)
+		assert.NotEqual(t, firstPage.Issues[0].ID, secondPage.Issues[0].ID)
+	})
+
+	t.Run("should not return issues from teams user is not member of", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user1, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		team1ID := testutils.CreateTeamAndAddUser(t, ctx, setup, user1.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+			Name:        "Secret database issue",
+			ColumnID:    column.ID,
+			Description: null.StringFrom("Only team 1 should see this"),
+		})
+		require.NoError(t, err)
+
+		client2 := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+
+		url := fmt.Sprintf("%s/issues/search?q=database", setup.Server.GetURL())
+		resp, err := client2.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Empty(t, result.Issues)
+	})
+
+	t.Run("should return 400 for invalid sort", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+
+		url := fmt.Sprintf("%s/issues/search?q=database&sort=priority", setup.Server.GetURL())
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCreateIssueDuplicateCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	setupProject := func(t *testing.T, setup *testutils.IntegrationTestSetup) (*http.Client, db.ProjectStatusColumn, db.Issue) {
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		existing, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+			Name:        "Login page crashes on submit",
+			ColumnID:    column.ID,
+			Description: null.StringFrom("Submitting the login form crashes the page"),
+		})
+		require.NoError(t, err)
+
+		return client, column, existing
+	}
+
+	t.Run("should return candidates when a similar issue exists", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, column, existing := setupProject(t, setup)
+
+		description := "The login page crashes when I press submit"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:            "Login crashes",
+			Description:     &description,
+			ColumnId:        column.ID,
+			CheckDuplicates: true,
+		})
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("%s/issues", setup.Server.GetURL())
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		var result schemas.DuplicateIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.NotEmpty(t, result.Candidates)
+		assert.Equal(t, existing.ID, result.Candidates[0].ID)
+		assert.Greater(t, result.Candidates[0].Score, 0.0)
+
+		issues, err := setup.Queries.GetIssuesByColumnId(ctx, column.ID)
+		require.NoError(t, err)
+		assert.Len(t, issues, 1)
+	})
+
+	t.Run("should create the issue when forced", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, column, _ := setupProject(t, setup)
+
+		description := "The login page crashes when I press submit"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:            "Login crashes",
+			Description:     &description,
+			ColumnId:        column.ID,
+			CheckDuplicates: true,
+			Force:           true,
+		})
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("%s/issues", setup.Server.GetURL())
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("should create the issue linked as a duplicate", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, column, existing := setupProject(t, setup)
+
+		description := "The login page crashes when I press submit"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:        "Login crashes",
+			Description: &description,
+			ColumnId:    column.ID,
+			DuplicateOf: null.IntFrom(existing.ID),
+		})
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("%s/issues", setup.Server.GetURL())
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var issue db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+
+		// Linking the same pair again must violate the unique constraint
+		_, err = setup.Queries.CreateIssueLink(ctx, db.CreateIssueLinkParams{
+			IssueID:       issue.ID,
+			LinkedIssueID: existing.ID,
+			LinkType:      "duplicates",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject a duplicate target from another project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, column, existing := setupProject(t, setup)
+
+		project, err := setup.Queries.GetProjectByID(ctx, int64(column.ProjectID))
+		require.NoError(t, err)
+		otherProject, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Other Project",
+			TeamID:    project.TeamID,
+			KeyPrefix: "OTH",
+		})
+		require.NoError(t, err)
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(otherProject.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		description := "Same crash in another project"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:        "Login crashes",
+			Description: &description,
+			ColumnId:    otherColumn.ID,
+			DuplicateOf: null.IntFrom(existing.ID),
+		})
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("%s/issues", setup.Server.GetURL())
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestIssueAssignees(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should record the reporter and manage assignees", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user1, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user1.ID, "Team 1")
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		_, err = setup.Queries.AddTeamMember(ctx, db.AddTeamMemberParams{TeamID: teamID, UserID: user2.ID})
+		require.NoError(t, err)
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "outsider@example.com", "Outsider", "password123")
+		outsider, err := setup.Queries.GetUserByEmail(ctx, "outsider@example.com")
+		require.NoError(t, err)
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		description := "Needs an owner"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:        "Owned issue",
+			Description: &description,
+			ColumnId:    column.ID,
+			AssigneeIDs: []int64{user1.ID},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.Post(fmt.Sprintf("%s/issues", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var issue db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, user1.ID, issue.ReporterID.Int64)
+
+		assigneesURL := fmt.Sprintf("%s/issues/%d/assignees", setup.Server.GetURL(), issue.ID)
+
+		// Team members can be assigned
+		body, _ := json.Marshal(schemas.AssignIssueInput{UserID: user2.ID})
+		resp, err = client.Post(assigneesURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var assignees []schemas.IssueUser
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&assignees))
+		require.Len(t, assignees, 2)
+
+		// Users outside the team cannot
+		body, _ = json.Marshal(schemas.AssignIssueInput{UserID: outsider.ID})
+		resp, err = client.Post(assigneesURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Get(fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), issue.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var details struct {
+			Reporter  *schemas.IssueUser  `json:"reporter"`
+			Assignees []schemas.IssueUser `json:"assignees"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+		require.NotNil(t, details.Reporter)
+		assert.Equal(t, user1.ID, details.Reporter.ID)
+		assert.Len(t, details.Assignees, 2)
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", assigneesURL, user2.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", assigneesURL, user2.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// Assigning and unassigning are recorded in the issue's history
+		activity, err := setup.Queries.GetIssueActivity(ctx, issue.ID)
+		require.NoError(t, err)
+		var changes [][2]string
+		for _, entry := range activity {
+			if entry.Field.String == "assignee" {
+				assert.Equal(t, user1.ID, entry.ActorID.Int64)
+				changes = append(changes, [2]string{entry.OldValue.String, entry.NewValue.String})
+			}
+		}
+		user2ID := strconv.FormatInt(user2.ID, 10)
+		assert.Equal(t, [][2]string{{"", user2ID}, {user2ID, ""}}, changes)
+	})
+
+	t.Run("should filter search results to issues assigned to me", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		mine, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Mine", ColumnID: column.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Not mine", ColumnID: column.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.AddIssueAssignee(ctx, db.AddIssueAssigneeParams{IssueID: mine.ID, UserID: user.ID})
+		require.NoError(t, err)
+
+		resp, err := client.Get(fmt.Sprintf("%s/issues/search?assigned_to_me=true", setup.Server.GetURL()))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, mine.ID, result.Issues[0].ID)
+	})
+}
+
+func TestIssueTriageFields(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should create issues with priority, due date and labels and filter by them", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+		otherTeamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 2")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		bug, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: teamID, Name: "bug", Color: "#d73a4a"})
+		require.NoError(t, err)
+		foreign, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: otherTeamID, Name: "bug", Color: "#d73a4a"})
+		require.NoError(t, err)
+
+		description := "Crashes on save"
+		dueDate := time.Date(2030, 5, 17, 0, 0, 0, 0, time.UTC)
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:        "Save crash",
+			Description: &description,
+			ColumnId:    column.ID,
+			Priority:    schemas.IssuePriorityUrgent,
+			DueDate:     schemas.DateFrom(dueDate),
+			LabelIDs:    []int64{bug.ID},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.Post(fmt.Sprintf("%s/issues", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var issue db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, schemas.IssuePriorityUrgent, issue.Priority)
+		assert.True(t, issue.DueDate.Time.Equal(dueDate))
+
+		// A timestamp's offset does not move the due date to another day
+		reqBody = []byte(fmt.Sprintf(`{"id": %d, "column_id": %d, "due_date": "2030-05-18T23:30:00-05:00"}`, issue.ID, column.ID))
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues", setup.Server.GetURL()), bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, "2030-05-18", issue.DueDate.Time.Format(time.DateOnly))
+
+		// Issues default to no priority
+		plain, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Plain", ColumnID: column.ID})
+		require.NoError(t, err)
+		assert.Equal(t, schemas.IssuePriorityNone, plain.Priority)
+
+		// Labels from another team are rejected
+		reqBody, err = json.Marshal(schemas.CreateIssueInput{
+			Name:        "Foreign label",
+			Description: &description,
+			ColumnId:    column.ID,
+			LabelIDs:    []int64{foreign.ID},
+		})
+		require.NoError(t, err)
+		resp, err = client.Post(fmt.Sprintf("%s/issues", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Get(fmt.Sprintf("%s/issues/search?priority=urgent&label_id=%d&due_before=2030-06-01", setup.Server.GetURL(), bug.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result schemas.SearchIssuesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, issue.ID, result.Issues[0].ID)
+		require.Len(t, result.Issues[0].Labels, 1)
+		assert.Equal(t, bug.ID, result.Issues[0].Labels[0].ID)
+
+		resp, err = client.Get(fmt.Sprintf("%s/projects/%d/details?priority=none", setup.Server.GetURL(), project.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var details schemas.GetProjectDetailsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+		require.Len(t, details.Issues, 1)
+		assert.Equal(t, plain.ID, details.Issues[0].ID)
+
+		resp, err = client.Get(fmt.Sprintf("%s/issues/search?priority=critical", setup.Server.GetURL()))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should add and remove labels on an issue", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Unlabelled", ColumnID: column.ID})
+		require.NoError(t, err)
+		label, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: teamID, Name: "ops", Color: "#0e8a16"})
+		require.NoError(t, err)
+
+		labelsURL := fmt.Sprintf("%s/issues/%d/labels", setup.Server.GetURL(), issue.ID)
+
+		body, _ := json.Marshal(schemas.AddIssueLabelInput{LabelID: label.ID})
+		resp, err := client.Post(labelsURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var labels []db.Label
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&labels))
+		require.Len(t, labels, 1)
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", labelsURL, label.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		labels = nil
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&labels))
+		assert.Empty(t, labels)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", labelsURL, label.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestMoveIssue(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should reorder issues within and across columns", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		done, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+		})
+		require.NoError(t, err)
+
+		// New issues go to the top of the column
+		c, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "C", ColumnID: todo.ID})
+		require.NoError(t, err)
+		b, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "B", ColumnID: todo.ID})
+		require.NoError(t, err)
+		a, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "A", ColumnID: todo.ID})
+		require.NoError(t, err)
+
+		columnOrder := func(columnID int64) []int64 {
+			issues, err := setup.Queries.GetIssuesByColumnId(ctx, columnID)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(issues))
+			for _, issue := range issues {
+				ids = append(ids, issue.ID)
+			}
+			return ids
+		}
+		move := func(issueID int64, input schemas.MoveIssueInput) *http.Response {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := client.Post(fmt.Sprintf("%s/issues/%d/move", setup.Server.GetURL(), issueID), "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		require.Equal(t, []int64{a.ID, b.ID, c.ID}, columnOrder(todo.ID))
+
+		// Between two neighbours
+		resp := move(c.ID, schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(a.ID), AfterID: null.IntFrom(b.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{a.ID, c.ID, b.ID}, columnOrder(todo.ID))
+
+		// Only the moved issue's rank changes
+		unchanged, err := setup.Queries.GetIssueByID(ctx, b.ID)
+		require.NoError(t, err)
+		assert.Equal(t, b.Rank, unchanged.Rank)
+
+		// Above the first issue
+		resp = move(b.ID, schemas.MoveIssueInput{ColumnID: todo.ID, AfterID: null.IntFrom(a.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{b.ID, a.ID, c.ID}, columnOrder(todo.ID))
+
+		// Right after an issue that already has a successor
+		resp = move(c.ID, schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(b.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{b.ID, c.ID, a.ID}, columnOrder(todo.ID))
+
+		// Into another column
+		resp = move(c.ID, schemas.MoveIssueInput{ColumnID: done.ID})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{b.ID, a.ID}, columnOrder(todo.ID))
+		assert.Equal(t, []int64{c.ID}, columnOrder(done.ID))
+
+		// Neighbours must be in the target column and in order
+		resp = move(a.ID, schemas.MoveIssueInput{ColumnID: done.ID, BeforeID: null.IntFrom(b.ID)})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp = move(c.ID, schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(a.ID), AfterID: null.IntFrom(b.ID)})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should rank issues changing column below the target's issues and split tied ranks", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "TP"})
+		require.NoError(t, err)
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "Doing", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		todo, doing, done := columns[0], columns[1], columns[2]
+
+		create := func(name string, columnID int64) db.Issue {
+			issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: name, ColumnID: columnID})
+			require.NoError(t, err)
+			return issue
+		}
+		columnOrder := func(columnID int64) []int64 {
+			issues, err := setup.Queries.GetIssuesByColumnId(ctx, columnID)
+			require.NoError(t, err)
+			ids := make([]int64, 0, len(issues))
+			for _, issue := range issues {
+				ids = append(ids, issue.ID)
+			}
+			return ids
+		}
+		request := func(method string, url string, body interface{}) *http.Response {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(method, setup.Server.GetURL()+url, bytes.NewBuffer(payload))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		// Both columns rank their first issue 0, so keeping ranks would tie
+		b := create("B", todo.ID)
+		a := create("A", todo.ID)
+		d := create("D", doing.ID)
+		c := create("C", doing.ID)
+
+		resp := request(http.MethodPut, "/issues", schemas.UpdateIssueInput{ID: c.ID, Name: "C", ColumnId: todo.ID})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{a.ID, b.ID, c.ID}, columnOrder(todo.ID))
+
+		// Deleting a column moves its issues below the next column's, in order
+		e := create("E", done.ID)
+		resp = request(http.MethodDelete, fmt.Sprintf("/project-columns/%d", doing.ID), nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, []int64{e.ID, d.ID}, columnOrder(done.ID))
+
+		// Ties left by earlier versions are ordered by ID and split when an issue goes between them
+		_, err = setup.DB.DB.ExecContext(ctx, "UPDATE issues SET rank = 1 WHERE column_id = $1", todo.ID)
+		require.NoError(t, err)
+		require.Equal(t, []int64{b.ID, a.ID, c.ID}, columnOrder(todo.ID))
+
+		resp = request(http.MethodPost, fmt.Sprintf("/issues/%d/move", e.ID), schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(b.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{b.ID, e.ID, a.ID, c.ID}, columnOrder(todo.ID))
+
+		_, err = setup.DB.DB.ExecContext(ctx, "UPDATE issues SET rank = 1 WHERE column_id = $1", todo.ID)
+		require.NoError(t, err)
+		require.Equal(t, []int64{b.ID, a.ID, c.ID, e.ID}, columnOrder(todo.ID))
+		resp = request(http.MethodPost, fmt.Sprintf("/issues/%d/move", c.ID), schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(b.ID), AfterID: null.IntFrom(a.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []int64{b.ID, c.ID, a.ID, e.ID}, columnOrder(todo.ID))
+
+		// A deleted issue is no longer a neighbour, so an issue dropped after C goes to the bottom
+		resp = request(http.MethodDelete, fmt.Sprintf("/issues/%d", a.ID), nil)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		resp = request(http.MethodPost, fmt.Sprintf("/issues/%d/move", e.ID), schemas.MoveIssueInput{ColumnID: todo.ID, BeforeID: null.IntFrom(c.ID)})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var deletedRank, movedRank float64
+		require.NoError(t, setup.DB.DB.QueryRowContext(ctx, "SELECT rank FROM issues WHERE id = $1", a.ID).Scan(&deletedRank))
+		require.NoError(t, setup.DB.DB.QueryRowContext(ctx, "SELECT rank FROM issues WHERE id = $1", e.ID).Scan(&movedRank))
+		assert.GreaterOrEqual(t, movedRank, deletedRank)
+		assert.Equal(t, []int64{b.ID, c.ID, e.ID}, columnOrder(todo.ID))
+	})
+
+	t.Run("should reject columns from another project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		var columns []db.ProjectStatusColumn
+		for i, name := range []string{"Project 1", "Project 2"} {
+			project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: name, TeamID: teamID, KeyPrefix: fmt.Sprintf("P%d", i+1)})
+			require.NoError(t, err)
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      "To Do",
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Stay put", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(schemas.MoveIssueInput{ColumnID: columns[1].ID})
+		require.NoError(t, err)
+		resp, err := client.Post(fmt.Sprintf("%s/issues/%d/move", setup.Server.GetURL(), issue.ID), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestIssueActivity(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should record creation, field changes, moves and deletion", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		done, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+		})
+		require.NoError(t, err)
+
+		description := "First draft"
+		reqBody, err := json.Marshal(schemas.CreateIssueInput{
+			Name:        "Fix login",
+			Description: &description,
+			ColumnId:    todo.ID,
+		})
+		require.NoError(t, err)
+		resp, err := client.Post(fmt.Sprintf("%s/issues", setup.Server.GetURL()), "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var issue db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+
+		reqBody, err = json.Marshal(schemas.UpdateIssueInput{
+			ID:          issue.ID,
+			Name:        "Fix login redirect",
+			Description: description,
+			Priority:    schemas.IssuePriorityHigh,
+		})
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues", setup.Server.GetURL()), bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		reqBody, err = json.Marshal(schemas.MoveIssueInput{ColumnID: done.ID})
+		require.NoError(t, err)
+		resp, err = client.Post(fmt.Sprintf("%s/issues/%d/move", setup.Server.GetURL(), issue.ID), "application/json", bytes.NewBuffer(reqBody))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = client.Get(fmt.Sprintf("%s/issues/%d/activity", setup.Server.GetURL(), issue.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var activity []schemas.IssueActivity
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&activity))
+
+		require.Len(t, activity, 4)
+		assert.Equal(t, schemas.IssueActivityCreated, activity[0].Action)
+		require.NotNil(t, activity[0].Actor)
+		assert.Equal(t, user.ID, activity[0].Actor.ID)
+
+		assert.Equal(t, schemas.IssueActivityUpdated, activity[1].Action)
+		assert.Equal(t, null.StringFrom("name"), activity[1].Field)
+		assert.Equal(t, null.StringFrom("Fix login"), activity[1].OldValue)
+		assert.Equal(t, null.StringFrom("Fix login redirect"), activity[1].NewValue)
+
+		assert.Equal(t, null.StringFrom("priority"), activity[2].Field)
+		assert.Equal(t, null.StringFrom(schemas.IssuePriorityNone), activity[2].OldValue)
+		assert.Equal(t, null.StringFrom(schemas.IssuePriorityHigh), activity[2].NewValue)
+
+		assert.Equal(t, schemas.IssueActivityMoved, activity[3].Action)
+		assert.Equal(t, null.StringFrom(fmt.Sprint(todo.ID)), activity[3].OldValue)
+		assert.Equal(t, null.StringFrom(fmt.Sprint(done.ID)), activity[3].NewValue)
+
+		// The history outlives the issue
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), issue.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		rows, err := setup.Queries.GetIssueActivity(ctx, issue.ID)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+		assert.Equal(t, schemas.IssueActivityDeleted, rows[4].Action)
+		assert.Equal(t, null.StringFrom("Fix login redirect"), rows[4].OldValue)
+	})
+}
+
+func TestIssueHierarchy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should nest sub-issues, reject cycles and roll up progress", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		done, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+			IsDone:    true,
+		})
+		require.NoError(t, err)
+
+		other, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Other", TeamID: teamID, KeyPrefix: "OTH"})
+		require.NoError(t, err)
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(other.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		stranger, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Elsewhere", ColumnID: otherColumn.ID})
+		require.NoError(t, err)
+
+		epic, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Epic", ColumnID: todo.ID})
+		require.NoError(t, err)
+
+		create := func(name string, columnID int64, parentID int64) *http.Response {
+			description := ""
+			body, err := json.Marshal(schemas.CreateIssueInput{
+				Name:        name,
+				Description: &description,
+				ColumnId:    columnID,
+				ParentID:    null.IntFrom(parentID),
+			})
+			require.NoError(t, err)
+			resp, err := client.Post(fmt.Sprintf("%s/issues", setup.Server.GetURL()), "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+		setParent := func(issueID int64, parentID null.Int) *http.Response {
+			body, err := json.Marshal(schemas.SetIssueParentInput{ParentID: parentID})
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues/%d/parent", setup.Server.GetURL(), issueID), bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp := create("Task 1", todo.ID, epic.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var task db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&task))
+		assert.Equal(t, null.IntFrom(epic.ID), task.ParentID)
+
+		resp = create("Task 2", done.ID, epic.ID)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// The parent must be in the same project
+		resp = create("Task 3", todo.ID, stranger.ID)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// An issue cannot be nested under itself or its own sub-issue
+		resp = setParent(epic.ID, null.IntFrom(epic.ID))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		resp = setParent(epic.ID, null.IntFrom(task.ID))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Get(fmt.Sprintf("%s/issues/%d/children", setup.Server.GetURL(), epic.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var children schemas.IssueChildren
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&children))
+		assert.Len(t, children.Children, 2)
+		assert.Equal(t, schemas.IssueProgress{Done: 1, Total: 2}, children.Progress)
+
+		resp, err = client.Get(fmt.Sprintf("%s/projects/%d/details", setup.Server.GetURL(), project.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var details schemas.GetProjectDetailsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+		for _, issue := range details.Issues {
+			if issue.ID == epic.ID {
+				require.NotNil(t, issue.Progress)
+				assert.Equal(t, int64(1), issue.Progress.Done)
+			} else {
+				assert.Nil(t, issue.Progress)
+			}
+		}
+
+		// Detaching records the change in the issue's history
+		resp = setParent(task.ID, null.Int{})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		activity, err := setup.Queries.GetIssueActivity(ctx, task.ID)
+		require.NoError(t, err)
+		last := activity[len(activity)-1]
+		assert.Equal(t, null.StringFrom("parent_id"), last.Field)
+		assert.Equal(t, null.StringFrom(fmt.Sprint(epic.ID)), last.OldValue)
+		assert.False(t, last.NewValue.Valid)
+	})
+}
+
+func TestIssueLinks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should link issues across projects and report blocked issues", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		backend, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Backend", TeamID: teamID, KeyPrefix: "BE"})
+		require.NoError(t, err)
+		backendTodo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(backend.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		backendDone, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(backend.ID),
+			Name:      "Done",
+			IsDone:    true,
+		})
+		require.NoError(t, err)
+		frontend, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Frontend", TeamID: teamID, KeyPrefix: "FE"})
+		require.NoError(t, err)
+		frontendTodo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(frontend.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		api, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Add endpoint", ColumnID: backendTodo.ID})
+		require.NoError(t, err)
+		ui, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Add page", ColumnID: frontendTodo.ID})
+		require.NoError(t, err)
+
+		// Another team's issue cannot be linked
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		otherTeamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user2.ID, "Team 2")
+		otherProject, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Secret", TeamID: otherTeamID, KeyPrefix: "SEC"})
+		require.NoError(t, err)
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(otherProject.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		secret, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Secret", ColumnID: otherColumn.ID})
+		require.NoError(t, err)
+
+		link := func(issueID int64, input schemas.CreateIssueLinkInput) *http.Response {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := client.Post(fmt.Sprintf("%s/issues/%d/links", setup.Server.GetURL(), issueID), "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+		links := func(issueID int64) []schemas.IssueLinkDetails {
+			resp, err := client.Get(fmt.Sprintf("%s/issues/%d/links", setup.Server.GetURL(), issueID))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var result []schemas.IssueLinkDetails
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			return result
+		}
+		frontendBlocked := func() bool {
+			resp, err := client.Get(fmt.Sprintf("%s/projects/%d/details", setup.Server.GetURL(), frontend.ID))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			var details schemas.GetProjectDetailsResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+			require.Len(t, details.Issues, 1)
+			return details.Issues[0].Blocked
+		}
+
+		resp := link(ui.ID, schemas.CreateIssueLinkInput{LinkedIssueID: api.ID, LinkType: schemas.IssueLinkBlockedBy})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		fromAPI := links(api.ID)
+		require.Len(t, fromAPI, 1)
+		assert.Equal(t, schemas.IssueLinkBlocks, fromAPI[0].LinkType)
+		assert.Equal(t, ui.ID, fromAPI[0].Issue.ID)
+		fromUI := links(ui.ID)
+		require.Len(t, fromUI, 1)
+		assert.Equal(t, schemas.IssueLinkBlockedBy, fromUI[0].LinkType)
+
+		assert.True(t, frontendBlocked())
+		_, err = setup.Queries.ArchiveIssue(ctx, api.ID)
+		require.NoError(t, err)
+		assert.False(t, frontendBlocked())
+		_, err = setup.Queries.UnarchiveIssue(ctx, api.ID)
+		require.NoError(t, err)
+		assert.True(t, frontendBlocked())
+		_, err = setup.Queries.UpdateIssue(ctx, db.UpdateIssueParams{ID: api.ID, Name: api.Name, ColumnID: backendDone.ID})
+		require.NoError(t, err)
+		assert.False(t, frontendBlocked())
+
+		// relates has no direction, so the reverse link already exists
+		resp = link(api.ID, schemas.CreateIssueLinkInput{LinkedIssueID: ui.ID, LinkType: schemas.IssueLinkRelates})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp = link(ui.ID, schemas.CreateIssueLinkInput{LinkedIssueID: api.ID, LinkType: schemas.IssueLinkRelates})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = link(ui.ID, schemas.CreateIssueLinkInput{LinkedIssueID: secret.ID, LinkType: schemas.IssueLinkRelates})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/issues/%d/links/%d", setup.Server.GetURL(), ui.ID, fromUI[0].ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Len(t, links(api.ID), 1)
+	})
+}
+
+func TestIssueKeys(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should number issues per project and look them up by key", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		createProject := func(input schemas.CreateProjectInput) (*http.Response, db.Project) {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := client.Post(setup.Server.GetURL()+"/projects", "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var project db.Project
+			if resp.StatusCode == http.StatusCreated {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&project))
+			}
+			return resp, project
+		}
+
+		// Prefixes are derived from the name and numbered when taken
+		resp, acacia := createProject(schemas.CreateProjectInput{Name: "Acacia", TeamID: teamID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "ACA", acacia.KeyPrefix)
+		resp, web := createProject(schemas.CreateProjectInput{Name: "acacia web", TeamID: teamID})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "ACA2", web.KeyPrefix)
+
+		resp, _ = createProject(schemas.CreateProjectInput{Name: "Ops", TeamID: teamID, KeyPrefix: "ACA"})
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+		resp, _ = createProject(schemas.CreateProjectInput{Name: "Ops", TeamID: teamID, KeyPrefix: "1OPS"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(acacia.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		webColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(web.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		// Concurrent creates never share a number
+		var wg sync.WaitGroup
+		numbers := make(chan int32, 5)
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: column.ID})
+				if assert.NoError(t, err) {
+					numbers <- issue.Number
+				}
+			}()
+		}
+		wg.Wait()
+		close(numbers)
+		var allocated []int32
+		for number := range numbers {
+			allocated = append(allocated, number)
+		}
+		assert.ElementsMatch(t, []int32{1, 2, 3, 4, 5}, allocated)
+
+		webIssue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Web issue", ColumnID: webColumn.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), webIssue.Number)
+
+		// The database rejects a number the project already uses, but not one used by another project
+		_, err = setup.DB.DB.ExecContext(ctx, "UPDATE issues SET number = 2 WHERE id = $1", webIssue.ID)
+		require.NoError(t, err)
+		_, err = setup.DB.DB.ExecContext(ctx, "UPDATE issues SET column_id = $1 WHERE id = $2", column.ID, webIssue.ID)
+		var pqErr *pq.Error
+		require.ErrorAs(t, err, &pqErr)
+		assert.Equal(t, pq.ErrorCode(db.PgErrUniqueViolation), pqErr.Code)
+		_, err = setup.DB.DB.ExecContext(ctx, "UPDATE issues SET number = 1 WHERE id = $1", webIssue.ID)
+		require.NoError(t, err)
+
+		getByKey := func(key string) *http.Response {
+			resp, err := client.Get(fmt.Sprintf("%s/issues/key/%s", setup.Server.GetURL(), key))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp = getByKey("aca2-1")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var issue map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, "ACA2-1", issue["key"])
+		assert.Equal(t, float64(webIssue.ID), issue["id"])
+
+		assert.Equal(t, http.StatusNotFound, getByKey("ACA-6").StatusCode)
+		assert.Equal(t, http.StatusBadRequest, getByKey("ACA").StatusCode)
+
+		// The prefix is fixed once the project exists
+		updateProject := func(input schemas.UpdateProjectInput) *http.Response {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/projects/%d", setup.Server.GetURL(), acacia.ID), bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+		assert.Equal(t, http.StatusBadRequest, updateProject(schemas.UpdateProjectInput{Name: "Acacia", KeyPrefix: "NEW"}).StatusCode)
+		assert.Equal(t, http.StatusOK, updateProject(schemas.UpdateProjectInput{Name: "Acacia", KeyPrefix: "ACA"}).StatusCode)
+		assert.Equal(t, http.StatusOK, getByKey("ACA-1").StatusCode)
+
+		// Prefixes are unique per team, and another team's keys are not found
+		otherClient := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		otherTeamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user2.ID, "Team 2")
+		body, err := json.Marshal(schemas.CreateProjectInput{Name: "Other Acacia", TeamID: otherTeamID, KeyPrefix: "ACA"})
+		require.NoError(t, err)
+		resp, err = otherClient.Post(setup.Server.GetURL()+"/projects", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var otherProject db.Project
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&otherProject))
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(otherProject.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Other", ColumnID: otherColumn.ID})
+		require.NoError(t, err)
+		secret, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Secret", TeamID: otherTeamID, KeyPrefix: "SEC"})
+		require.NoError(t, err)
+		secretColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(secret.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Secret", ColumnID: secretColumn.ID})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusNotFound, getByKey("SEC-1").StatusCode)
+		resp = getByKey("ACA-1")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, float64(column.ID), issue["column_id"])
+
+		// Once in both teams the key is ambiguous
+		_, err = setup.Queries.AddTeamMember(ctx, db.AddTeamMemberParams{TeamID: otherTeamID, UserID: user.ID})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, getByKey("ACA-1").StatusCode)
+		assert.Equal(t, http.StatusOK, getByKey("SEC-1").StatusCode)
+	})
+}
+
+func TestBulkIssues(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should apply the operation per issue and roll back everything in all-or-nothing mode", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		var issueIDs []int64
+		for _, name := range []string{"First", "Second", "Third"} {
+			issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: name, ColumnID: columns[0].ID})
+			require.NoError(t, err)
+			issueIDs = append(issueIDs, issue.ID)
+		}
+
+		// An issue of another team fails without revealing it
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		otherTeamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user2.ID, "Team 2")
+		otherProject, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Secret", TeamID: otherTeamID, KeyPrefix: "SEC"})
+		require.NoError(t, err)
+		otherColumn, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(otherProject.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		secret, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Secret", ColumnID: otherColumn.ID})
+		require.NoError(t, err)
+
+		bulk := func(input schemas.BulkIssueInput) (int, schemas.BulkIssueResponse) {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := client.Post(setup.Server.GetURL()+"/issues/bulk", "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			var result schemas.BulkIssueResponse
+			if resp.StatusCode == http.StatusOK {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+			}
+			return resp.StatusCode, result
+		}
+		priorities := func() []string {
+			var out []string
+			for _, id := range issueIDs {
+				issue, err := setup.Queries.GetIssueByID(ctx, id)
+				require.NoError(t, err)
+				out = append(out, issue.Priority)
+			}
+			return out
+		}
+
+		status, _ := bulk(schemas.BulkIssueInput{IssueIDs: issueIDs, Operation: schemas.BulkIssueMove})
+		assert.Equal(t, http.StatusBadRequest, status)
+
+		// All-or-nothing: the inaccessible issue undoes the others
+		status, resp := bulk(schemas.BulkIssueInput{
+			IssueIDs:     append([]int64{secret.ID}, issueIDs...),
+			Operation:    schemas.BulkIssueSetPriority,
+			Priority:     schemas.IssuePriorityHigh,
+			AllOrNothing: true,
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.True(t, resp.RolledBack)
+		assert.Equal(t, 0, resp.Succeeded)
+		assert.Equal(t, 1, resp.Failed)
+		require.Len(t, resp.Results, 4)
+		assert.Equal(t, schemas.BulkIssueFailed, resp.Results[0].Status)
+		assert.Equal(t, "Issue not found", resp.Results[0].Error)
+		assert.Equal(t, schemas.BulkIssueRolledBack, resp.Results[1].Status)
+		assert.Equal(t, []string{"none", "none", "none"}, priorities())
+
+		// Best effort: the others are applied
+		status, resp = bulk(schemas.BulkIssueInput{
+			IssueIDs:  append([]int64{secret.ID}, issueIDs...),
+			Operation: schemas.BulkIssueSetPriority,
+			Priority:  schemas.IssuePriorityHigh,
+		})
+		require.Equal(t, http.StatusOK, status)
+		assert.False(t, resp.RolledBack)
+		assert.Equal(t, 3, resp.Succeeded)
+		assert.Equal(t, 1, resp.Failed)
+		assert.Equal(t, []string{"high", "high", "high"}, priorities())
+
+		// Moving every issue to Done records the moves
+		status, resp = bulk(schemas.BulkIssueInput{IssueIDs: issueIDs, Operation: schemas.BulkIssueMove, ColumnID: columns[1].ID})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 3, resp.Succeeded)
+		for _, id := range issueIDs {
+			issue, err := setup.Queries.GetIssueByID(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, columns[1].ID, issue.ColumnID)
+		}
+		activity, err := setup.Queries.GetIssueActivity(ctx, issueIDs[0])
+		require.NoError(t, err)
+		assert.Equal(t, schemas.IssueActivityMoved, activity[len(activity)-1].Action)
+
+		// Labels and assignees of the team only
+		label, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: teamID, Name: "bug", Color: "#d73a4a"})
+		require.NoError(t, err)
+		status, resp = bulk(schemas.BulkIssueInput{IssueIDs: issueIDs, Operation: schemas.BulkIssueLabel, LabelID: label.ID})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 3, resp.Succeeded)
+		labels, err := setup.Queries.GetIssueLabels(ctx, issueIDs[2])
+		require.NoError(t, err)
+		assert.Len(t, labels, 1)
+
+		status, resp = bulk(schemas.BulkIssueInput{IssueIDs: issueIDs[:1], Operation: schemas.BulkIssueAssign, UserID: user2.ID})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Assignees must be members of the project's team", resp.Results[0].Error)
+
+		// Archive and delete
+		status, resp = bulk(schemas.BulkIssueInput{IssueIDs: issueIDs[:2], Operation: schemas.BulkIssueArchive})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 2, resp.Succeeded)
+		archived, err := setup.Queries.GetIssueByID(ctx, issueIDs[0])
+		require.NoError(t, err)
+		assert.True(t, archived.ArchivedAt.Valid)
+
+		status, resp = bulk(schemas.BulkIssueInput{IssueIDs: issueIDs, Operation: schemas.BulkIssueDelete})
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 3, resp.Succeeded)
+		_, err = setup.Queries.GetIssueByID(ctx, issueIDs[2])
+		assert.Error(t, err)
+	})
+
+	t.Run("should not clear a priority the issue's column requires", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID:      int32(project.ID),
+			Name:           "Triaged",
+			RequiredFields: []string{schemas.ColumnRequiredFieldPriority},
+		})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+			Name:     "Triaged issue",
+			ColumnID: column.ID,
+			Priority: null.StringFrom(schemas.IssuePriorityHigh),
+		})
+		require.NoError(t, err)
+
+		body, err := json.Marshal(schemas.BulkIssueInput{
+			IssueIDs:  []int64{issue.ID},
+			Operation: schemas.BulkIssueSetPriority,
+			Priority:  schemas.IssuePriorityNone,
+		})
+		require.NoError(t, err)
+		resp, err := client.Post(setup.Server.GetURL()+"/issues/bulk", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var result schemas.BulkIssueResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, 1, result.Failed)
+		require.Len(t, result.Results, 1)
+		assert.Contains(t, result.Results[0].Error, "priority")
+
+		issue, err = setup.Queries.GetIssueByID(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Equal(t, schemas.IssuePriorityHigh, issue.Priority)
+	})
+}
+
+func TestMoveIssueToProject(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should move an issue into another accessible project with a new key", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		createProject := func(name string, prefix string, team int64, columnNames ...string) (db.Project, []db.ProjectStatusColumn) {
+			project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: name, TeamID: team, KeyPrefix: prefix})
+			require.NoError(t, err)
+			var columns []db.ProjectStatusColumn
+			for _, columnName := range columnNames {
+				column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+					ProjectID: int32(project.ID),
+					Name:      columnName,
+				})
+				require.NoError(t, err)
+				columns = append(columns, column)
+			}
+			return project, columns
+		}
+		_, webColumns := createProject("Web", "WEB", teamID, "To Do", "Doing")
+		api, apiColumns := createProject("API", "API", teamID, "Backlog", "doing")
+
+		parent, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Parent", ColumnID: webColumns[1].ID})
+		require.NoError(t, err)
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: webColumns[1].ID})
+		require.NoError(t, err)
+		child, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Child", ColumnID: webColumns[0].ID, ParentID: null.IntFrom(issue.ID)})
+		require.NoError(t, err)
+		_, err = setup.Queries.SetIssueParent(ctx, db.SetIssueParentParams{ID: issue.ID, ParentID: null.IntFrom(parent.ID)})
+		require.NoError(t, err)
+
+		post := func(url string, body interface{}) *http.Response {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			resp, err := client.Post(setup.Server.GetURL()+url, "application/json", bytes.NewBuffer(payload))
+			require.NoError(t, err)
+			return resp
+		}
+		put := func(body interface{}) *http.Response {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPut, setup.Server.GetURL()+"/issues", bytes.NewBuffer(payload))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			return resp
+		}
+
+		// PUT /issues no longer moves issues across projects
+		resp := put(schemas.UpdateIssueInput{ID: issue.ID, Name: "Issue", ColumnId: apiColumns[0].ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Nor into a project of another team
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		secret, secretColumns := createProject("Secret", "SEC", testutils.CreateTeamAndAddUser(t, ctx, setup, user2.ID, "Team 2"), "To Do")
+		resp = put(schemas.UpdateIssueInput{ID: issue.ID, Name: "Issue", ColumnId: secretColumns[0].ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = post(fmt.Sprintf("/issues/%d/move-to-project", issue.ID), schemas.MoveIssueToProjectInput{ProjectID: secret.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		resp = post(fmt.Sprintf("/issues/%d/move-to-project", issue.ID), schemas.MoveIssueToProjectInput{
+			ProjectID: api.ID,
+			ColumnID:  null.IntFrom(webColumns[0].ID),
+		})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Without a column the issue lands in the column with the same name
+		resp = post(fmt.Sprintf("/issues/%d/move-to-project", issue.ID), schemas.MoveIssueToProjectInput{ProjectID: api.ID})
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var moved db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&moved))
+		assert.Equal(t, apiColumns[1].ID, moved.ColumnID)
+		assert.False(t, moved.ParentID.Valid)
+
+		key, err := setup.Queries.GetIssueKey(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "API-1", key)
+
+		detached, err := setup.Queries.GetIssueByID(ctx, child.ID)
+		require.NoError(t, err)
+		assert.False(t, detached.ParentID.Valid)
+
+		activity, err := setup.Queries.GetIssueActivity(ctx, issue.ID)
+		require.NoError(t, err)
+		var projectMoves int
+		for _, entry := range activity {
+			if entry.Action == schemas.IssueActivityMoved && entry.Field.String == "project_id" {
+				projectMoves++
+			}
+		}
+		assert.Equal(t, 1, projectMoves)
+
+		resp = post(fmt.Sprintf("/issues/%d/move-to-project", issue.ID), schemas.MoveIssueToProjectInput{ProjectID: api.ID})
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should drop links to the old team's issues when moving to another team", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+
+		var columns []db.ProjectStatusColumn
+		for i, prefix := range []string{"ONE", "TWO"} {
+			teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, fmt.Sprintf("Team %d", i+1))
+			project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: prefix, TeamID: teamID, KeyPrefix: prefix})
+			require.NoError(t, err)
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      "To Do",
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+		blocker, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Blocker", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+		blocked, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Blocked", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssueLink(ctx, db.CreateIssueLinkParams{IssueID: blocker.ID, LinkedIssueID: issue.ID, LinkType: schemas.IssueLinkBlocks})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssueLink(ctx, db.CreateIssueLinkParams{IssueID: issue.ID, LinkedIssueID: blocked.ID, LinkType: schemas.IssueLinkBlocks})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssueLink(ctx, db.CreateIssueLinkParams{IssueID: blocker.ID, LinkedIssueID: blocked.ID, LinkType: schemas.IssueLinkRelates})
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(schemas.MoveIssueToProjectInput{ProjectID: int64(columns[1].ProjectID)})
+		require.NoError(t, err)
+		resp, err := client.Post(fmt.Sprintf("%s/issues/%d/move-to-project", setup.Server.GetURL(), issue.ID), "application/json", bytes.NewBuffer(payload))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		links, err := setup.Queries.GetIssueLinks(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Empty(t, links)
+		links, err = setup.Queries.GetIssueLinks(ctx, blocker.ID)
+		require.NoError(t, err)
+		require.Len(t, links, 1)
+		assert.Equal(t, blocked.ID, links[0].OtherIssueID)
+	})
+}
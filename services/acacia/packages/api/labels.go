@@ -0,0 +1,164 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+)
+
+type LabelsController struct {
+	queries   *db.Queries
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func NewLabelsController(queries *db.Queries, logger *logrus.Logger) *LabelsController {
+	return &LabelsController{
+		queries:   queries,
+		logger:    logger,
+		validator: validator.New(),
+	}
+}
+
+// CreateLabel creates a label for the team
+func (c *LabelsController) CreateLabel(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CreateLabelInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleLabelValidationErrors(err), http.StatusBadRequest)
+	}
+
+	label, err := c.queries.CreateLabel(r.Context(), db.CreateLabelParams{
+		TeamID: teamID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return httperr.WithStatus(errors.New("A label with this name already exists"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to create label")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(label)
+	return nil
+}
+
+// GetLabels returns all labels of the team ordered by name
+func (c *LabelsController) GetLabels(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+
+	labels, err := c.queries.GetLabelsByTeamID(r.Context(), teamID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get labels")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	// Ensure we always return an array, not null
+	if labels == nil {
+		labels = []db.Label{}
+	}
+
+	json.NewEncoder(w).Encode(labels)
+	return nil
+}
+
+// UpdateLabel renames or recolours one of the team's labels
+func (c *LabelsController) UpdateLabel(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+
+	labelID, err := strconv.ParseInt(chi.URLParam(r, "label_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid label ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.UpdateLabelInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleLabelValidationErrors(err), http.StatusBadRequest)
+	}
+
+	label, err := c.queries.UpdateLabel(r.Context(), db.UpdateLabelParams{
+		ID:     labelID,
+		TeamID: teamID,
+		Name:   req.Name,
+		Color:  req.Color,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Label not found"), http.StatusNotFound)
+		}
+		if isUniqueViolation(err) {
+			return httperr.WithStatus(errors.New("A label with this name already exists"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to update label")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(label)
+	return nil
+}
+
+// DeleteLabel deletes one of the team's labels and removes it from all issues
+func (c *LabelsController) DeleteLabel(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+
+	labelID, err := strconv.ParseInt(chi.URLParam(r, "label_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid label ID"), http.StatusBadRequest)
+	}
+
+	deleted, err := c.queries.DeleteLabel(r.Context(), db.DeleteLabelParams{
+		ID:     labelID,
+		TeamID: teamID,
+	})
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to delete label")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+	if deleted == 0 {
+		return httperr.WithStatus(errors.New("Label not found"), http.StatusNotFound)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == db.PgErrUniqueViolation
+}
@@ -0,0 +1,111 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamLabels(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should create, list, update and delete labels", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		labelsURL := fmt.Sprintf("%s/teams/%d/labels", setup.Server.GetURL(), teamID)
+
+		body, _ := json.Marshal(schemas.CreateLabelInput{Name: "bug", Color: "#d73a4a"})
+		resp, err := client.Post(labelsURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var label db.Label
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&label))
+		assert.Equal(t, teamID, label.TeamID)
+		assert.Equal(t, "bug", label.Name)
+
+		// Names are unique per team
+		resp, err = client.Post(labelsURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Colours must be hex
+		body, _ = json.Marshal(schemas.CreateLabelInput{Name: "feature", Color: "green"})
+		resp, err = client.Post(labelsURL, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		body, _ = json.Marshal(schemas.UpdateLabelInput{Name: "defect", Color: "#b60205"})
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/%d", labelsURL, label.ID), bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, err = client.Get(labelsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var labels []db.Label
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&labels))
+		require.Len(t, labels, 1)
+		assert.Equal(t, "defect", labels[0].Name)
+		assert.Equal(t, "#b60205", labels[0].Color)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", labelsURL, label.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", labelsURL, label.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("should return 403 for users outside the team", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "owner@example.com", "Owner", "password123")
+		owner, err := setup.Queries.GetUserByEmail(ctx, "owner@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, owner.ID, "Team 1")
+
+		outsider := testutils.CreateAuthenticatedClient(t, setup, "outsider@example.com", "Outsider", "password123")
+
+		resp, err := outsider.Get(fmt.Sprintf("%s/teams/%d/labels", setup.Server.GetURL(), teamID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
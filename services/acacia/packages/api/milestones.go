@@ -0,0 +1,238 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type MilestonesController struct {
+	queries          *db.Queries
+	logger           *logrus.Logger
+	validator        *validator.Validate
+	milestoneService *services.MilestoneService
+}
+
+func NewMilestonesController(queries *db.Queries, logger *logrus.Logger, database *sql.DB) *MilestonesController {
+	return &MilestonesController{
+		queries:          queries,
+		logger:           logger,
+		validator:        validator.New(),
+		milestoneService: services.NewMilestoneService(queries, database),
+	}
+}
+
+// GetMilestones returns the project's milestones by target date with their completion
+func (c *MilestonesController) GetMilestones(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	milestones, err := c.milestoneService.List(r.Context(), projectID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get milestones")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(milestones)
+	return nil
+}
+
+// GetMilestone returns one of the project's milestones with its completion
+func (c *MilestonesController) GetMilestone(w http.ResponseWriter, r *http.Request) error {
+	projectID, milestoneID, err := parseMilestoneURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	milestone, err := c.milestoneService.Get(r.Context(), projectID, milestoneID)
+	if err != nil {
+		if err := milestoneError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to get milestone")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(milestone)
+	return nil
+}
+
+// CreateMilestone adds a milestone to the project
+func (c *MilestonesController) CreateMilestone(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.MilestoneInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleMilestoneValidationErrors(err), http.StatusBadRequest)
+	}
+
+	milestone, err := c.milestoneService.Create(r.Context(), projectID, req)
+	if err != nil {
+		if err := milestoneError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to create milestone")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(milestone)
+	return nil
+}
+
+// UpdateMilestone replaces the name, description and target date of one of the project's milestones
+func (c *MilestonesController) UpdateMilestone(w http.ResponseWriter, r *http.Request) error {
+	projectID, milestoneID, err := parseMilestoneURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	var req schemas.MilestoneInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleMilestoneValidationErrors(err), http.StatusBadRequest)
+	}
+
+	milestone, err := c.milestoneService.Update(r.Context(), projectID, milestoneID, req)
+	if err != nil {
+		if err := milestoneError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to update milestone")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(milestone)
+	return nil
+}
+
+// DeleteMilestone deletes one of the project's milestones, keeping its issues
+func (c *MilestonesController) DeleteMilestone(w http.ResponseWriter, r *http.Request) error {
+	projectID, milestoneID, err := parseMilestoneURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	if err := c.milestoneService.Delete(r.Context(), projectID, milestoneID); err != nil {
+		if err := milestoneError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to delete milestone")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GetReleaseNotes returns the milestone's completed issues grouped by label.
+// The format query parameter selects json (the default) or markdown.
+func (c *MilestonesController) GetReleaseNotes(w http.ResponseWriter, r *http.Request) error {
+	projectID, milestoneID, err := parseMilestoneURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "markdown" {
+		return httperr.WithStatus(errors.New("format must be one of: json, markdown"), http.StatusBadRequest)
+	}
+
+	notes, err := c.milestoneService.ReleaseNotes(r.Context(), projectID, milestoneID)
+	if err != nil {
+		if err := milestoneError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to generate release notes")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	if format == "markdown" {
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+		io.WriteString(w, services.ReleaseNotesMarkdown(notes))
+		return nil
+	}
+
+	json.NewEncoder(w).Encode(notes)
+	return nil
+}
+
+// SetIssueMilestone assigns the issue to a milestone of its project or removes it from its milestone
+func (c *MilestonesController) SetIssueMilestone(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.SetIssueMilestoneInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	issue, err := c.milestoneService.SetIssueMilestone(r.Context(), issueID, userID, req.MilestoneID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrMilestoneNotFound):
+			return httperr.WithStatus(errors.New("Milestone must be in the issue's project"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to set issue milestone")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(issue)
+	return nil
+}
+
+func parseMilestoneURLParams(r *http.Request) (int64, int64, error) {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+	milestoneID, err := strconv.ParseInt(chi.URLParam(r, "milestone_id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid milestone ID"), http.StatusBadRequest)
+	}
+	return projectID, milestoneID, nil
+}
+
+func milestoneError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMilestoneNotFound):
+		return httperr.WithStatus(errors.New("Milestone not found"), http.StatusNotFound)
+	case errors.Is(err, services.ErrMilestoneTaken):
+		return httperr.WithStatus(errors.New("A milestone with this name already exists"), http.StatusConflict)
+	}
+	return nil
+}
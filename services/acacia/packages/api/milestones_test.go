@@ -0,0 +1,159 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMilestones(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should track milestone completion and generate release notes", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		done, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+			IsDone:    true,
+		})
+		require.NoError(t, err)
+
+		milestonesURL := fmt.Sprintf("%s/projects/%d/milestones", setup.Server.GetURL(), project.ID)
+		body := `{"name": "v1.0", "description": "First public release", "target_date": "2025-06-30T00:00:00Z"}`
+		resp, err := client.Post(milestonesURL, "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var milestone schemas.MilestoneSummary
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&milestone))
+		assert.Equal(t, int64(0), milestone.PercentComplete)
+
+		resp, err = client.Post(milestonesURL, "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		bug, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: teamID, Name: "Bug", Color: "#ff0000"})
+		require.NoError(t, err)
+		feature, err := setup.Queries.CreateLabel(ctx, db.CreateLabelParams{TeamID: teamID, Name: "Feature", Color: "#00ff00"})
+		require.NoError(t, err)
+
+		issues := make([]db.Issue, 0, 4)
+		for _, spec := range []struct {
+			name     string
+			columnID int64
+			labels   []int64
+		}{
+			{"Fix login crash", done.ID, []int64{bug.ID}},
+			{"Add dark mode", done.ID, []int64{feature.ID, bug.ID}},
+			{"Update *copyright* [year]\n# 2025", done.ID, nil},
+			{"Add export", todo.ID, []int64{feature.ID}},
+		} {
+			issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: spec.name, ColumnID: spec.columnID})
+			require.NoError(t, err)
+			for _, labelID := range spec.labels {
+				_, err = setup.Queries.AddIssueLabel(ctx, db.AddIssueLabelParams{IssueID: issue.ID, LabelID: labelID})
+				require.NoError(t, err)
+			}
+			issues = append(issues, issue)
+		}
+
+		setMilestone := func(issueID int64, body string) *http.Response {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues/%d/milestone", setup.Server.GetURL(), issueID), bytes.NewBufferString(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		for _, issue := range issues {
+			resp = setMilestone(issue.ID, fmt.Sprintf(`{"milestone_id": %d}`, milestone.ID))
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var updated db.Issue
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&updated))
+			assert.Equal(t, null.IntFrom(milestone.ID), updated.MilestoneID)
+		}
+
+		other, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Other", TeamID: teamID, KeyPrefix: "OTH"})
+		require.NoError(t, err)
+		otherMilestone, err := setup.Queries.CreateMilestone(ctx, db.CreateMilestoneParams{ProjectID: other.ID, Name: "v1.0"})
+		require.NoError(t, err)
+		resp = setMilestone(issues[0].ID, fmt.Sprintf(`{"milestone_id": %d}`, otherMilestone.ID))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Get(fmt.Sprintf("%s/%d", milestonesURL, milestone.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&milestone))
+		assert.Equal(t, int64(4), milestone.IssueCount)
+		assert.Equal(t, int64(3), milestone.DoneIssueCount)
+		assert.Equal(t, int64(75), milestone.PercentComplete)
+
+		// Release notes group completed issues by label, unlabeled issues last
+		notesURL := fmt.Sprintf("%s/%d/release-notes", milestonesURL, milestone.ID)
+		resp, err = client.Get(notesURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var notes schemas.ReleaseNotes
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&notes))
+		require.Len(t, notes.Groups, 3)
+		assert.Equal(t, "Bug", notes.Groups[0].Label.Name)
+		assert.Len(t, notes.Groups[0].Issues, 2)
+		assert.Equal(t, "Feature", notes.Groups[1].Label.Name)
+		require.Len(t, notes.Groups[1].Issues, 1)
+		assert.Equal(t, "Add dark mode", notes.Groups[1].Issues[0].Name)
+		assert.Nil(t, notes.Groups[2].Label)
+		require.Len(t, notes.Groups[2].Issues, 1)
+		assert.Equal(t, schemas.IssueKey("PRJ", issues[2].Number), notes.Groups[2].Issues[0].Key)
+
+		resp, err = client.Get(notesURL + "?format=markdown")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/markdown")
+		markdown, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(markdown), "# v1.0\n")
+		assert.Contains(t, string(markdown), "3 of 4 issues done (75%)")
+		assert.Contains(t, string(markdown), fmt.Sprintf("## Feature\n\n- PRJ-%d Add dark mode\n", issues[1].Number))
+		assert.Contains(t, string(markdown), fmt.Sprintf("## Other changes\n\n- PRJ-%d Update \\*copyright\\* \\[year\\] \\# 2025\n", issues[2].Number))
+		assert.NotContains(t, string(markdown), "Add export")
+
+		resp, err = client.Get(notesURL + "?format=pdf")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
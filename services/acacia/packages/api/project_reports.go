@@ -0,0 +1,135 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/llm"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type ProjectReportsController struct {
+	queries       *db.Queries
+	logger        *logrus.Logger
+	validator     *validator.Validate
+	reportService *services.ProjectReportService
+}
+
+func NewProjectReportsController(
+	queries *db.Queries,
+	logger *logrus.Logger,
+	providers *services.TeamProviderResolver,
+) *ProjectReportsController {
+	return &ProjectReportsController{
+		queries:       queries,
+		logger:        logger,
+		validator:     validator.New(),
+		reportService: services.NewProjectReportService(queries, providers, logger),
+	}
+}
+
+func (c *ProjectReportsController) GenerateProjectReport(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.GenerateProjectReportInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleProjectReportValidationErrors(err), http.StatusBadRequest)
+	}
+
+	report, err := c.reportService.Generate(r.Context(), projectID, userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidReportWindow):
+			return httperr.WithStatus(errors.New("Report window must end after it starts and span at most 90 days"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrAPIKeyNotFound):
+			return httperr.WithStatus(errors.New("No API key configured for this provider"), http.StatusBadRequest)
+		case errors.Is(err, llm.ErrProviderNotSupported), errors.Is(err, llm.ErrStructuredOutputNotSupported):
+			return httperr.WithStatus(errors.New("Provider does not support status reports"), http.StatusBadRequest)
+		case errors.Is(err, llm.ErrInvalidAPIKey):
+			return httperr.WithStatus(errors.New("The team's API key was rejected by the provider"), http.StatusBadGateway)
+		case errors.Is(err, llm.ErrRateLimitExceeded):
+			return httperr.WithStatus(errors.New("Provider rate limit exceeded"), http.StatusTooManyRequests)
+		case errors.Is(err, services.ErrInvalidModelResponse):
+			return httperr.WithStatus(errors.New("The model returned an invalid response"), http.StatusBadGateway)
+		}
+		c.logger.WithError(err).Error("Failed to generate project report")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+	return nil
+}
+
+func (c *ProjectReportsController) GetProjectReports(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	reports, err := c.queries.GetProjectReportsByProjectID(r.Context(), projectID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get project reports")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	// Ensure we always return an array, not null
+	if reports == nil {
+		reports = []db.ProjectReport{}
+	}
+
+	json.NewEncoder(w).Encode(reports)
+	return nil
+}
+
+func (c *ProjectReportsController) GetProjectReportByID(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	reportID, err := strconv.ParseInt(chi.URLParam(r, "report_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid report ID"), http.StatusBadRequest)
+	}
+
+	report, err := c.queries.GetProjectReportByID(r.Context(), db.GetProjectReportByIDParams{
+		ID:        reportID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Report not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to get project report")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(report)
+	return nil
+}
@@ -0,0 +1,193 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectReports(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should list and read stored reports", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		now := time.Now().UTC()
+		report, err := setup.Queries.CreateProjectReport(ctx, db.CreateProjectReportParams{
+			ProjectID:   project.ID,
+			CreatedBy:   null.IntFrom(user.ID),
+			Provider:    "openai",
+			Model:       "gpt-4o-mini",
+			PeriodStart: now.Add(-7 * 24 * time.Hour),
+			PeriodEnd:   now,
+			Summary:     "Steady progress",
+			Done:        "Login page",
+			InProgress:  "Signup flow",
+			Blocked:     "None.",
+			Risks:       "None.",
+		})
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("%s/projects/%d/reports", setup.Server.GetURL(), project.ID)
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var reports []db.ProjectReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reports))
+		require.Len(t, reports, 1)
+		assert.Equal(t, report.ID, reports[0].ID)
+
+		url = fmt.Sprintf("%s/projects/%d/reports/%d", setup.Server.GetURL(), project.ID, report.ID)
+		resp, err = client.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var fetched db.ProjectReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&fetched))
+		assert.Equal(t, "Steady progress", fetched.Summary)
+		assert.Equal(t, "Signup flow", fetched.InProgress)
+	})
+
+	t.Run("should not allow reading reports of other teams' projects", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		_ = testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user1, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		team1ID := testutils.CreateTeamAndAddUser(t, ctx, setup, user1.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		client2 := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+
+		url := fmt.Sprintf("%s/projects/%d/reports", setup.Server.GetURL(), project.ID)
+		resp, err := client2.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("should return 400 for an invalid report window", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+
+		now := time.Now().UTC()
+		body, _ := json.Marshal(schemas.GenerateProjectReportInput{
+			Provider: "openai",
+			Model:    "gpt-4o-mini",
+			Since:    null.TimeFrom(now),
+			Until:    null.TimeFrom(now.Add(-time.Hour)),
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/report", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should generate and store a report", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+		testutils.CreateTeamLLMAPIKey(t, setup, client, teamID, testutils.FakeLLMProviderName)
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Team 1 Project",
+			TeamID:    teamID,
+			KeyPrefix: "TP",
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+		})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+			Name:     "Ship login page",
+			ColumnID: column.ID,
+		})
+		require.NoError(t, err)
+
+		setup.LLM.ScriptStructured(`{"summary": "Login shipped", "done": "Login page", "in_progress": "None.", "blocked": "None.", "risks": "None."}`)
+
+		body, _ := json.Marshal(schemas.GenerateProjectReportInput{
+			Provider: testutils.FakeLLMProviderName,
+			Model:    "fake-model",
+		})
+
+		url := fmt.Sprintf("%s/projects/%d/report", setup.Server.GetURL(), project.ID)
+		resp, err := client.Post(url, "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var report db.ProjectReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		assert.Equal(t, "Login shipped", report.Summary)
+		assert.Equal(t, user.ID, report.CreatedBy.Int64)
+
+		requests := setup.LLM.Requests()
+		require.Len(t, requests, 1)
+		assert.Contains(t, requests[0].Messages[len(requests[0].Messages)-1].Content, "Ship login page")
+
+		stored, err := setup.Queries.GetProjectReportsByProjectID(ctx, project.ID)
+		require.NoError(t, err)
+		assert.Len(t, stored, 1)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"acacia/packages/auth"
 	"acacia/packages/db"
 	"acacia/packages/httperr"
 	"acacia/packages/schemas"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
 	"github.com/sirupsen/logrus"
 )
 
@@ -55,6 +57,28 @@ func (c *ProjectStatusColumnsController) GetProjectStatusColumnsByProjectID(w ht
 	return nil
 }
 
+// GetProjectStatusColumnByID returns the column with its ETag for conditional updates
+func (c *ProjectStatusColumnsController) GetProjectStatusColumnByID(w http.ResponseWriter, r *http.Request) error {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
+	}
+
+	column, err := c.queries.GetProjectStatusColumnByID(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to get project status column by ID")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	setETag(w, column.UpdatedAt)
+	json.NewEncoder(w).Encode(column)
+	return nil
+}
+
 func (c *ProjectStatusColumnsController) CreateProjectStatusColumn(w http.ResponseWriter, r *http.Request) error {
 	var req schemas.CreateProjectStatusColumnInput
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -66,8 +90,11 @@ func (c *ProjectStatusColumnsController) CreateProjectStatusColumn(w http.Respon
 	}
 
 	params := db.CreateProjectStatusColumnParams{
-		ProjectID: req.ProjectID,
-		Name:      req.Name,
+		ProjectID:      req.ProjectID,
+		Name:           req.Name,
+		WipLimit:       wipLimitParam(req.WIPLimit),
+		RequiredFields: req.RequiredFields,
+		IsDone:         req.IsDone,
 	}
 
 	column, err := c.queries.CreateProjectStatusColumn(r.Context(), params)
@@ -98,36 +125,149 @@ func (c *ProjectStatusColumnsController) UpdateProjectStatusColumn(w http.Respon
 	}
 
 	params := db.UpdateProjectStatusColumnParams{
-		ID:            id,
-		Name:          req.Name,
-		PositionIndex: req.PositionIndex,
+		ID:             id,
+		Name:           req.Name,
+		PositionIndex:  req.PositionIndex,
+		WipLimit:       wipLimitParam(req.WIPLimit),
+		RequiredFields: req.RequiredFields,
+		IsDone:         req.IsDone,
+		IfUpdatedAt:    ifMatch(r),
 	}
 
 	column, err := c.queries.UpdateProjectStatusColumn(r.Context(), params)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if params.IfUpdatedAt != nil && c.writeColumnChanged(w, r, id) {
+				return nil
+			}
 			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
 		}
 		c.logger.WithError(err).Error("Failed to update project status column")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
 
+	setETag(w, column.UpdatedAt)
 	json.NewEncoder(w).Encode(column)
 	return nil
 }
 
-func (c *ProjectStatusColumnsController) DeleteProjectStatusColumn(w http.ResponseWriter, r *http.Request) error {
+// writeColumnChanged answers a change refused by its If-Match with the column as it is now.
+// It reports false, writing nothing, when the column does not exist.
+func (c *ProjectStatusColumnsController) writeColumnChanged(w http.ResponseWriter, r *http.Request, columnID int64) bool {
+	column, err := c.queries.GetProjectStatusColumnByID(r.Context(), columnID)
+	if err != nil {
+		return false
+	}
+
+	writePreconditionFailed(w, column, column.UpdatedAt)
+	return true
+}
+
+func (c *ProjectStatusColumnsController) MoveProjectStatusColumn(w http.ResponseWriter, r *http.Request) error {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
 		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
 	}
 
-	_, err = c.projectStatusColumnService.DeleteProjectStatusColumnWithReorder(r.Context(), id)
+	var req schemas.MoveProjectStatusColumnInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("Validation failed: "+err.Error()), http.StatusBadRequest)
+	}
+
+	columns, err := c.projectStatusColumnService.MoveProjectStatusColumn(r.Context(), id, *req.PositionIndex)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
+		}
+		if errors.Is(err, services.ErrInvalidColumnPosition) {
+			return httperr.WithStatus(err, http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to move project status column")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(columns)
+	return nil
+}
+
+// GetColumnTransitions returns the columns issues may move to from the column
+func (c *ProjectStatusColumnsController) GetColumnTransitions(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
+	}
+
+	columns, err := c.projectStatusColumnService.GetTransitions(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to get column transitions")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(columns)
+	return nil
+}
+
+// SetColumnTransitions replaces the columns issues may move to from the column
+func (c *ProjectStatusColumnsController) SetColumnTransitions(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.SetColumnTransitionsInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(errors.New("Validation failed: "+err.Error()), http.StatusBadRequest)
+	}
+
+	columns, err := c.projectStatusColumnService.SetTransitions(r.Context(), id, req.ToColumnIDs)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
 		}
+		if errors.Is(err, services.ErrInvalidTransition) {
+			return httperr.WithStatus(errors.New("Transition targets must be other columns of the same project"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to set column transitions")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(columns)
+	return nil
+}
+
+func (c *ProjectStatusColumnsController) DeleteProjectStatusColumn(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid column ID"), http.StatusBadRequest)
+	}
+
+	ifUpdatedAt := ifMatch(r)
+	_, err = c.projectStatusColumnService.DeleteProjectStatusColumnWithReorder(r.Context(), id, userID, ifUpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if ifUpdatedAt != nil && c.writeColumnChanged(w, r, id) {
+				return nil
+			}
+			return httperr.WithStatus(errors.New("Project status column not found"), http.StatusNotFound)
+		}
 		if errors.Is(err, services.ErrCannotDeleteLastColumn) {
 			return httperr.WithStatus(err, http.StatusBadRequest)
 		}
@@ -138,3 +278,11 @@ func (c *ProjectStatusColumnsController) DeleteProjectStatusColumn(w http.Respon
 	w.WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+// wipLimitParam converts an optional WIP limit from a request into its nullable column value
+func wipLimitParam(limit *int32) null.Int {
+	if limit == nil {
+		return null.Int{}
+	}
+	return null.IntFrom(int64(*limit))
+}
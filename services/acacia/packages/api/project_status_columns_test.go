@@ -1,12 +1,16 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 
 	"acacia/packages/db"
+	"acacia/packages/schemas"
 	"acacia/packages/testutils"
 
 	"github.com/stretchr/testify/assert"
@@ -32,8 +36,9 @@ func TestDeleteProjectStatusColumn(t *testing.T) {
 
 		// Create a test project first
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Test Project",
-			TeamID: teamID,
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
 		})
 		require.NoError(t, err)
 
@@ -87,8 +92,9 @@ func TestDeleteProjectStatusColumn(t *testing.T) {
 
 		// Create a test project
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Test Project",
-			TeamID: teamID,
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
 		})
 		require.NoError(t, err)
 
@@ -174,8 +180,9 @@ func TestDeleteProjectStatusColumn(t *testing.T) {
 
 		// Create a test project
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Test Project",
-			TeamID: teamID,
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
 		})
 		require.NoError(t, err)
 
@@ -237,8 +244,9 @@ func TestDeleteProjectStatusColumn(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -277,3 +285,395 @@ func TestDeleteProjectStatusColumn(t *testing.T) {
 	})
 }
 
+func TestMoveProjectStatusColumn(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	setupColumns := func(t *testing.T, setup *testutils.IntegrationTestSetup, email string) (*http.Client, []db.ProjectStatusColumn) {
+		client := testutils.CreateAuthenticatedClient(t, setup, email, "Test User", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, email)
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Test Team")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
+		})
+		require.NoError(t, err)
+
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "In Progress", "Review", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		return client, columns
+	}
+
+	moveColumn := func(t *testing.T, client *http.Client, url string, columnID int64, position int16) *http.Response {
+		body, err := json.Marshal(schemas.MoveProjectStatusColumnInput{PositionIndex: &position})
+		require.NoError(t, err)
+		resp, err := client.Post(fmt.Sprintf("%s/project-columns/%d/move", url, columnID), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("should reorder all columns in the project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, columns := setupColumns(t, setup, "move1@example.com")
+
+		resp := moveColumn(t, client, setup.Server.GetURL(), columns[3].ID, 1)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var reordered []db.ProjectStatusColumn
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reordered))
+		require.Len(t, reordered, 4)
+
+		expected := []int64{columns[0].ID, columns[3].ID, columns[1].ID, columns[2].ID}
+		for i, column := range reordered {
+			assert.Equal(t, expected[i], column.ID)
+			assert.Equal(t, int16(i), column.PositionIndex)
+		}
+	})
+
+	t.Run("should return 400 for a position outside the project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, columns := setupColumns(t, setup, "move2@example.com")
+
+		resp := moveColumn(t, client, setup.Server.GetURL(), columns[0].ID, 4)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should serialise concurrent moves", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, columns := setupColumns(t, setup, "move3@example.com")
+
+		var wg sync.WaitGroup
+		for i, column := range columns {
+			wg.Add(1)
+			go func(columnID int64, position int16) {
+				defer wg.Done()
+				body, _ := json.Marshal(schemas.MoveProjectStatusColumnInput{PositionIndex: &position})
+				resp, err := client.Post(fmt.Sprintf("%s/project-columns/%d/move", setup.Server.GetURL(), columnID), "application/json", bytes.NewBuffer(body))
+				if assert.NoError(t, err) {
+					assert.Equal(t, http.StatusOK, resp.StatusCode)
+					resp.Body.Close()
+				}
+			}(column.ID, int16(len(columns)-1-i))
+		}
+		wg.Wait()
+
+		// Whatever the interleaving, positions stay a permutation of 0..n-1
+		reordered, err := setup.Queries.GetProjectStatusColumnsByProjectID(ctx, columns[0].ProjectID)
+		require.NoError(t, err)
+		require.Len(t, reordered, len(columns))
+		for i, column := range reordered {
+			assert.Equal(t, int16(i), column.PositionIndex)
+		}
+	})
+}
+
+func TestColumnWIPLimits(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// setupBoard creates a project with a "To Do" column and a "Doing" column limited to one issue
+	setupBoard := func(t *testing.T, setup *testutils.IntegrationTestSetup, email string) (*http.Client, db.Project, db.ProjectStatusColumn, db.ProjectStatusColumn) {
+		client := testutils.CreateAuthenticatedClient(t, setup, email, "Test User", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, email)
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Test Team")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, schemas.WIPLimitModeSoft, project.WipLimitMode)
+
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		limit := int32(1)
+		body, _ := json.Marshal(schemas.CreateProjectStatusColumnInput{
+			ProjectID: int32(project.ID),
+			Name:      "Doing",
+			WIPLimit:  &limit,
+		})
+		resp, err := client.Post(setup.Server.GetURL()+"/project-columns", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var doing db.ProjectStatusColumn
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doing))
+		require.True(t, doing.WipLimit.Valid)
+		assert.Equal(t, int64(1), doing.WipLimit.Int64)
+
+		return client, project, todo, doing
+	}
+
+	createIssue := func(t *testing.T, client *http.Client, url string, columnID int64, name string) *http.Response {
+		description := ""
+		body, _ := json.Marshal(schemas.CreateIssueInput{
+			Name:        name,
+			Description: &description,
+			ColumnId:    columnID,
+		})
+		resp, err := client.Post(url+"/issues", "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	t.Run("should warn and flag the column when a soft limit is exceeded", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, project, _, doing := setupBoard(t, setup, "wip1@example.com")
+		url := setup.Server.GetURL()
+
+		resp := createIssue(t, client, url, doing.ID, "First")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var first schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&first))
+		assert.Nil(t, first.WIPWarning)
+
+		resp = createIssue(t, client, url, doing.ID, "Second")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var second schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&second))
+		require.NotNil(t, second.WIPWarning)
+		assert.Equal(t, doing.ID, second.WIPWarning.ColumnID)
+		assert.Equal(t, int64(1), second.WIPWarning.WIPLimit)
+		assert.Equal(t, int64(2), second.WIPWarning.IssueCount)
+
+		resp, err := client.Get(fmt.Sprintf("%s/projects/%d/details", url, project.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var details schemas.GetProjectDetailsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+		require.Len(t, details.Columns, 2)
+		assert.Equal(t, int64(0), details.Columns[0].IssueCount)
+		assert.False(t, details.Columns[0].OverWIPLimit)
+		assert.Equal(t, int64(2), details.Columns[1].IssueCount)
+		assert.True(t, details.Columns[1].OverWIPLimit)
+	})
+
+	t.Run("should reject creating or moving into a full column with a hard limit", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, project, todo, doing := setupBoard(t, setup, "wip2@example.com")
+		url := setup.Server.GetURL()
+
+		body, _ := json.Marshal(schemas.UpdateProjectInput{Name: project.Name, WIPLimitMode: schemas.WIPLimitModeHard})
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/projects/%d", url, project.ID), bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = createIssue(t, client, url, doing.ID, "In progress")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = createIssue(t, client, url, doing.ID, "One too many")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = createIssue(t, client, url, todo.ID, "Waiting")
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var waiting db.Issue
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&waiting))
+
+		body, _ = json.Marshal(schemas.MoveIssueInput{ColumnID: doing.ID})
+		resp, err = client.Post(fmt.Sprintf("%s/issues/%d/move", url, waiting.ID), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Reordering within the full column is still allowed
+		issues, err := setup.Queries.GetIssuesByColumnId(ctx, doing.ID)
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		body, _ = json.Marshal(schemas.MoveIssueInput{ColumnID: doing.ID})
+		resp, err = client.Post(fmt.Sprintf("%s/issues/%d/move", url, issues[0].ID), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("should reject a WIP limit below one", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client, _, todo, _ := setupBoard(t, setup, "wip3@example.com")
+
+		limit := int32(0)
+		body, _ := json.Marshal(schemas.UpdateProjectStatusColumnInput{Name: todo.Name, PositionIndex: todo.PositionIndex, WIPLimit: &limit})
+		req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/project-columns/%d", setup.Server.GetURL(), todo.ID), bytes.NewBuffer(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestColumnWorkflowRules(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should enforce allowed transitions and required fields", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "workflow1@example.com", "Test User", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "workflow1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Test Team")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      "Test Project",
+			TeamID:    teamID,
+			KeyPrefix: "TST",
+		})
+		require.NoError(t, err)
+
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "In Progress", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		todo, inProgress, done := columns[0], columns[1], columns[2]
+		url := setup.Server.GetURL()
+
+		put := func(target string, payload any) *http.Response {
+			body, _ := json.Marshal(payload)
+			req, err := http.NewRequest(http.MethodPut, target, bytes.NewBuffer(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		// To Do may only move to In Progress
+		transitionsURL := fmt.Sprintf("%s/project-columns/%d/transitions", url, todo.ID)
+		resp := put(transitionsURL, schemas.SetColumnTransitionsInput{ToColumnIDs: []int64{inProgress.ID}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = put(transitionsURL, schemas.SetColumnTransitionsInput{ToColumnIDs: []int64{todo.ID}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = client.Get(transitionsURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var allowed []db.ProjectStatusColumn
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&allowed))
+		require.Len(t, allowed, 1)
+		assert.Equal(t, inProgress.ID, allowed[0].ID)
+
+		// In Progress requires an assignee
+		resp = put(fmt.Sprintf("%s/project-columns/%d", url, inProgress.ID), schemas.UpdateProjectStatusColumnInput{
+			Name:           inProgress.Name,
+			PositionIndex:  inProgress.PositionIndex,
+			RequiredFields: []string{schemas.ColumnRequiredFieldAssignee},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{
+			Name:     "Workflow issue",
+			ColumnID: todo.ID,
+		})
+		require.NoError(t, err)
+
+		moveURL := fmt.Sprintf("%s/issues/%d/move", url, issue.ID)
+		move := func(columnID int64) *http.Response {
+			body, _ := json.Marshal(schemas.MoveIssueInput{ColumnID: columnID})
+			resp, err := client.Post(moveURL, "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp = move(done.ID)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		resp = put(fmt.Sprintf("%s/issues", url), schemas.UpdateIssueInput{ID: issue.ID, Name: issue.Name, ColumnId: done.ID})
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+
+		resp = move(inProgress.ID)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		var errResp map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Contains(t, errResp["message"], "assignee")
+
+		body, _ := json.Marshal(schemas.AssignIssueInput{UserID: user.ID})
+		resp, err = client.Post(fmt.Sprintf("%s/issues/%d/assignees", url, issue.ID), "application/json", bytes.NewBuffer(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = move(inProgress.ID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// The last assignee cannot be removed while the column requires one
+		unassign, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/issues/%d/assignees/%d", url, issue.ID, user.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(unassign)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		assignees, err := setup.Queries.GetIssueAssignees(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Len(t, assignees, 1)
+
+		// A field the column starts requiring must be filled before the issue can be edited in place
+		resp = put(fmt.Sprintf("%s/project-columns/%d", url, inProgress.ID), schemas.UpdateProjectStatusColumnInput{
+			Name:           inProgress.Name,
+			PositionIndex:  inProgress.PositionIndex,
+			RequiredFields: []string{schemas.ColumnRequiredFieldAssignee, schemas.ColumnRequiredFieldPriority},
+		})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = put(fmt.Sprintf("%s/issues", url), schemas.UpdateIssueInput{ID: issue.ID, Name: "Renamed"})
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+		resp = put(fmt.Sprintf("%s/issues", url), schemas.UpdateIssueInput{ID: issue.ID, Name: "Renamed", Priority: schemas.IssuePriorityHigh})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// In Progress has no rules of its own, so the issue can move anywhere from there
+		resp = move(done.ID)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
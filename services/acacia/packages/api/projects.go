@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -11,6 +12,7 @@ import (
 	"acacia/packages/db"
 	"acacia/packages/httperr"
 	"acacia/packages/schemas"
+	"acacia/packages/services"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -18,16 +20,18 @@ import (
 )
 
 type ProjectsController struct {
-	queries   *db.Queries
-	logger    *logrus.Logger
-	validator *validator.Validate
+	queries        *db.Queries
+	logger         *logrus.Logger
+	validator      *validator.Validate
+	projectService *services.ProjectService
 }
 
 func NewProjectsController(queries *db.Queries, logger *logrus.Logger) *ProjectsController {
 	return &ProjectsController{
-		queries:   queries,
-		logger:    logger,
-		validator: validator.New(),
+		queries:        queries,
+		logger:         logger,
+		validator:      validator.New(),
+		projectService: services.NewProjectService(queries),
 	}
 }
 
@@ -47,6 +51,7 @@ func (c *ProjectsController) GetProjectByID(w http.ResponseWriter, r *http.Reque
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
 
+	setETag(w, project.UpdatedAt)
 	json.NewEncoder(w).Encode(project)
 	return nil
 }
@@ -61,13 +66,11 @@ func (c *ProjectsController) CreateProject(w http.ResponseWriter, r *http.Reques
 		return httperr.WithStatus(errors.New("Validation failed: "+err.Error()), http.StatusBadRequest)
 	}
 
-	params := db.CreateProjectParams{
-		Name:   req.Name,
-		TeamID: req.TeamID,
-	}
-
-	project, err := c.queries.CreateProject(r.Context(), params)
+	project, err := c.projectService.Create(r.Context(), req)
 	if err != nil {
+		if err := keyPrefixError(err); err != nil {
+			return err
+		}
 		c.logger.WithError(err).Error("Failed to create project")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
@@ -93,24 +96,39 @@ func (c *ProjectsController) UpdateProject(w http.ResponseWriter, r *http.Reques
 		return httperr.WithStatus(errors.New("Validation failed: "+err.Error()), http.StatusBadRequest)
 	}
 
-	params := db.UpdateProjectParams{
-		ID:   id,
-		Name: req.Name,
-	}
-
-	project, err := c.queries.UpdateProject(r.Context(), params)
+	ifUpdatedAt := ifMatch(r)
+	project, err := c.projectService.Update(r.Context(), id, req, ifUpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if ifUpdatedAt != nil && c.writeProjectChanged(w, r, id) {
+				return nil
+			}
 			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
 		}
+		if err := keyPrefixError(err); err != nil {
+			return err
+		}
 		c.logger.WithError(err).Error("Failed to update project")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
 
+	setETag(w, project.UpdatedAt)
 	json.NewEncoder(w).Encode(project)
 	return nil
 }
 
+// writeProjectChanged answers a change refused by its If-Match with the project as it is now.
+// It reports false, writing nothing, when the project does not exist.
+func (c *ProjectsController) writeProjectChanged(w http.ResponseWriter, r *http.Request, projectID int64) bool {
+	project, err := c.queries.GetProjectByID(r.Context(), projectID)
+	if err != nil {
+		return false
+	}
+
+	writePreconditionFailed(w, project, project.UpdatedAt)
+	return true
+}
+
 func (c *ProjectsController) GetProjectDetailsByID(w http.ResponseWriter, r *http.Request) error {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -118,41 +136,35 @@ func (c *ProjectsController) GetProjectDetailsByID(w http.ResponseWriter, r *htt
 		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
 	}
 
-	project, err := c.queries.GetProjectByID(r.Context(), id)
+	query := r.URL.Query()
+	issueFilter, err := parseIssueFilter(query)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
-		}
-		c.logger.WithError(err).Error("Failed to get project details by ID")
-		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+		return err
 	}
-
-	projectColumns, err := c.queries.GetProjectStatusColumnsByProjectID(r.Context(), int32(id))
-	if err != nil {
-		c.logger.WithError(err).Error("Failed to get project columns")
-		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	filter := schemas.ProjectIssueFilter{
+		IssueFilter:      issueFilter,
+		CustomFieldValue: query.Get("custom_field_value"),
+	}
+	if filter.CustomFieldID, err = parseOptionalIntParam(query.Get("custom_field_id")); err != nil {
+		return httperr.WithStatus(errors.New("Invalid custom field ID"), http.StatusBadRequest)
 	}
 
-	projectIssues, err := c.queries.GetProjectIssues(r.Context(), int32(id))
+	resp, err := c.projectService.GetDetails(r.Context(), id, filter)
 	if err != nil {
-		c.logger.WithError(err).Error("Failed to get project issues")
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
+		}
+		if errors.Is(err, services.ErrCustomFieldNotFound) {
+			return httperr.WithStatus(errors.New("Custom field not found in this project"), http.StatusBadRequest)
+		}
+		var fieldErr *services.CustomFieldError
+		if errors.As(err, &fieldErr) {
+			return httperr.WithStatus(fieldErr, http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to get project details by ID")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
 	}
 
-	// Ensure we always return arrays, not null
-	if projectColumns == nil {
-		projectColumns = []db.ProjectStatusColumn{}
-	}
-	if projectIssues == nil {
-		projectIssues = []db.Issue{}
-	}
-
-	resp := schemas.GetProjectDetailsResponse{
-		Project: project,
-		Columns: projectColumns,
-		Issues:  projectIssues,
-	}
-
 	json.NewEncoder(w).Encode(resp)
 	return nil
 }
@@ -164,7 +176,15 @@ func (c *ProjectsController) GetProjects(w http.ResponseWriter, r *http.Request)
 		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
 	}
 
-	projects, err := c.queries.GetProjects(r.Context(), userID)
+	includeArchived, err := parseIncludeArchived(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	projects, err := c.queries.GetProjects(r.Context(), db.GetProjectsParams{
+		UserID:          userID,
+		IncludeArchived: includeArchived,
+	})
 	if err != nil {
 		c.logger.WithError(err).Error("Failed to get projects")
 		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
@@ -187,9 +207,16 @@ func (c *ProjectsController) DeleteProject(w http.ResponseWriter, r *http.Reques
 		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
 	}
 
-	_, err = c.queries.DeleteProject(r.Context(), id)
+	ifUpdatedAt := ifMatch(r)
+	_, err = c.queries.DeleteProject(r.Context(), db.DeleteProjectParams{
+		ID:          id,
+		IfUpdatedAt: ifUpdatedAt,
+	})
 	if err != nil {
 		if err == sql.ErrNoRows {
+			if ifUpdatedAt != nil && c.writeProjectChanged(w, r, id) {
+				return nil
+			}
 			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
 		}
 		c.logger.WithError(err).Error("Failed to delete project")
@@ -199,3 +226,45 @@ func (c *ProjectsController) DeleteProject(w http.ResponseWriter, r *http.Reques
 	w.WriteHeader(http.StatusNoContent)
 	return nil
 }
+
+// ArchiveProject hides the project from default listings and makes it read-only
+func (c *ProjectsController) ArchiveProject(w http.ResponseWriter, r *http.Request) error {
+	return c.setProjectArchived(w, r, c.queries.ArchiveProject)
+}
+
+// UnarchiveProject returns an archived project to the default listings and makes it writable again
+func (c *ProjectsController) UnarchiveProject(w http.ResponseWriter, r *http.Request) error {
+	return c.setProjectArchived(w, r, c.queries.UnarchiveProject)
+}
+
+func (c *ProjectsController) setProjectArchived(w http.ResponseWriter, r *http.Request, set func(context.Context, int64) (db.Project, error)) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	project, err := set(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Project not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to set project archived state")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(project)
+	return nil
+}
+
+// keyPrefixError maps an invalid, already used or changed key prefix to its HTTP error
+func keyPrefixError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidKeyPrefix):
+		return httperr.WithStatus(errors.New("Key prefix must be 2 to 10 upper-case letters and digits starting with a letter"), http.StatusBadRequest)
+	case errors.Is(err, services.ErrKeyPrefixTaken):
+		return httperr.WithStatus(errors.New("Key prefix is already used by another project in the team"), http.StatusConflict)
+	case errors.Is(err, services.ErrKeyPrefixImmutable):
+		return httperr.WithStatus(errors.New("Key prefix cannot be changed"), http.StatusBadRequest)
+	}
+	return nil
+}
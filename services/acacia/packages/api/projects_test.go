@@ -382,8 +382,9 @@ func TestGetProjects(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -413,8 +414,9 @@ func TestGetProjects(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -454,8 +456,9 @@ func TestGetProjects(t *testing.T) {
 
 		// Create a project for team 1
 		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{
-			Name:   "Team 1 Project",
-			TeamID: team1ID,
+			Name:      "Team 1 Project",
+			TeamID:    team1ID,
+			KeyPrefix: "TP",
 		})
 		require.NoError(t, err)
 
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+type SprintsController struct {
+	queries       *db.Queries
+	logger        *logrus.Logger
+	validator     *validator.Validate
+	sprintService *services.SprintService
+}
+
+func NewSprintsController(queries *db.Queries, logger *logrus.Logger, database *sql.DB) *SprintsController {
+	return &SprintsController{
+		queries:       queries,
+		logger:        logger,
+		validator:     validator.New(),
+		sprintService: services.NewSprintService(queries, database),
+	}
+}
+
+// GetSprints returns the project's sprints by start date with their issue counts
+func (c *SprintsController) GetSprints(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	sprints, err := c.sprintService.List(r.Context(), projectID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get sprints")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(sprints)
+	return nil
+}
+
+// CreateSprint plans a sprint in the project
+func (c *SprintsController) CreateSprint(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.SprintInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleSprintValidationErrors(err), http.StatusBadRequest)
+	}
+
+	sprint, err := c.sprintService.Create(r.Context(), projectID, req)
+	if err != nil {
+		if err := sprintError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to create sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sprint)
+	return nil
+}
+
+// UpdateSprint replaces the name, goal and dates of one of the project's sprints
+func (c *SprintsController) UpdateSprint(w http.ResponseWriter, r *http.Request) error {
+	projectID, sprintID, err := parseSprintURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	var req schemas.SprintInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleSprintValidationErrors(err), http.StatusBadRequest)
+	}
+
+	sprint, err := c.sprintService.Update(r.Context(), projectID, sprintID, req)
+	if err != nil {
+		if err := sprintError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to update sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(sprint)
+	return nil
+}
+
+// DeleteSprint deletes one of the project's sprints and returns its issues to the backlog
+func (c *SprintsController) DeleteSprint(w http.ResponseWriter, r *http.Request) error {
+	projectID, sprintID, err := parseSprintURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	if err := c.sprintService.Delete(r.Context(), projectID, sprintID); err != nil {
+		if err := sprintError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to delete sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// StartSprint makes a planned sprint the project's active sprint
+func (c *SprintsController) StartSprint(w http.ResponseWriter, r *http.Request) error {
+	projectID, sprintID, err := parseSprintURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	sprint, err := c.sprintService.Start(r.Context(), projectID, sprintID)
+	if err != nil {
+		if err := sprintError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to start sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(sprint)
+	return nil
+}
+
+// CompleteSprint completes the active sprint and moves its unfinished issues to the next planned sprint
+// or the backlog
+func (c *SprintsController) CompleteSprint(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	projectID, sprintID, err := parseSprintURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	// The body is optional
+	var req schemas.CompleteSprintInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+		}
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleSprintValidationErrors(err), http.StatusBadRequest)
+	}
+
+	completed, err := c.sprintService.Complete(r.Context(), projectID, sprintID, userID, req.MoveTo)
+	if err != nil {
+		if err := sprintError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to complete sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(completed)
+	return nil
+}
+
+// SetIssueSprint assigns the issue to a sprint of its project or returns it to the backlog
+func (c *SprintsController) SetIssueSprint(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.SetIssueSprintInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	issue, err := c.sprintService.SetIssueSprint(r.Context(), issueID, userID, req.SprintID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrSprintNotFound):
+			return httperr.WithStatus(errors.New("Sprint must be in the issue's project"), http.StatusBadRequest)
+		case errors.Is(err, services.ErrSprintCompleted):
+			return httperr.WithStatus(errors.New("Issues cannot be added to a completed sprint"), http.StatusBadRequest)
+		}
+		c.logger.WithError(err).Error("Failed to set issue sprint")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(issue)
+	return nil
+}
+
+func parseSprintURLParams(r *http.Request) (int64, int64, error) {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+	sprintID, err := strconv.ParseInt(chi.URLParam(r, "sprint_id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid sprint ID"), http.StatusBadRequest)
+	}
+	return projectID, sprintID, nil
+}
+
+func sprintError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrSprintNotFound):
+		return httperr.WithStatus(errors.New("Sprint not found"), http.StatusNotFound)
+	case errors.Is(err, services.ErrSprintDates):
+		return httperr.WithStatus(errors.New("A sprint cannot end before it starts"), http.StatusBadRequest)
+	case errors.Is(err, services.ErrSprintAlreadyActive):
+		return httperr.WithStatus(errors.New("The project already has an active sprint; complete it first"), http.StatusConflict)
+	case errors.Is(err, services.ErrSprintNotPlanned):
+		return httperr.WithStatus(errors.New("Only a planned sprint can be started"), http.StatusConflict)
+	case errors.Is(err, services.ErrSprintNotActive):
+		return httperr.WithStatus(errors.New("Only an active sprint can be completed"), http.StatusConflict)
+	}
+	return nil
+}
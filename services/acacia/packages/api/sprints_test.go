@@ -0,0 +1,170 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprints(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should plan, start and complete sprints and carry unfinished issues over", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		todo, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		done, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Done",
+			IsDone:    true,
+		})
+		require.NoError(t, err)
+
+		sprintsURL := fmt.Sprintf("%s/projects/%d/sprints", setup.Server.GetURL(), project.ID)
+
+		createSprint := func(body string) (*http.Response, schemas.SprintSummary) {
+			resp, err := client.Post(sprintsURL, "application/json", bytes.NewBufferString(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var sprint schemas.SprintSummary
+			if resp.StatusCode == http.StatusCreated {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&sprint))
+			}
+			return resp, sprint
+		}
+
+		resp, first := createSprint(`{"name": "Sprint 1", "goal": "Ship the MVP", "start_date": "2025-03-03T00:00:00Z", "end_date": "2025-03-14T00:00:00Z"}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, schemas.SprintPlanned, first.Status)
+		assert.Equal(t, null.StringFrom("Ship the MVP"), first.Goal)
+
+		resp, second := createSprint(`{"name": "Sprint 2", "start_date": "2025-03-17T00:00:00Z", "end_date": "2025-03-28T00:00:00Z"}`)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp, _ = createSprint(`{"name": "Backwards", "start_date": "2025-03-17T00:00:00Z", "end_date": "2025-03-03T00:00:00Z"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		finished, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Finished", ColumnID: done.ID, EstimatePoints: null.IntFrom(3)})
+		require.NoError(t, err)
+		unfinished, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Unfinished", ColumnID: todo.ID, EstimatePoints: null.IntFrom(5)})
+		require.NoError(t, err)
+
+		setSprint := func(issueID int64, body string) *http.Response {
+			req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/issues/%d/sprint", setup.Server.GetURL(), issueID), bytes.NewBufferString(body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		for _, issue := range []db.Issue{finished, unfinished} {
+			resp = setSprint(issue.ID, fmt.Sprintf(`{"sprint_id": %d}`, first.ID))
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		// Only one sprint can be active at a time
+		post := func(url string, body string) *http.Response {
+			resp, err := client.Post(url, "application/json", bytes.NewBufferString(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		resp = post(fmt.Sprintf("%s/%d/start", sprintsURL, first.ID), "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var started schemas.SprintSummary
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&started))
+		assert.Equal(t, schemas.SprintActive, started.Status)
+		assert.True(t, started.StartedAt.Valid)
+
+		resp = post(fmt.Sprintf("%s/%d/start", sprintsURL, second.ID), "")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		resp = post(fmt.Sprintf("%s/%d/complete", sprintsURL, second.ID), "")
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		// Project details report each sprint's progress
+		resp, err = client.Get(fmt.Sprintf("%s/projects/%d/details", setup.Server.GetURL(), project.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var details schemas.GetProjectDetailsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&details))
+		require.Len(t, details.Sprints, 2)
+		assert.Equal(t, first.ID, details.Sprints[0].ID)
+		assert.Equal(t, int64(2), details.Sprints[0].IssueCount)
+		assert.Equal(t, int64(1), details.Sprints[0].DoneIssueCount)
+		assert.Equal(t, int64(8), details.Sprints[0].Points)
+		assert.Equal(t, int64(3), details.Sprints[0].DonePoints)
+		for _, issue := range details.Issues {
+			assert.Equal(t, null.IntFrom(first.ID), issue.SprintID)
+		}
+
+		// Completing moves unfinished issues to the next planned sprint
+		resp = post(fmt.Sprintf("%s/%d/complete", sprintsURL, first.ID), "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var completed schemas.CompletedSprint
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&completed))
+		assert.Equal(t, schemas.SprintCompleted, completed.Sprint.Status)
+		assert.Equal(t, 1, completed.MovedIssueCount)
+		assert.Equal(t, null.IntFrom(second.ID), completed.MovedToSprintID)
+		assert.Equal(t, int64(1), completed.Sprint.IssueCount)
+
+		moved, err := setup.Queries.GetIssueByID(ctx, unfinished.ID)
+		require.NoError(t, err)
+		assert.Equal(t, null.IntFrom(second.ID), moved.SprintID)
+
+		activity, err := setup.Queries.GetIssueActivity(ctx, unfinished.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, activity)
+		last := activity[len(activity)-1]
+		assert.Equal(t, null.StringFrom("sprint_id"), last.Field)
+		assert.Equal(t, null.StringFrom(fmt.Sprint(second.ID)), last.NewValue)
+
+		resp = setSprint(unfinished.ID, fmt.Sprintf(`{"sprint_id": %d}`, first.ID))
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Or to the backlog
+		resp = post(fmt.Sprintf("%s/%d/start", sprintsURL, second.ID), "")
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = post(fmt.Sprintf("%s/%d/complete", sprintsURL, second.ID), `{"move_to": "backlog"}`)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&completed))
+		assert.Equal(t, 1, completed.MovedIssueCount)
+		assert.False(t, completed.MovedToSprintID.Valid)
+
+		moved, err = setup.Queries.GetIssueByID(ctx, unfinished.ID)
+		require.NoError(t, err)
+		assert.False(t, moved.SprintID.Valid)
+
+		resp = post(fmt.Sprintf("%s/%d/complete", sprintsURL, second.ID), `{"move_to": "elsewhere"}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+	"github.com/sirupsen/logrus"
+)
+
+type TimeEntriesController struct {
+	queries          *db.Queries
+	logger           *logrus.Logger
+	validator        *validator.Validate
+	timeEntryService *services.TimeEntryService
+}
+
+func NewTimeEntriesController(queries *db.Queries, logger *logrus.Logger) *TimeEntriesController {
+	return &TimeEntriesController{
+		queries:          queries,
+		logger:           logger,
+		validator:        validator.New(),
+		timeEntryService: services.NewTimeEntryService(queries),
+	}
+}
+
+// GetIssueTimeEntries returns the time logged on the issue oldest first together with its estimates
+func (c *TimeEntriesController) GetIssueTimeEntries(w http.ResponseWriter, r *http.Request) error {
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	entries, err := c.timeEntryService.List(r.Context(), issueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return httperr.WithStatus(errors.New("Issue not found"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to get time entries")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(entries)
+	return nil
+}
+
+// CreateTimeEntry logs time the current user spent on the issue
+func (c *TimeEntriesController) CreateTimeEntry(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	var req schemas.CreateTimeEntryInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleTimeEntryValidationErrors(err), http.StatusBadRequest)
+	}
+
+	entry, err := c.timeEntryService.Log(r.Context(), issueID, userID, req)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to log time")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+	return nil
+}
+
+// StartTimer starts a timer on the issue for the current user
+func (c *TimeEntriesController) StartTimer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	// The body is optional
+	var req schemas.StartTimerInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return httperr.WithStatus(errors.New("Invalid JSON"), http.StatusBadRequest)
+		}
+	}
+
+	if err := c.validator.Struct(&req); err != nil {
+		return httperr.WithStatus(schemas.HandleTimeEntryValidationErrors(err), http.StatusBadRequest)
+	}
+
+	entry, err := c.timeEntryService.Start(r.Context(), issueID, userID, req.Note)
+	if err != nil {
+		var running *services.TimerRunningError
+		if errors.As(err, &running) {
+			return httperr.WithStatus(fmt.Errorf("You already have a timer running on %s; stop it first", running.IssueKey), http.StatusConflict)
+		}
+		if errors.Is(err, services.ErrTimerRunning) {
+			return httperr.WithStatus(errors.New("You already have a timer running; stop it first"), http.StatusConflict)
+		}
+		c.logger.WithError(err).Error("Failed to start timer")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+	return nil
+}
+
+// StopTimer stops the current user's timer on the issue
+func (c *TimeEntriesController) StopTimer(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+
+	entry, err := c.timeEntryService.Stop(r.Context(), issueID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNoRunningTimer) {
+			return httperr.WithStatus(errors.New("No timer is running on this issue"), http.StatusNotFound)
+		}
+		c.logger.WithError(err).Error("Failed to stop timer")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(entry)
+	return nil
+}
+
+// DeleteTimeEntry deletes time the current user logged on the issue
+func (c *TimeEntriesController) DeleteTimeEntry(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	issueID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid issue ID"), http.StatusBadRequest)
+	}
+	entryID, err := strconv.ParseInt(chi.URLParam(r, "entry_id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid time entry ID"), http.StatusBadRequest)
+	}
+
+	if err := c.timeEntryService.Delete(r.Context(), issueID, entryID, userID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTimeEntryNotFound):
+			return httperr.WithStatus(errors.New("Time entry not found"), http.StatusNotFound)
+		case errors.Is(err, services.ErrTimeEntryNotLogger):
+			return httperr.WithStatus(errors.New("Only the user who logged time can delete it"), http.StatusForbidden)
+		}
+		c.logger.WithError(err).Error("Failed to delete time entry")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// GetProjectTimeReport returns the time logged on the project grouped by user and week.
+// The optional from (inclusive) and to (exclusive) query parameters are YYYY-MM-DD dates.
+func (c *TimeEntriesController) GetProjectTimeReport(w http.ResponseWriter, r *http.Request) error {
+	projectID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid project ID"), http.StatusBadRequest)
+	}
+
+	query := r.URL.Query()
+	var from, to null.Time
+	dateParams := map[string]*null.Time{
+		"from": &from,
+		"to":   &to,
+	}
+	for name, target := range dateParams {
+		if *target, err = parseOptionalDateParam(query.Get(name)); err != nil {
+			return httperr.WithStatus(fmt.Errorf("Invalid %s: expected YYYY-MM-DD date", name), http.StatusBadRequest)
+		}
+	}
+
+	report, err := c.timeEntryService.Report(r.Context(), projectID, from, to)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get time report")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(report)
+	return nil
+}
@@ -0,0 +1,237 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeTracking(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should log time, run timers and report by user and week", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		teammate := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+		user2, err := setup.Queries.GetUserByEmail(ctx, "user2@example.com")
+		require.NoError(t, err)
+		_, err = setup.Queries.AddTeamMember(ctx, db.AddTeamMemberParams{TeamID: teamID, UserID: user2.ID})
+		require.NoError(t, err)
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+
+		// Estimates are set when creating an issue and must not be negative
+		body := fmt.Sprintf(`{"name": "Issue", "description": "", "column_id": %d, "estimate_points": 5, "estimate_minutes": 240}`, column.ID)
+		resp, err := client.Post(setup.Server.GetURL()+"/issues", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var issue schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&issue))
+		assert.Equal(t, null.IntFrom(5), issue.EstimatePoints)
+		assert.Equal(t, null.IntFrom(240), issue.EstimateMinutes)
+
+		body = fmt.Sprintf(`{"name": "Negative", "description": "", "column_id": %d, "estimate_points": -1}`, column.ID)
+		resp, err = client.Post(setup.Server.GetURL()+"/issues", "application/json", bytes.NewBufferString(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Other", ColumnID: column.ID, EstimatePoints: null.IntFrom(3)})
+		require.NoError(t, err)
+
+		entriesURL := fmt.Sprintf("%s/issues/%d/time-entries", setup.Server.GetURL(), issue.ID)
+
+		logTime := func(c *http.Client, input schemas.CreateTimeEntryInput) (*http.Response, schemas.TimeEntry) {
+			body, err := json.Marshal(input)
+			require.NoError(t, err)
+			resp, err := c.Post(entriesURL, "application/json", bytes.NewBuffer(body))
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			var entry schemas.TimeEntry
+			if resp.StatusCode == http.StatusCreated {
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&entry))
+			}
+			return resp, entry
+		}
+
+		monday := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+		resp, entry := logTime(client, schemas.CreateTimeEntryInput{Minutes: 90, StartedAt: null.TimeFrom(monday), Note: "Investigation"})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, null.IntFrom(90), entry.Minutes)
+		assert.Equal(t, monday.Add(90*time.Minute), entry.EndedAt.Time.UTC())
+		assert.False(t, entry.Running)
+
+		resp, _ = logTime(client, schemas.CreateTimeEntryInput{Minutes: 30, StartedAt: null.TimeFrom(monday.AddDate(0, 0, 4))})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp, _ = logTime(client, schemas.CreateTimeEntryInput{Minutes: 60, StartedAt: null.TimeFrom(monday.AddDate(0, 0, 7))})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		resp, teammateEntry := logTime(teammate, schemas.CreateTimeEntryInput{Minutes: 45, StartedAt: null.TimeFrom(monday.AddDate(0, 0, 1))})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp, _ = logTime(client, schemas.CreateTimeEntryInput{Minutes: 0})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		// Only one timer runs per user, and stopping it records its minutes
+		timerURL := fmt.Sprintf("%s/issues/%d/timer", setup.Server.GetURL(), issue.ID)
+		resp, err = client.Post(timerURL+"/start", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var timer schemas.TimeEntry
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&timer))
+		assert.True(t, timer.Running)
+
+		resp, err = client.Post(timerURL+"/start", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+		var errResp map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+		assert.Contains(t, errResp["message"], fmt.Sprintf("PRJ-%d", issue.Number))
+
+		resp, err = client.Post(timerURL+"/stop", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&timer))
+		assert.False(t, timer.Running)
+		assert.Equal(t, null.IntFrom(0), timer.Minutes)
+
+		resp, err = client.Post(timerURL+"/stop", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// Entries can only be deleted by the user who logged them
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", entriesURL, teammateEntry.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		req, err = http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/%d", entriesURL, timer.ID), nil)
+		require.NoError(t, err)
+		resp, err = client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		resp, err = client.Get(entriesURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var entries schemas.IssueTimeEntries
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&entries))
+		assert.Len(t, entries.Entries, 4)
+		assert.Equal(t, int64(225), entries.LoggedMinutes)
+		assert.Equal(t, null.IntFrom(5), entries.EstimatePoints)
+
+		reportURL := fmt.Sprintf("%s/projects/%d/time-report", setup.Server.GetURL(), project.ID)
+		resp, err = client.Get(reportURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var report schemas.ProjectTimeReport
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.Len(t, report.Users, 2)
+		assert.Equal(t, "User 1", report.Users[0].User.Name)
+		assert.Equal(t, []schemas.TimeReportWeek{
+			{WeekStart: "2025-03-03", Minutes: 120},
+			{WeekStart: "2025-03-10", Minutes: 60},
+		}, report.Users[0].Weeks)
+		assert.Equal(t, int64(180), report.Users[0].LoggedMinutes)
+		assert.Equal(t, int64(45), report.Users[1].LoggedMinutes)
+		assert.Equal(t, schemas.ProjectTimeTotal{
+			IssueCount:          2,
+			EstimatedIssueCount: 2,
+			EstimatePoints:      8,
+			EstimateMinutes:     240,
+			LoggedMinutes:       225,
+		}, report.Totals)
+
+		resp, err = client.Get(reportURL + "?from=2025-03-10")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+		require.Len(t, report.Users, 1)
+		assert.Equal(t, int64(60), report.Totals.LoggedMinutes)
+
+		resp, err = client.Get(reportURL + "?to=March")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should stop the running timer of a deleted issue", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "To Do",
+		})
+		require.NoError(t, err)
+		deleted, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Deleted", ColumnID: column.ID})
+		require.NoError(t, err)
+		other, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Other", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		startTimer := func(issueID int64) int {
+			resp, err := client.Post(fmt.Sprintf("%s/issues/%d/timer/start", setup.Server.GetURL(), issueID), "application/json", nil)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		require.Equal(t, http.StatusCreated, startTimer(deleted.ID))
+		req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), deleted.ID), nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		assert.Equal(t, http.StatusCreated, startTimer(other.ID))
+		entries, err := setup.Queries.GetTimeEntriesByIssueID(ctx, deleted.ID)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.True(t, entries[0].EndedAt.Valid)
+	})
+}
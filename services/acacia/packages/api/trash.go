@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/httperr"
+	"acacia/packages/services"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type TrashController struct {
+	queries         *db.Queries
+	logger          *logrus.Logger
+	trashService    *services.TrashService
+	activityService *services.IssueActivityService
+}
+
+func NewTrashController(queries *db.Queries, logger *logrus.Logger, trashService *services.TrashService) *TrashController {
+	return &TrashController{
+		queries:         queries,
+		logger:          logger,
+		trashService:    trashService,
+		activityService: services.NewIssueActivityService(queries),
+	}
+}
+
+// GetTrash returns the team's deleted projects, columns and issues with the time each will be purged
+func (c *TrashController) GetTrash(w http.ResponseWriter, r *http.Request) error {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+
+	trash, err := c.trashService.List(r.Context(), teamID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get trash")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(trash)
+	return nil
+}
+
+// RestoreProject takes a deleted project out of the team's trash together with its columns and issues
+func (c *TrashController) RestoreProject(w http.ResponseWriter, r *http.Request) error {
+	teamID, projectID, err := parseTrashURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	project, err := c.trashService.RestoreProject(r.Context(), teamID, projectID)
+	if err != nil {
+		if err := trashError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to restore project")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(project)
+	return nil
+}
+
+// RestoreColumn takes a deleted column out of the team's trash and puts it back at its old position
+func (c *TrashController) RestoreColumn(w http.ResponseWriter, r *http.Request) error {
+	teamID, columnID, err := parseTrashURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	column, err := c.trashService.RestoreColumn(r.Context(), teamID, columnID)
+	if err != nil {
+		if err := trashError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to restore column")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(column)
+	return nil
+}
+
+// RestoreIssue takes a deleted issue out of the team's trash, warning when its column goes over a soft WIP limit
+func (c *TrashController) RestoreIssue(w http.ResponseWriter, r *http.Request) error {
+	userID, ok := auth.GetUserID(r)
+	if !ok {
+		return httperr.WithStatus(errors.New("Unauthorized"), http.StatusUnauthorized)
+	}
+
+	teamID, issueID, err := parseTrashURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	issue, err := c.trashService.RestoreIssue(r.Context(), teamID, issueID, userID)
+	if err != nil {
+		if err := trashError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to restore issue")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(issue)
+	return nil
+}
+
+// GetIssueActivity returns the history of a deleted issue in the team's trash, which the issue routes no longer reach
+func (c *TrashController) GetIssueActivity(w http.ResponseWriter, r *http.Request) error {
+	teamID, issueID, err := parseTrashURLParams(r)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.trashService.GetIssue(r.Context(), teamID, issueID); err != nil {
+		if err := trashError(err); err != nil {
+			return err
+		}
+		c.logger.WithError(err).Error("Failed to get deleted issue")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	activity, err := c.activityService.List(r.Context(), issueID)
+	if err != nil {
+		c.logger.WithError(err).Error("Failed to get issue activity")
+		return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+	}
+
+	json.NewEncoder(w).Encode(activity)
+	return nil
+}
+
+func parseTrashURLParams(r *http.Request) (int64, int64, error) {
+	teamID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid team ID"), http.StatusBadRequest)
+	}
+	itemID, err := strconv.ParseInt(chi.URLParam(r, "item_id"), 10, 64)
+	if err != nil {
+		return 0, 0, httperr.WithStatus(errors.New("Invalid item ID"), http.StatusBadRequest)
+	}
+	return teamID, itemID, nil
+}
+
+func trashError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrTrashItemNotFound):
+		return httperr.WithStatus(errors.New("Item not found in trash"), http.StatusNotFound)
+	case errors.Is(err, services.ErrTrashParentGone):
+		return httperr.WithStatus(errors.New("The item's project or column is deleted; restore it first"), http.StatusConflict)
+	case errors.Is(err, services.ErrWIPLimitExceeded):
+		return httperr.WithStatus(errors.New("Column has reached its WIP limit"), http.StatusConflict)
+	case errors.Is(err, auth.ErrProjectArchived):
+		return httperr.WithStatus(auth.ErrProjectArchived, http.StatusForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/testutils"
+
+	"github.com/guregu/null"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("should keep deleted items in the team trash until they are restored", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		outsider := testutils.CreateAuthenticatedClient(t, setup, "user2@example.com", "User 2", "password123")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "Doing", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+
+		del := func(url string) int {
+			req, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+		restore := func(c *http.Client, kind string, id int64) int {
+			url := fmt.Sprintf("%s/teams/%d/trash/%s/%d/restore", setup.Server.GetURL(), teamID, kind, id)
+			resp, err := c.Post(url, "application/json", nil)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+		getTrash := func() schemas.Trash {
+			resp, err := client.Get(fmt.Sprintf("%s/teams/%d/trash", setup.Server.GetURL(), teamID))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var trash schemas.Trash
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&trash))
+			return trash
+		}
+
+		// A deleted issue disappears from the project but stays in the trash
+		require.Equal(t, http.StatusNoContent, del(fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), issue.ID)))
+		resp, err := client.Get(fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), issue.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		trash := getTrash()
+		assert.Equal(t, 30, trash.RetentionDays)
+		require.Len(t, trash.Issues, 1)
+		assert.Equal(t, issue.ID, trash.Issues[0].ID)
+		assert.Equal(t, trash.Issues[0].DeletedAt.AddDate(0, 0, 30), trash.Issues[0].PurgeAt)
+
+		// Its history stays readable through the trash, for team members only
+		activityURL := fmt.Sprintf("%s/teams/%d/trash/issues/%d/activity", setup.Server.GetURL(), teamID, issue.ID)
+		resp, err = outsider.Get(activityURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		resp, err = client.Get(activityURL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var history []schemas.IssueActivity
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&history))
+		require.NotEmpty(t, history)
+		assert.Equal(t, schemas.IssueActivityDeleted, history[len(history)-1].Action)
+
+		assert.Equal(t, http.StatusForbidden, restore(outsider, "issues", issue.ID))
+		require.Equal(t, http.StatusOK, restore(client, "issues", issue.ID))
+		assert.Equal(t, http.StatusNotFound, restore(client, "issues", issue.ID))
+
+		restored, err := setup.Queries.GetIssueByID(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Equal(t, columns[0].ID, restored.ColumnID)
+		activity, err := setup.Queries.GetIssueActivity(ctx, issue.ID)
+		require.NoError(t, err)
+		assert.Equal(t, schemas.IssueActivityRestored, activity[len(activity)-1].Action)
+
+		// A restored column goes back to its old position
+		require.Equal(t, http.StatusNoContent, del(fmt.Sprintf("%s/project-columns/%d", setup.Server.GetURL(), columns[1].ID)))
+		live, err := setup.Queries.GetProjectStatusColumnsByProjectID(ctx, int32(project.ID))
+		require.NoError(t, err)
+		require.Len(t, live, 2)
+		require.Len(t, getTrash().Columns, 1)
+
+		require.Equal(t, http.StatusOK, restore(client, "columns", columns[1].ID))
+		live, err = setup.Queries.GetProjectStatusColumnsByProjectID(ctx, int32(project.ID))
+		require.NoError(t, err)
+		require.Len(t, live, 3)
+		for i, column := range live {
+			assert.Equal(t, columns[i].ID, column.ID)
+			assert.Equal(t, int16(i), column.PositionIndex)
+		}
+
+		// Issues cannot come back while their project is deleted
+		require.Equal(t, http.StatusNoContent, del(fmt.Sprintf("%s/issues/%d", setup.Server.GetURL(), issue.ID)))
+		require.Equal(t, http.StatusNoContent, del(fmt.Sprintf("%s/projects/%d", setup.Server.GetURL(), project.ID)))
+
+		trash = getTrash()
+		require.Len(t, trash.Projects, 1)
+		assert.Empty(t, trash.Issues)
+
+		resp, err = client.Get(setup.Server.GetURL() + "/projects")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var projects []db.Project
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&projects))
+		assert.Empty(t, projects)
+
+		assert.Equal(t, http.StatusConflict, restore(client, "issues", issue.ID))
+		require.Equal(t, http.StatusOK, restore(client, "projects", project.ID))
+		require.Equal(t, http.StatusOK, restore(client, "issues", issue.ID))
+		assert.Empty(t, getTrash().Issues)
+	})
+
+	t.Run("should not restore an issue into a column at its WIP limit", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		project, err = setup.Queries.UpdateProject(ctx, db.UpdateProjectParams{
+			ID:           project.ID,
+			Name:         project.Name,
+			WipLimitMode: null.StringFrom(schemas.WIPLimitModeHard),
+		})
+		require.NoError(t, err)
+		column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+			ProjectID: int32(project.ID),
+			Name:      "Doing",
+			WipLimit:  null.IntFrom(1),
+		})
+		require.NoError(t, err)
+		deleted, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Deleted", ColumnID: column.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.DeleteIssue(ctx, db.DeleteIssueParams{ID: deleted.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Taking the slot", ColumnID: column.ID})
+		require.NoError(t, err)
+
+		restore := func() *http.Response {
+			url := fmt.Sprintf("%s/teams/%d/trash/issues/%d/restore", setup.Server.GetURL(), teamID, deleted.ID)
+			resp, err := client.Post(url, "application/json", nil)
+			require.NoError(t, err)
+			t.Cleanup(func() { resp.Body.Close() })
+			return resp
+		}
+
+		assert.Equal(t, http.StatusConflict, restore().StatusCode)
+		_, err = setup.Queries.GetDeletedIssue(ctx, db.GetDeletedIssueParams{ID: deleted.ID, TeamID: teamID})
+		require.NoError(t, err)
+
+		// Soft limits restore the issue with a warning
+		_, err = setup.Queries.UpdateProject(ctx, db.UpdateProjectParams{
+			ID:           project.ID,
+			Name:         project.Name,
+			WipLimitMode: null.StringFrom(schemas.WIPLimitModeSoft),
+		})
+		require.NoError(t, err)
+		resp := restore()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var restored schemas.IssueWithWIPWarning
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&restored))
+		assert.Equal(t, deleted.ID, restored.ID)
+		require.NotNil(t, restored.WIPWarning)
+		assert.Equal(t, int64(2), restored.WIPWarning.IssueCount)
+	})
+
+	t.Run("should not restore columns or issues into an archived project", func(t *testing.T) {
+		t.Parallel()
+		setup := testutils.WithIntegrationTestSetup(ctx, t)
+		defer setup.Cleanup()
+
+		client := testutils.CreateAuthenticatedClient(t, setup, "user1@example.com", "User 1", "password123")
+		user, err := setup.Queries.GetUserByEmail(ctx, "user1@example.com")
+		require.NoError(t, err)
+		teamID := testutils.CreateTeamAndAddUser(t, ctx, setup, user.ID, "Team 1")
+
+		project, err := setup.Queries.CreateProject(ctx, db.CreateProjectParams{Name: "Project", TeamID: teamID, KeyPrefix: "PRJ"})
+		require.NoError(t, err)
+		var columns []db.ProjectStatusColumn
+		for _, name := range []string{"To Do", "Done"} {
+			column, err := setup.Queries.CreateProjectStatusColumn(ctx, db.CreateProjectStatusColumnParams{
+				ProjectID: int32(project.ID),
+				Name:      name,
+			})
+			require.NoError(t, err)
+			columns = append(columns, column)
+		}
+		issue, err := setup.Queries.CreateIssue(ctx, db.CreateIssueParams{Name: "Issue", ColumnID: columns[0].ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.DeleteIssue(ctx, db.DeleteIssueParams{ID: issue.ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.DeleteProjectStatusColumn(ctx, db.DeleteProjectStatusColumnParams{ID: columns[1].ID})
+		require.NoError(t, err)
+		_, err = setup.Queries.ArchiveProject(ctx, project.ID)
+		require.NoError(t, err)
+
+		restore := func(kind string, id int64) int {
+			url := fmt.Sprintf("%s/teams/%d/trash/%s/%d/restore", setup.Server.GetURL(), teamID, kind, id)
+			resp, err := client.Post(url, "application/json", nil)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusForbidden, restore("issues", issue.ID))
+		assert.Equal(t, http.StatusForbidden, restore("columns", columns[1].ID))
+		trash, err := setup.Queries.GetDeletedIssue(ctx, db.GetDeletedIssueParams{ID: issue.ID, TeamID: teamID})
+		require.NoError(t, err)
+		assert.Equal(t, issue.ID, trash.ID)
+	})
+}
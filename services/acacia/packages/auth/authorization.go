@@ -10,7 +10,8 @@ import (
 )
 
 var (
-	ErrForbidden = errors.New("Forbidden: insufficient permissions")
+	ErrForbidden       = errors.New("Forbidden: insufficient permissions")
+	ErrProjectArchived = errors.New("Project is archived and read-only")
 )
 
 // AccessChecker is a callback function that checks if a user has access
@@ -56,3 +57,26 @@ func (m *AuthorizationMiddleware) RequireAccess(checker AccessChecker) func(http
 		return nil
 	})
 }
+
+// RequireWritable creates a middleware that rejects changes to archived projects using the provided checker.
+// Safe methods pass through so archived projects stay readable; use it after RequireAccess.
+func (m *AuthorizationMiddleware) RequireWritable(checker AccessChecker) func(http.Handler) http.Handler {
+	return httperr.WithMiddlewareErrorHandler(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+			return nil
+		}
+
+		if err := checker(r, m.queries); err != nil {
+			if errors.Is(err, ErrProjectArchived) {
+				return httperr.WithStatus(ErrProjectArchived, http.StatusForbidden)
+			}
+			m.logger.WithError(err).Error("Failed to check whether the project is archived")
+			return httperr.WithStatus(errors.New("Internal server error"), http.StatusInternalServerError)
+		}
+
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
@@ -83,7 +83,8 @@ func CheckIssueAccessByURLParam(paramName string) AccessChecker {
 	}
 }
 
-// CheckIssueAccessByBody checks if user has access to an issue from request body
+// CheckIssueAccessByBody checks if user has access to an issue from request body and, when the body
+// names a column_id, to that column as well
 func CheckIssueAccessByBody() AccessChecker {
 	return func(r *http.Request, queries *db.Queries) error {
 		body, err := io.ReadAll(r.Body)
@@ -93,7 +94,8 @@ func CheckIssueAccessByBody() AccessChecker {
 		r.Body = io.NopCloser(bytes.NewBuffer(body))
 
 		var data struct {
-			ID int64 `json:"id"`
+			ID       int64 `json:"id"`
+			ColumnID int64 `json:"column_id"`
 		}
 		if err := json.Unmarshal(body, &data); err != nil {
 			// Invalid JSON - let the handler return 400
@@ -105,7 +107,14 @@ func CheckIssueAccessByBody() AccessChecker {
 			return nil
 		}
 
-		return CheckIssueAccess(r.Context(), queries, data.ID)
+		if err := CheckIssueAccess(r.Context(), queries, data.ID); err != nil {
+			return err
+		}
+
+		if data.ColumnID == 0 {
+			return nil
+		}
+		return CheckColumnAccess(r.Context(), queries, data.ColumnID)
 	}
 }
 
@@ -217,3 +226,96 @@ func CheckConversationOwnershipByBody() AccessChecker {
 		return nil
 	}
 }
+
+// CheckProjectWritableByURLParam checks that the project specified in URL parameter is not archived
+func CheckProjectWritableByURLParam(paramName string) AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		projectID, err := strconv.ParseInt(chi.URLParam(r, paramName), 10, 64)
+		if err != nil {
+			// Invalid ID - let the handler return 400
+			return nil
+		}
+
+		return CheckProjectWritable(r.Context(), queries, projectID)
+	}
+}
+
+// CheckColumnWritableByURLParam checks that the project of the column specified in URL parameter is not archived
+func CheckColumnWritableByURLParam(paramName string) AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		columnID, err := strconv.ParseInt(chi.URLParam(r, paramName), 10, 64)
+		if err != nil {
+			// Invalid ID - let the handler return 400
+			return nil
+		}
+
+		return CheckColumnWritable(r.Context(), queries, columnID)
+	}
+}
+
+// CheckIssueWritableByURLParam checks that the project of the issue specified in URL parameter is not archived
+func CheckIssueWritableByURLParam(paramName string) AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		issueID, err := strconv.ParseInt(chi.URLParam(r, paramName), 10, 64)
+		if err != nil {
+			// Invalid ID - let the handler return 400
+			return nil
+		}
+
+		return CheckIssueWritable(r.Context(), queries, issueID)
+	}
+}
+
+// CheckProjectWritableByBody checks that the project_id from request body is not archived
+func CheckProjectWritableByBody() AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		projectID, err := bodyID(r, "project_id")
+		if err != nil || projectID == 0 {
+			return err
+		}
+
+		return CheckProjectWritable(r.Context(), queries, projectID)
+	}
+}
+
+// CheckColumnWritableByBody checks that the project of the column_id from request body is not archived
+func CheckColumnWritableByBody() AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		columnID, err := bodyID(r, "column_id")
+		if err != nil || columnID == 0 {
+			return err
+		}
+
+		return CheckColumnWritable(r.Context(), queries, columnID)
+	}
+}
+
+// CheckIssueWritableByBody checks that the project of the issue id from request body is not archived
+func CheckIssueWritableByBody() AccessChecker {
+	return func(r *http.Request, queries *db.Queries) error {
+		issueID, err := bodyID(r, "id")
+		if err != nil || issueID == 0 {
+			return err
+		}
+
+		return CheckIssueWritable(r.Context(), queries, issueID)
+	}
+}
+
+// bodyID reads a numeric field from the JSON request body and restores the body for the handler.
+// Returns 0 when the body or the field is invalid so the handler can report it.
+func bodyID(r *http.Request, fieldName string) (int64, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return 0, errors.New("failed to read request body")
+	}
+	r.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, nil
+	}
+
+	id, _ := data[fieldName].(float64)
+	return int64(id), nil
+}
@@ -129,3 +129,32 @@ func CheckColumnAccess(ctx context.Context, queries *db.Queries, columnID int64)
 
 	return nil
 }
+
+// CheckProjectWritable returns ErrProjectArchived when the project is archived.
+// A missing project passes so the caller can report it.
+func CheckProjectWritable(ctx context.Context, queries *db.Queries, projectID int64) error {
+	return checkNotArchived(queries.IsProjectArchived(ctx, projectID))
+}
+
+// CheckColumnWritable returns ErrProjectArchived when the column's project is archived
+func CheckColumnWritable(ctx context.Context, queries *db.Queries, columnID int64) error {
+	return checkNotArchived(queries.IsColumnProjectArchived(ctx, columnID))
+}
+
+// CheckIssueWritable returns ErrProjectArchived when the issue's project is archived
+func CheckIssueWritable(ctx context.Context, queries *db.Queries, issueID int64) error {
+	return checkNotArchived(queries.IsIssueProjectArchived(ctx, issueID))
+}
+
+func checkNotArchived(archived bool, err error) error {
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	if archived {
+		return ErrProjectArchived
+	}
+	return nil
+}
@@ -2,22 +2,24 @@ package config
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
 type Environment struct {
-	Port            string
-	DatabaseURL     string
-	Env             string
-	JWTSecret       string
-	EncryptionKey   []byte
-	AWSS3Bucket     string
-	AWSRegion       string
-	AWSAccessKeyID  string
-	AWSSecretKey    string
-	AWSEndpoint     string // For localstack
+	Port               string
+	DatabaseURL        string
+	Env                string
+	JWTSecret          string
+	EncryptionKey      []byte
+	AWSS3Bucket        string
+	AWSRegion          string
+	AWSAccessKeyID     string
+	AWSSecretKey       string
+	AWSEndpoint        string // For localstack
+	TrashRetentionDays int    // Days deleted items stay in the trash; 0 uses the default
 }
 
 const (
@@ -76,17 +78,27 @@ func LoadEnvironment() *Environment {
 	// Optional: for localstack development
 	awsEndpoint := os.Getenv("AWS_ENDPOINT")
 
+	// Optional: defaults to 30 days
+	var trashRetentionDays int
+	if value := os.Getenv("TRASH_RETENTION_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 {
+			logrus.Fatal("TRASH_RETENTION_DAYS must be a positive number of days")
+		}
+		trashRetentionDays = days
+	}
+
 	return &Environment{
-		Env:            env,
-		Port:           port,
-		DatabaseURL:    databaseURL,
-		JWTSecret:      jwtSecret,
-		EncryptionKey:  []byte(encryptionKey),
-		AWSS3Bucket:    awsS3Bucket,
-		AWSRegion:      awsRegion,
-		AWSAccessKeyID: awsAccessKeyID,
-		AWSSecretKey:   awsSecretKey,
-		AWSEndpoint:    awsEndpoint,
+		Env:                env,
+		Port:               port,
+		DatabaseURL:        databaseURL,
+		JWTSecret:          jwtSecret,
+		EncryptionKey:      []byte(encryptionKey),
+		AWSS3Bucket:        awsS3Bucket,
+		AWSRegion:          awsRegion,
+		AWSAccessKeyID:     awsAccessKeyID,
+		AWSSecretKey:       awsSecretKey,
+		AWSEndpoint:        awsEndpoint,
+		TrashRetentionDays: trashRetentionDays,
 	}
 }
-
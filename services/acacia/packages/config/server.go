@@ -27,12 +27,39 @@ type Database struct {
 }
 
 type Server struct {
-	httpServer *http.Server
-	db         *Database
-	logger     *logrus.Logger
+	httpServer   *http.Server
+	db           *Database
+	logger       *logrus.Logger
+	trashService *services.TrashService
+	purgeCtx     context.Context
+	stopPurge    context.CancelFunc
 }
 
-func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
+// trashPurgeInterval is how often expired items are purged from the trash
+const trashPurgeInterval = time.Hour
+
+// ServerOption customizes how NewServer wires its dependencies
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	llmProviders map[string]llm.ProviderFactory
+}
+
+// WithLLMProvider registers an additional LLM provider factory, e.g. a scripted provider in tests
+func WithLLMProvider(name string, factory llm.ProviderFactory) ServerOption {
+	return func(o *serverOptions) {
+		o.llmProviders[name] = factory
+	}
+}
+
+func NewServer(d *Database, l *logrus.Logger, env *Environment, opts ...ServerOption) *Server {
+	options := &serverOptions{
+		llmProviders: map[string]llm.ProviderFactory{},
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	// Initialize JWT manager
 	jwtManager := auth.NewJWTManager(
 		env.JWTSecret,
@@ -54,19 +81,23 @@ func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
 
 	// Initialize LLM provider registry
 	providerRegistry := llm.NewProviderRegistry(l)
+	for name, factory := range options.llmProviders {
+		providerRegistry.Register(name, factory)
+	}
 
 	// Initialize tools for LLM
 	toolsList := []llm.Tool{
 		tools.NewGetUserProjectsTool(d.Queries, l),
 		tools.NewGetProjectDetailsTool(d.Queries, l),
-		tools.NewGetIssueDetailsTool(d.Queries, l),
+		tools.NewGetIssueDetailsTool(d.Queries, d.Conn, l),
+		tools.NewGetIssueCommentsTool(d.Queries, d.Conn, l),
 		tools.NewSearchIssuesTool(d.Queries, l),
 	}
 	toolRegistry := llm.NewToolRegistry(toolsList)
 	l.WithField("tool_count", len(toolsList)).Info("Tool registry initialized")
 
-	// Initialize conversation service
-	conversationService := services.NewConversationService(
+	// Initialize team-scoped LLM provider resolution
+	teamProviderResolver := services.NewTeamProviderResolver(
 		d.Queries,
 		providerRegistry,
 		encryptionService,
@@ -74,6 +105,9 @@ func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
 		l,
 	)
 
+	// Initialize conversation service
+	conversationService := services.NewConversationService(d.Queries, teamProviderResolver, l)
+
 	// Initialize S3 storage
 	s3Storage, err := storage.NewS3Storage(storage.S3Config{
 		Bucket:          env.AWSS3Bucket,
@@ -86,13 +120,25 @@ func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
 		l.WithError(err).Fatal("Failed to initialize S3 storage")
 	}
 
-	issuesController := api.NewIssuesController(d.Queries, l, s3Storage)
+	issuesController := api.NewIssuesController(d.Queries, l, s3Storage, d.Conn, teamProviderResolver)
+	issueCommentsController := api.NewIssueCommentsController(d.Queries, l, s3Storage, d.Conn)
 	projectsController := api.NewProjectsController(d.Queries, l)
 	projectColumnsController := api.NewProjectStatusColumnsController(d.Queries, l, d.Conn)
 	usersController := api.NewUsersController(d.Queries, l, jwtManager)
 	teamsController := api.NewTeamsController(d.Queries, l)
 	teamLLMAPIKeysController := api.NewTeamLLMAPIKeysController(d.Queries, l, encryptionService)
+	labelsController := api.NewLabelsController(d.Queries, l)
 	conversationsController := api.NewConversationsController(d.Queries, l, conversationService)
+	issueDraftsController := api.NewIssueDraftsController(d.Queries, l, d.Conn, teamProviderResolver)
+	projectReportsController := api.NewProjectReportsController(d.Queries, l, teamProviderResolver)
+	customFieldsController := api.NewCustomFieldsController(d.Queries, l, d.Conn)
+	timeEntriesController := api.NewTimeEntriesController(d.Queries, l)
+	sprintsController := api.NewSprintsController(d.Queries, l, d.Conn)
+	milestonesController := api.NewMilestonesController(d.Queries, l, d.Conn)
+
+	// Initialize trash service; it also purges expired items in the background
+	trashService := services.NewTrashService(d.Queries, d.Conn, s3Storage, env.TrashRetentionDays, l)
+	trashController := api.NewTrashController(d.Queries, l, trashService)
 
 	r := chi.NewRouter()
 	r.Use(middleware.Logger)
@@ -100,21 +146,26 @@ func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
 
 	authMiddlewares := chi.Middlewares{authMiddleware.Handle}
 
-	r.Mount("/issues", routes.IssuesRoutes(issuesController, authMiddlewares, authzMiddleware))
-	r.Mount("/projects", routes.ProjectsRoutes(projectsController, authMiddlewares, authzMiddleware))
+	r.Mount("/issues", routes.IssuesRoutes(issuesController, issueCommentsController, timeEntriesController, sprintsController, milestonesController, authMiddlewares, authzMiddleware))
+	r.Mount("/projects", routes.ProjectsRoutes(projectsController, issueDraftsController, projectReportsController, customFieldsController, timeEntriesController, sprintsController, milestonesController, authMiddlewares, authzMiddleware))
 	r.Mount("/project-columns", routes.ProjectStatusColumnsRoutes(projectColumnsController, authMiddlewares, authzMiddleware))
 	r.Mount("/users", routes.UsersRoutes(usersController, authMiddlewares))
-	r.Mount("/teams", routes.TeamsRoutes(teamsController, teamLLMAPIKeysController, authMiddlewares, authzMiddleware))
+	r.Mount("/teams", routes.TeamsRoutes(teamsController, teamLLMAPIKeysController, labelsController, trashController, authMiddlewares, authzMiddleware))
 	r.Mount("/conversations", routes.ConversationsRoutes(conversationsController, authMiddlewares, authzMiddleware))
 
 	httpServer := &http.Server{
 		Handler: r,
 	}
 
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+
 	server := &Server{
-		httpServer: httpServer,
-		db:         d,
-		logger:     l,
+		httpServer:   httpServer,
+		db:           d,
+		logger:       l,
+		trashService: trashService,
+		purgeCtx:     purgeCtx,
+		stopPurge:    stopPurge,
 	}
 
 	return server
@@ -123,12 +174,16 @@ func NewServer(d *Database, l *logrus.Logger, env *Environment) *Server {
 func (s *Server) ListenAndServe(port string) {
 	s.httpServer.Addr = ":" + port
 	s.logger.WithField("port", port).Info("Starting server")
+
+	go s.trashService.RunPurge(s.purgeCtx, trashPurgeInterval)
+
 	if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		s.logger.WithError(err).Error("Server failed to start")
 	}
 }
 
 func (s *Server) Close() {
+	s.stopPurge()
 	ctx, _ := context.WithTimeout(context.Background(), time.Millisecond*5000)
 	s.httpServer.Shutdown(ctx)
 }
@@ -34,7 +34,7 @@ SELECT p.team_id
 FROM issues i
 JOIN project_status_columns psc ON i.column_id = psc.id
 JOIN projects p ON psc.project_id = p.id
-WHERE i.id = $1
+WHERE i.id = $1 AND i.deleted_at IS NULL AND psc.deleted_at IS NULL AND p.deleted_at IS NULL
 `
 
 func (q *Queries) GetTeamIDByIssue(ctx context.Context, id int64) (int64, error) {
@@ -47,7 +47,7 @@ func (q *Queries) GetTeamIDByIssue(ctx context.Context, id int64) (int64, error)
 const getTeamIDByProject = `-- name: GetTeamIDByProject :one
 SELECT team_id
 FROM projects
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetTeamIDByProject(ctx context.Context, id int64) (int64, error) {
@@ -61,7 +61,7 @@ const getTeamIDByProjectStatusColumn = `-- name: GetTeamIDByProjectStatusColumn
 SELECT p.team_id
 FROM project_status_columns psc
 JOIN projects p ON psc.project_id = p.id
-WHERE psc.id = $1
+WHERE psc.id = $1 AND psc.deleted_at IS NULL AND p.deleted_at IS NULL
 `
 
 func (q *Queries) GetTeamIDByProjectStatusColumn(ctx context.Context, id int64) (int64, error) {
@@ -70,3 +70,45 @@ func (q *Queries) GetTeamIDByProjectStatusColumn(ctx context.Context, id int64)
 	err := row.Scan(&team_id)
 	return team_id, err
 }
+
+const isColumnProjectArchived = `-- name: IsColumnProjectArchived :one
+SELECT p.archived_at IS NOT NULL AS archived
+FROM project_status_columns psc
+JOIN projects p ON psc.project_id = p.id
+WHERE psc.id = $1
+`
+
+func (q *Queries) IsColumnProjectArchived(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isColumnProjectArchived, id)
+	var archived bool
+	err := row.Scan(&archived)
+	return archived, err
+}
+
+const isIssueProjectArchived = `-- name: IsIssueProjectArchived :one
+SELECT p.archived_at IS NOT NULL AS archived
+FROM issues i
+JOIN project_status_columns psc ON i.column_id = psc.id
+JOIN projects p ON psc.project_id = p.id
+WHERE i.id = $1
+`
+
+func (q *Queries) IsIssueProjectArchived(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isIssueProjectArchived, id)
+	var archived bool
+	err := row.Scan(&archived)
+	return archived, err
+}
+
+const isProjectArchived = `-- name: IsProjectArchived :one
+SELECT archived_at IS NOT NULL AS archived
+FROM projects
+WHERE id = $1
+`
+
+func (q *Queries) IsProjectArchived(ctx context.Context, id int64) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProjectArchived, id)
+	var archived bool
+	err := row.Scan(&archived)
+	return archived, err
+}
@@ -7,25 +7,107 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/guregu/null"
+	"github.com/lib/pq"
 )
 
+const archiveIssue = `-- name: ArchiveIssue :one
+UPDATE
+    issues
+SET
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+func (q *Queries) ArchiveIssue(ctx context.Context, id int64) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, archiveIssue, id)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const createIssue = `-- name: CreateIssue :one
-INSERT INTO issues (name, column_id, description, created_at, updated_at)
-    VALUES ($1, $2, $3, NOW(), NOW())
+WITH allocated AS (
+    UPDATE
+        projects
+    SET
+        next_issue_number = next_issue_number + 1
+    WHERE
+        id = (
+            SELECT
+                project_id
+            FROM
+                project_status_columns
+            WHERE
+                id = $1)
+        RETURNING
+            next_issue_number - 1 AS number)
+INSERT INTO issues (name, column_id, description, reporter_id, priority, due_date, parent_id, estimate_points, estimate_minutes, number, rank, created_at, updated_at)
+    VALUES ($2, $1, $3, $4, COALESCE($5::text, 'none'), $6, $7, $8, $9, (
+            SELECT
+                number
+            FROM allocated), COALESCE((
+            SELECT
+                floor(MIN(rank)) - 1
+            FROM issues
+            WHERE
+                column_id = $1), 0), NOW(), NOW())
 RETURNING
-    id, name, description, created_at, updated_at, column_id
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
 `
 
 type CreateIssueParams struct {
-	Name        string      `db:"name" json:"name"`
-	ColumnID    int64       `db:"column_id" json:"column_id"`
-	Description null.String `db:"description" json:"description"`
+	ColumnID        int64       `db:"column_id" json:"column_id"`
+	Name            string      `db:"name" json:"name"`
+	Description     null.String `db:"description" json:"description"`
+	ReporterID      null.Int    `db:"reporter_id" json:"reporter_id"`
+	Priority        null.String `db:"priority" json:"priority"`
+	DueDate         null.Time   `db:"due_date" json:"due_date"`
+	ParentID        null.Int    `db:"parent_id" json:"parent_id"`
+	EstimatePoints  null.Int    `db:"estimate_points" json:"estimate_points"`
+	EstimateMinutes null.Int    `db:"estimate_minutes" json:"estimate_minutes"`
 }
 
 func (q *Queries) CreateIssue(ctx context.Context, arg CreateIssueParams) (Issue, error) {
-	row := q.db.QueryRowContext(ctx, createIssue, arg.Name, arg.ColumnID, arg.Description)
+	row := q.db.QueryRowContext(ctx, createIssue,
+		arg.ColumnID,
+		arg.Name,
+		arg.Description,
+		arg.ReporterID,
+		arg.Priority,
+		arg.DueDate,
+		arg.ParentID,
+		arg.EstimatePoints,
+		arg.EstimateMinutes,
+	)
 	var i Issue
 	err := row.Scan(
 		&i.ID,
@@ -34,27 +116,138 @@ func (q *Queries) CreateIssue(ctx context.Context, arg CreateIssueParams) (Issue
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
-const deleteIssue = `-- name: DeleteIssue :exec
-DELETE FROM issues
-WHERE id = $1
+const deleteIssue = `-- name: DeleteIssue :execrows
+UPDATE
+    issues
+SET
+    deleted_at = NOW()
+WHERE
+    id = $1
+    AND ($2::timestamp[] IS NULL
+        OR updated_at = ANY ($2::timestamp[]))
 `
 
-func (q *Queries) DeleteIssue(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteIssue, id)
+type DeleteIssueParams struct {
+	ID          int64       `db:"id" json:"id"`
+	IfUpdatedAt []time.Time `db:"if_updated_at" json:"if_updated_at"`
+}
+
+func (q *Queries) DeleteIssue(ctx context.Context, arg DeleteIssueParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteIssue, arg.ID, pq.Array(arg.IfUpdatedAt))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const detachIssueChildren = `-- name: DetachIssueChildren :exec
+UPDATE
+    issues
+SET
+    parent_id = NULL,
+    updated_at = NOW()
+WHERE
+    parent_id = $1
+`
+
+func (q *Queries) DetachIssueChildren(ctx context.Context, parentID null.Int) error {
+	_, err := q.db.ExecContext(ctx, detachIssueChildren, parentID)
 	return err
 }
 
+const findSimilarIssuesInProject = `-- name: FindSimilarIssuesInProject :many
+WITH q AS (
+    SELECT
+        replace(plainto_tsquery('english', $1::text)::text, ' & ', ' | ')::tsquery AS query
+)
+SELECT
+    i.id,
+    i.name,
+    i.description,
+    i.column_id,
+    ts_rank(i.search_vector, q.query, 32)::float8 AS score
+FROM
+    issues i
+    JOIN project_status_columns psc ON psc.id = i.column_id
+    CROSS JOIN q
+WHERE
+    psc.project_id = $2
+    AND i.search_vector @@ q.query
+    AND i.deleted_at IS NULL
+ORDER BY
+    score DESC,
+    i.id DESC
+LIMIT $3
+`
+
+type FindSimilarIssuesInProjectParams struct {
+	Content        string `db:"content" json:"content"`
+	ProjectID      int32  `db:"project_id" json:"project_id"`
+	CandidateLimit int32  `db:"candidate_limit" json:"candidate_limit"`
+}
+
+type FindSimilarIssuesInProjectRow struct {
+	ID          int64       `db:"id" json:"id"`
+	Name        string      `db:"name" json:"name"`
+	Description null.String `db:"description" json:"description"`
+	ColumnID    int64       `db:"column_id" json:"column_id"`
+	Score       float64     `db:"score" json:"score"`
+}
+
+func (q *Queries) FindSimilarIssuesInProject(ctx context.Context, arg FindSimilarIssuesInProjectParams) ([]FindSimilarIssuesInProjectRow, error) {
+	rows, err := q.db.QueryContext(ctx, findSimilarIssuesInProject, arg.Content, arg.ProjectID, arg.CandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindSimilarIssuesInProjectRow
+	for rows.Next() {
+		var i FindSimilarIssuesInProjectRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ColumnID,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getIssueByID = `-- name: GetIssueByID :one
 SELECT
-    id, name, description, created_at, updated_at, column_id
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
 FROM
     issues
 WHERE
     id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) GetIssueByID(ctx context.Context, id int64) (Issue, error) {
@@ -67,19 +260,176 @@ func (q *Queries) GetIssueByID(ctx context.Context, id int64) (Issue, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
+const getIssueChildren = `-- name: GetIssueChildren :many
+SELECT
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+FROM
+    issues
+WHERE
+    parent_id = $1
+    AND deleted_at IS NULL
+ORDER BY
+    created_at,
+    id
+`
+
+func (q *Queries) GetIssueChildren(ctx context.Context, parentID null.Int) ([]Issue, error) {
+	rows, err := q.db.QueryContext(ctx, getIssueChildren, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Issue
+	for rows.Next() {
+		var i Issue
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ColumnID,
+			&i.SearchVector,
+			&i.ReporterID,
+			&i.Priority,
+			&i.DueDate,
+			&i.Rank,
+			&i.ParentID,
+			&i.Number,
+			&i.EstimatePoints,
+			&i.EstimateMinutes,
+			&i.SprintID,
+			&i.MilestoneID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIssueIDsByKey = `-- name: GetIssueIDsByKey :many
+SELECT
+    i.id
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+    JOIN team_members tm ON tm.team_id = p.team_id
+WHERE
+    tm.user_id = $1
+    AND p.key_prefix = $2
+    AND i.number = $3
+    AND i.deleted_at IS NULL
+    AND p.deleted_at IS NULL
+`
+
+type GetIssueIDsByKeyParams struct {
+	UserID    int64  `db:"user_id" json:"user_id"`
+	KeyPrefix string `db:"key_prefix" json:"key_prefix"`
+	Number    int32  `db:"number" json:"number"`
+}
+
+func (q *Queries) GetIssueIDsByKey(ctx context.Context, arg GetIssueIDsByKeyParams) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, getIssueIDsByKey, arg.UserID, arg.KeyPrefix, arg.Number)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIssueKey = `-- name: GetIssueKey :one
+SELECT
+    (p.key_prefix || '-' || i.number)::text AS key
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+WHERE
+    i.id = $1
+`
+
+func (q *Queries) GetIssueKey(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getIssueKey, id)
+	var key string
+	err := row.Scan(&key)
+	return key, err
+}
+
+const getIssueRankInColumn = `-- name: GetIssueRankInColumn :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    id = $1
+    AND column_id = $2
+    AND deleted_at IS NULL
+`
+
+type GetIssueRankInColumnParams struct {
+	ID       int64 `db:"id" json:"id"`
+	ColumnID int64 `db:"column_id" json:"column_id"`
+}
+
+func (q *Queries) GetIssueRankInColumn(ctx context.Context, arg GetIssueRankInColumnParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getIssueRankInColumn, arg.ID, arg.ColumnID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
 const getIssuesByColumnId = `-- name: GetIssuesByColumnId :many
 SELECT
-    id, name, description, created_at, updated_at, column_id
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
 FROM
     issues
 WHERE
     column_id = $1
+    AND deleted_at IS NULL
 ORDER BY
-    created_at DESC
+    rank,
+    id
 `
 
 func (q *Queries) GetIssuesByColumnId(ctx context.Context, columnID int64) ([]Issue, error) {
@@ -98,6 +448,19 @@ func (q *Queries) GetIssuesByColumnId(ctx context.Context, columnID int64) ([]Is
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.ColumnID,
+			&i.SearchVector,
+			&i.ReporterID,
+			&i.Priority,
+			&i.DueDate,
+			&i.Rank,
+			&i.ParentID,
+			&i.Number,
+			&i.EstimatePoints,
+			&i.EstimateMinutes,
+			&i.SprintID,
+			&i.MilestoneID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -112,13 +475,344 @@ func (q *Queries) GetIssuesByColumnId(ctx context.Context, columnID int64) ([]Is
 	return items, nil
 }
 
+const getLastIssueRank = `-- name: GetLastIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = $1
+    AND id <> $2
+    AND deleted_at IS NULL
+ORDER BY
+    rank DESC
+LIMIT 1
+`
+
+type GetLastIssueRankParams struct {
+	ColumnID int64 `db:"column_id" json:"column_id"`
+	IssueID  int64 `db:"issue_id" json:"issue_id"`
+}
+
+func (q *Queries) GetLastIssueRank(ctx context.Context, arg GetLastIssueRankParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLastIssueRank, arg.ColumnID, arg.IssueID)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getNextIssueRank = `-- name: GetNextIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = $1
+    AND id <> $2
+    AND deleted_at IS NULL
+    AND (rank > $3
+        OR (rank = $3
+            AND id > $4))
+ORDER BY
+    rank,
+    id
+LIMIT 1
+`
+
+type GetNextIssueRankParams struct {
+	ColumnID    int64  `db:"column_id" json:"column_id"`
+	IssueID     int64  `db:"issue_id" json:"issue_id"`
+	Rank        string `db:"rank" json:"rank"`
+	NeighbourID int64  `db:"neighbour_id" json:"neighbour_id"`
+}
+
+func (q *Queries) GetNextIssueRank(ctx context.Context, arg GetNextIssueRankParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getNextIssueRank,
+		arg.ColumnID,
+		arg.IssueID,
+		arg.Rank,
+		arg.NeighbourID,
+	)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getPreviousIssueRank = `-- name: GetPreviousIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = $1
+    AND id <> $2
+    AND deleted_at IS NULL
+    AND (rank < $3
+        OR (rank = $3
+            AND id < $4))
+ORDER BY
+    rank DESC,
+    id DESC
+LIMIT 1
+`
+
+type GetPreviousIssueRankParams struct {
+	ColumnID    int64  `db:"column_id" json:"column_id"`
+	IssueID     int64  `db:"issue_id" json:"issue_id"`
+	Rank        string `db:"rank" json:"rank"`
+	NeighbourID int64  `db:"neighbour_id" json:"neighbour_id"`
+}
+
+func (q *Queries) GetPreviousIssueRank(ctx context.Context, arg GetPreviousIssueRankParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getPreviousIssueRank,
+		arg.ColumnID,
+		arg.IssueID,
+		arg.Rank,
+		arg.NeighbourID,
+	)
+	var rank string
+	err := row.Scan(&rank)
+	return rank, err
+}
+
+const getSubIssueProgress = `-- name: GetSubIssueProgress :many
+SELECT
+    i.parent_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE c.is_done) AS done
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    i.parent_id = ANY ($1::bigint[])
+    AND i.deleted_at IS NULL
+GROUP BY
+    i.parent_id
+`
+
+type GetSubIssueProgressRow struct {
+	ParentID null.Int `db:"parent_id" json:"parent_id"`
+	Total    int64    `db:"total" json:"total"`
+	Done     int64    `db:"done" json:"done"`
+}
+
+func (q *Queries) GetSubIssueProgress(ctx context.Context, parentIds []int64) ([]GetSubIssueProgressRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSubIssueProgress, pq.Array(parentIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSubIssueProgressRow
+	for rows.Next() {
+		var i GetSubIssueProgressRow
+		if err := rows.Scan(&i.ParentID, &i.Total, &i.Done); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isIssueAncestor = `-- name: IsIssueAncestor :one
+WITH RECURSIVE ancestors AS (
+    SELECT
+        id,
+        parent_id
+    FROM
+        issues
+    WHERE
+        id = $1
+    UNION
+    SELECT
+        i.id,
+        i.parent_id
+    FROM
+        issues i
+        JOIN ancestors a ON i.id = a.parent_id
+)
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            ancestors
+        WHERE
+            id = $2)
+`
+
+type IsIssueAncestorParams struct {
+	IssueID    int64 `db:"issue_id" json:"issue_id"`
+	AncestorID int64 `db:"ancestor_id" json:"ancestor_id"`
+}
+
+func (q *Queries) IsIssueAncestor(ctx context.Context, arg IsIssueAncestorParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isIssueAncestor, arg.IssueID, arg.AncestorID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const moveIssue = `-- name: MoveIssue :one
+UPDATE
+    issues
+SET
+    column_id = $1,
+    rank = CASE WHEN $2::numeric IS NULL
+        AND $3::numeric IS NULL THEN
+        0
+    WHEN $3::numeric IS NULL THEN
+        floor($2::numeric) + 1
+    WHEN $2::numeric IS NULL THEN
+        floor($3::numeric) - 1
+    ELSE
+        ($2::numeric + $3::numeric) * 0.5
+    END,
+    updated_at = NOW()
+WHERE
+    id = $4
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+type MoveIssueParams struct {
+	ColumnID  int64          `db:"column_id" json:"column_id"`
+	LowerRank sql.NullString `db:"lower_rank" json:"lower_rank"`
+	UpperRank sql.NullString `db:"upper_rank" json:"upper_rank"`
+	ID        int64          `db:"id" json:"id"`
+}
+
+func (q *Queries) MoveIssue(ctx context.Context, arg MoveIssueParams) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, moveIssue,
+		arg.ColumnID,
+		arg.LowerRank,
+		arg.UpperRank,
+		arg.ID,
+	)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const moveIssueToProject = `-- name: MoveIssueToProject :one
+WITH allocated AS (
+    UPDATE
+        projects
+    SET
+        next_issue_number = next_issue_number + 1
+    WHERE
+        id = (
+            SELECT
+                project_id
+            FROM
+                project_status_columns
+            WHERE
+                id = $1)
+        RETURNING
+            next_issue_number - 1 AS number)
+UPDATE
+    issues
+SET
+    column_id = $1,
+    number = (
+        SELECT
+            number
+        FROM
+            allocated),
+    rank = COALESCE((
+        SELECT
+            floor(MAX(rank)) + 1
+        FROM issues
+        WHERE
+            column_id = $1), 0),
+    parent_id = NULL,
+    sprint_id = NULL,
+    milestone_id = NULL,
+    updated_at = NOW()
+WHERE
+    id = $2
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+type MoveIssueToProjectParams struct {
+	ColumnID int64 `db:"column_id" json:"column_id"`
+	ID       int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) MoveIssueToProject(ctx context.Context, arg MoveIssueToProjectParams) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, moveIssueToProject, arg.ColumnID, arg.ID)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const reassignAllIssuesFromColumn = `-- name: ReassignAllIssuesFromColumn :exec
 UPDATE
     issues
 SET
-    column_id = $1
+    column_id = $1,
+    rank = moved.rank
+FROM (
+    SELECT
+        i.id,
+        COALESCE((
+            SELECT
+                floor(MAX(t.rank))
+            FROM issues t
+            WHERE
+                t.column_id = $1), 0) + row_number() OVER (ORDER BY i.rank, i.id) AS rank
+    FROM
+        issues i
+    WHERE
+        i.column_id = $2) moved
 WHERE
-    column_id = $2
+    issues.id = moved.id
 `
 
 type ReassignAllIssuesFromColumnParams struct {
@@ -131,25 +825,490 @@ func (q *Queries) ReassignAllIssuesFromColumn(ctx context.Context, arg ReassignA
 	return err
 }
 
+const renumberColumnRanks = `-- name: RenumberColumnRanks :exec
+UPDATE
+    issues
+SET
+    rank = ordered.position
+FROM (
+    SELECT
+        id,
+        row_number() OVER (ORDER BY rank, id) AS position
+    FROM
+        issues
+    WHERE
+        column_id = $1) ordered
+WHERE
+    issues.id = ordered.id
+`
+
+func (q *Queries) RenumberColumnRanks(ctx context.Context, columnID int64) error {
+	_, err := q.db.ExecContext(ctx, renumberColumnRanks, columnID)
+	return err
+}
+
+const searchIssues = `-- name: SearchIssues :many
+WITH matches AS (
+    SELECT
+        i.id,
+        i.name,
+        i.description,
+        i.column_id,
+        psc.project_id,
+        i.priority,
+        i.due_date,
+        i.created_at,
+        i.updated_at,
+        ts_rank(i.search_vector, websearch_to_tsquery('english', $1::text))::float8 AS rank
+    FROM
+        issues i
+        JOIN project_status_columns psc ON psc.id = i.column_id
+        JOIN projects p ON p.id = psc.project_id
+        JOIN team_members tm ON tm.team_id = p.team_id
+    WHERE
+        i.deleted_at IS NULL
+        AND psc.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        AND tm.user_id = $2
+        AND ($1::text = ''
+            OR i.search_vector @@ websearch_to_tsquery('english', $1::text))
+        AND ($3::bigint IS NULL
+            OR psc.project_id = $3::bigint)
+        AND ($4::bigint IS NULL
+            OR i.column_id = $4::bigint)
+        AND ($5::timestamp IS NULL
+            OR i.created_at >= $5::timestamp)
+        AND ($6::timestamp IS NULL
+            OR i.created_at < $6::timestamp)
+        AND ($7::timestamp IS NULL
+            OR i.updated_at >= $7::timestamp)
+        AND ($8::timestamp IS NULL
+            OR i.updated_at < $8::timestamp)
+        AND ($9::bigint IS NULL
+            OR EXISTS (
+                SELECT
+                    1
+                FROM
+                    issue_assignees ia
+                WHERE
+                    ia.issue_id = i.id
+                    AND ia.user_id = $9::bigint))
+        AND ($10::text IS NULL
+            OR i.priority = $10::text)
+        AND ($11::bigint IS NULL
+            OR EXISTS (
+                SELECT
+                    1
+                FROM
+                    issue_labels il
+                WHERE
+                    il.issue_id = i.id
+                    AND il.label_id = $11::bigint))
+        AND ($12::date IS NULL
+            OR i.due_date >= $12::date)
+        AND ($13::date IS NULL
+            OR i.due_date < $13::date)
+        AND ($14::boolean
+            OR (i.archived_at IS NULL
+                AND p.archived_at IS NULL))
+),
+keyed AS (
+    SELECT
+        id,
+        name,
+        description,
+        column_id,
+        project_id,
+        priority,
+        due_date,
+        created_at,
+        updated_at,
+        rank,
+        (
+            CASE $15::text
+            WHEN 'created_at' THEN
+                EXTRACT(EPOCH FROM created_at)::float8
+            WHEN 'updated_at' THEN
+                EXTRACT(EPOCH FROM updated_at)::float8
+            ELSE
+                rank
+            END)::float8 AS sort_key
+    FROM
+        matches
+)
+SELECT
+    id,
+    name,
+    description,
+    column_id,
+    project_id,
+    priority,
+    due_date,
+    created_at,
+    updated_at,
+    rank,
+    sort_key,
+    ts_headline('english', replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), websearch_to_tsquery('english', $1::text), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+    ts_headline('english', replace(replace(replace(replace(replace(COALESCE(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), websearch_to_tsquery('english', $1::text), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=8, MaxWords=25') AS snippet
+FROM
+    keyed
+WHERE
+    $16::bigint IS NULL
+    OR ($17::boolean
+        AND (sort_key, id) < ($18::float8, $16::bigint))
+    OR (NOT $17::boolean
+        AND (sort_key, id) > ($18::float8, $16::bigint))
+ORDER BY
+    CASE WHEN $17::boolean THEN
+        sort_key
+    END DESC,
+    CASE WHEN $17::boolean THEN
+        id
+    END DESC,
+    CASE WHEN NOT $17::boolean THEN
+        sort_key
+    END ASC,
+    CASE WHEN NOT $17::boolean THEN
+        id
+    END ASC
+LIMIT $19
+`
+
+type SearchIssuesParams struct {
+	Query           string          `db:"query" json:"query"`
+	UserID          int64           `db:"user_id" json:"user_id"`
+	ProjectID       null.Int        `db:"project_id" json:"project_id"`
+	ColumnID        null.Int        `db:"column_id" json:"column_id"`
+	CreatedAfter    null.Time       `db:"created_after" json:"created_after"`
+	CreatedBefore   null.Time       `db:"created_before" json:"created_before"`
+	UpdatedAfter    null.Time       `db:"updated_after" json:"updated_after"`
+	UpdatedBefore   null.Time       `db:"updated_before" json:"updated_before"`
+	AssigneeID      null.Int        `db:"assignee_id" json:"assignee_id"`
+	Priority        null.String     `db:"priority" json:"priority"`
+	LabelID         null.Int        `db:"label_id" json:"label_id"`
+	DueAfter        null.Time       `db:"due_after" json:"due_after"`
+	DueBefore       null.Time       `db:"due_before" json:"due_before"`
+	IncludeArchived bool            `db:"include_archived" json:"include_archived"`
+	SortBy          string          `db:"sort_by" json:"sort_by"`
+	CursorID        null.Int        `db:"cursor_id" json:"cursor_id"`
+	SortDesc        bool            `db:"sort_desc" json:"sort_desc"`
+	CursorKey       sql.NullFloat64 `db:"cursor_key" json:"cursor_key"`
+	PageLimit       int32           `db:"page_limit" json:"page_limit"`
+}
+
+type SearchIssuesRow struct {
+	ID            int64       `db:"id" json:"id"`
+	Name          string      `db:"name" json:"name"`
+	Description   null.String `db:"description" json:"description"`
+	ColumnID      int64       `db:"column_id" json:"column_id"`
+	ProjectID     int32       `db:"project_id" json:"project_id"`
+	Priority      string      `db:"priority" json:"priority"`
+	DueDate       null.Time   `db:"due_date" json:"due_date"`
+	CreatedAt     time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time   `db:"updated_at" json:"updated_at"`
+	Rank          float64     `db:"rank" json:"rank"`
+	SortKey       float64     `db:"sort_key" json:"sort_key"`
+	NameHighlight string      `db:"name_highlight" json:"name_highlight"`
+	Snippet       string      `db:"snippet" json:"snippet"`
+}
+
+func (q *Queries) SearchIssues(ctx context.Context, arg SearchIssuesParams) ([]SearchIssuesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchIssues,
+		arg.Query,
+		arg.UserID,
+		arg.ProjectID,
+		arg.ColumnID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.UpdatedAfter,
+		arg.UpdatedBefore,
+		arg.AssigneeID,
+		arg.Priority,
+		arg.LabelID,
+		arg.DueAfter,
+		arg.DueBefore,
+		arg.IncludeArchived,
+		arg.SortBy,
+		arg.CursorID,
+		arg.SortDesc,
+		arg.CursorKey,
+		arg.PageLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchIssuesRow
+	for rows.Next() {
+		var i SearchIssuesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.ColumnID,
+			&i.ProjectID,
+			&i.Priority,
+			&i.DueDate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Rank,
+			&i.SortKey,
+			&i.NameHighlight,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setIssueMilestone = `-- name: SetIssueMilestone :one
+UPDATE
+    issues
+SET
+    milestone_id = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+type SetIssueMilestoneParams struct {
+	ID          int64    `db:"id" json:"id"`
+	MilestoneID null.Int `db:"milestone_id" json:"milestone_id"`
+}
+
+func (q *Queries) SetIssueMilestone(ctx context.Context, arg SetIssueMilestoneParams) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, setIssueMilestone, arg.ID, arg.MilestoneID)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const setIssueParent = `-- name: SetIssueParent :one
+UPDATE
+    issues
+SET
+    parent_id = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+type SetIssueParentParams struct {
+	ID       int64    `db:"id" json:"id"`
+	ParentID null.Int `db:"parent_id" json:"parent_id"`
+}
+
+func (q *Queries) SetIssueParent(ctx context.Context, arg SetIssueParentParams) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, setIssueParent, arg.ID, arg.ParentID)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const setIssueSprint = `-- name: SetIssueSprint :one
+UPDATE
+    issues
+SET
+    sprint_id = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+type SetIssueSprintParams struct {
+	ID       int64    `db:"id" json:"id"`
+	SprintID null.Int `db:"sprint_id" json:"sprint_id"`
+}
+
+func (q *Queries) SetIssueSprint(ctx context.Context, arg SetIssueSprintParams) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, setIssueSprint, arg.ID, arg.SprintID)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const touchIssues = `-- name: TouchIssues :exec
+UPDATE
+    issues
+SET
+    updated_at = NOW()
+WHERE
+    id = ANY ($1::bigint[])
+`
+
+func (q *Queries) TouchIssues(ctx context.Context, issueIds []int64) error {
+	_, err := q.db.ExecContext(ctx, touchIssues, pq.Array(issueIds))
+	return err
+}
+
+const unarchiveIssue = `-- name: UnarchiveIssue :one
+UPDATE
+    issues
+SET
+    archived_at = NULL,
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
+`
+
+func (q *Queries) UnarchiveIssue(ctx context.Context, id int64) (Issue, error) {
+	row := q.db.QueryRowContext(ctx, unarchiveIssue, id)
+	var i Issue
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const updateIssue = `-- name: UpdateIssue :one
 UPDATE
     issues
 SET
     name = COALESCE($1, name),
     description = COALESCE($2, description),
-    column_id = COALESCE($3, column_id),
+    column_id = COALESCE(NULLIF($3::bigint, 0), column_id),
+    rank = CASE WHEN $3::bigint IN (0, column_id) THEN
+        rank
+    ELSE
+        COALESCE((
+            SELECT
+                floor(MAX(i.rank)) + 1
+            FROM issues i
+            WHERE
+                i.column_id = $3::bigint), 0)
+    END,
+    priority = COALESCE($4::text, priority),
+    due_date = CASE WHEN $5::boolean THEN
+        NULL
+    ELSE
+        COALESCE($6::date, due_date)
+    END,
+    estimate_points = CASE WHEN $7::boolean THEN
+        NULL
+    ELSE
+        COALESCE($8::integer, estimate_points)
+    END,
+    estimate_minutes = CASE WHEN $7::boolean THEN
+        NULL
+    ELSE
+        COALESCE($9::integer, estimate_minutes)
+    END,
     updated_at = NOW()
 WHERE
-    id = $4
+    id = $10
+    AND ($11::timestamp[] IS NULL
+        OR updated_at = ANY ($11::timestamp[]))
 RETURNING
-    id, name, description, created_at, updated_at, column_id
+    id, name, description, created_at, updated_at, column_id, search_vector, reporter_id, priority, due_date, rank, parent_id, number, estimate_points, estimate_minutes, sprint_id, milestone_id, deleted_at, archived_at
 `
 
 type UpdateIssueParams struct {
-	Name        string      `db:"name" json:"name"`
-	Description null.String `db:"description" json:"description"`
-	ColumnID    int64       `db:"column_id" json:"column_id"`
-	ID          int64       `db:"id" json:"id"`
+	Name            string      `db:"name" json:"name"`
+	Description     null.String `db:"description" json:"description"`
+	ColumnID        int64       `db:"column_id" json:"column_id"`
+	Priority        null.String `db:"priority" json:"priority"`
+	ClearDueDate    bool        `db:"clear_due_date" json:"clear_due_date"`
+	DueDate         null.Time   `db:"due_date" json:"due_date"`
+	ClearEstimate   bool        `db:"clear_estimate" json:"clear_estimate"`
+	EstimatePoints  null.Int    `db:"estimate_points" json:"estimate_points"`
+	EstimateMinutes null.Int    `db:"estimate_minutes" json:"estimate_minutes"`
+	ID              int64       `db:"id" json:"id"`
+	IfUpdatedAt     []time.Time `db:"if_updated_at" json:"if_updated_at"`
 }
 
 func (q *Queries) UpdateIssue(ctx context.Context, arg UpdateIssueParams) (Issue, error) {
@@ -157,7 +1316,14 @@ func (q *Queries) UpdateIssue(ctx context.Context, arg UpdateIssueParams) (Issue
 		arg.Name,
 		arg.Description,
 		arg.ColumnID,
+		arg.Priority,
+		arg.ClearDueDate,
+		arg.DueDate,
+		arg.ClearEstimate,
+		arg.EstimatePoints,
+		arg.EstimateMinutes,
 		arg.ID,
+		pq.Array(arg.IfUpdatedAt),
 	)
 	var i Issue
 	err := row.Scan(
@@ -167,6 +1333,19 @@ func (q *Queries) UpdateIssue(ctx context.Context, arg UpdateIssueParams) (Issue
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ColumnID,
+		&i.SearchVector,
+		&i.ReporterID,
+		&i.Priority,
+		&i.DueDate,
+		&i.Rank,
+		&i.ParentID,
+		&i.Number,
+		&i.EstimatePoints,
+		&i.EstimateMinutes,
+		&i.SprintID,
+		&i.MilestoneID,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
@@ -11,6 +11,12 @@ import (
 	"github.com/guregu/null"
 )
 
+type ColumnTransition struct {
+	FromColumnID int64     `db:"from_column_id" json:"from_column_id"`
+	ToColumnID   int64     `db:"to_column_id" json:"to_column_id"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
 type Conversation struct {
 	ID        int64     `db:"id" json:"id"`
 	UserID    int64     `db:"user_id" json:"user_id"`
@@ -22,13 +28,102 @@ type Conversation struct {
 	TeamID    int64     `db:"team_id" json:"team_id"`
 }
 
+type CustomField struct {
+	ID        int64     `db:"id" json:"id"`
+	ProjectID int64     `db:"project_id" json:"project_id"`
+	Name      string    `db:"name" json:"name"`
+	FieldType string    `db:"field_type" json:"field_type"`
+	Options   []string  `db:"options" json:"options"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type Issue struct {
-	ID          int64       `db:"id" json:"id"`
-	Name        string      `db:"name" json:"name"`
-	Description null.String `db:"description" json:"description"`
-	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
-	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
-	ColumnID    int64       `db:"column_id" json:"column_id"`
+	ID              int64       `db:"id" json:"id"`
+	Name            string      `db:"name" json:"name"`
+	Description     null.String `db:"description" json:"description"`
+	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+	ColumnID        int64       `db:"column_id" json:"column_id"`
+	SearchVector    string      `db:"search_vector" json:"-"`
+	ReporterID      null.Int    `db:"reporter_id" json:"reporter_id"`
+	Priority        string      `db:"priority" json:"priority"`
+	DueDate         null.Time   `db:"due_date" json:"due_date"`
+	Rank            string      `db:"rank" json:"rank"`
+	ParentID        null.Int    `db:"parent_id" json:"parent_id"`
+	Number          int32       `db:"number" json:"number"`
+	EstimatePoints  null.Int    `db:"estimate_points" json:"estimate_points"`
+	EstimateMinutes null.Int    `db:"estimate_minutes" json:"estimate_minutes"`
+	SprintID        null.Int    `db:"sprint_id" json:"sprint_id"`
+	MilestoneID     null.Int    `db:"milestone_id" json:"milestone_id"`
+	DeletedAt       null.Time   `db:"deleted_at" json:"-"`
+	ArchivedAt      null.Time   `db:"archived_at" json:"archived_at"`
+}
+
+type IssueActivity struct {
+	ID        int64       `db:"id" json:"id"`
+	IssueID   int64       `db:"issue_id" json:"issue_id"`
+	ActorID   null.Int    `db:"actor_id" json:"actor_id"`
+	Action    string      `db:"action" json:"action"`
+	Field     null.String `db:"field" json:"field"`
+	OldValue  null.String `db:"old_value" json:"old_value"`
+	NewValue  null.String `db:"new_value" json:"new_value"`
+	CreatedAt time.Time   `db:"created_at" json:"created_at"`
+}
+
+type IssueAssignee struct {
+	IssueID   int64     `db:"issue_id" json:"issue_id"`
+	UserID    int64     `db:"user_id" json:"user_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type IssueComment struct {
+	ID                int64     `db:"id" json:"id"`
+	IssueID           int64     `db:"issue_id" json:"issue_id"`
+	ParentID          null.Int  `db:"parent_id" json:"parent_id"`
+	AuthorID          null.Int  `db:"author_id" json:"author_id"`
+	Body              string    `db:"body" json:"body"`
+	HasSerializedBody bool      `db:"has_serialized_body" json:"has_serialized_body"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type IssueCommentMention struct {
+	CommentID int64 `db:"comment_id" json:"comment_id"`
+	UserID    int64 `db:"user_id" json:"user_id"`
+}
+
+type IssueCustomFieldValue struct {
+	IssueID      int64           `db:"issue_id" json:"issue_id"`
+	FieldID      int64           `db:"field_id" json:"field_id"`
+	TextValue    null.String     `db:"text_value" json:"text_value"`
+	NumberValue  sql.NullFloat64 `db:"number_value" json:"number_value"`
+	DateValue    null.Time       `db:"date_value" json:"date_value"`
+	UserID       null.Int        `db:"user_id" json:"user_id"`
+	OptionValues []string        `db:"option_values" json:"option_values"`
+}
+
+type IssueLabel struct {
+	IssueID   int64     `db:"issue_id" json:"issue_id"`
+	LabelID   int64     `db:"label_id" json:"label_id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type IssueLink struct {
+	ID            int64     `db:"id" json:"id"`
+	IssueID       int64     `db:"issue_id" json:"issue_id"`
+	LinkedIssueID int64     `db:"linked_issue_id" json:"linked_issue_id"`
+	LinkType      string    `db:"link_type" json:"link_type"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+type Label struct {
+	ID        int64     `db:"id" json:"id"`
+	TeamID    int64     `db:"team_id" json:"team_id"`
+	Name      string    `db:"name" json:"name"`
+	Color     string    `db:"color" json:"color"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type Message struct {
@@ -40,21 +135,56 @@ type Message struct {
 	CreatedAt      time.Time `db:"created_at" json:"created_at"`
 }
 
+type Milestone struct {
+	ID          int64       `db:"id" json:"id"`
+	ProjectID   int64       `db:"project_id" json:"project_id"`
+	Name        string      `db:"name" json:"name"`
+	Description null.String `db:"description" json:"description"`
+	TargetDate  null.Time   `db:"target_date" json:"target_date"`
+	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
+}
+
 type Project struct {
-	ID        int64     `db:"id" json:"id"`
-	Name      string    `db:"name" json:"name"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
-	TeamID    int64     `db:"team_id" json:"team_id"`
+	ID              int64     `db:"id" json:"id"`
+	Name            string    `db:"name" json:"name"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	TeamID          int64     `db:"team_id" json:"team_id"`
+	WipLimitMode    string    `db:"wip_limit_mode" json:"wip_limit_mode"`
+	KeyPrefix       string    `db:"key_prefix" json:"key_prefix"`
+	NextIssueNumber int32     `db:"next_issue_number" json:"next_issue_number"`
+	DeletedAt       null.Time `db:"deleted_at" json:"-"`
+	ArchivedAt      null.Time `db:"archived_at" json:"archived_at"`
+}
+
+type ProjectReport struct {
+	ID          int64     `db:"id" json:"id"`
+	ProjectID   int64     `db:"project_id" json:"project_id"`
+	CreatedBy   null.Int  `db:"created_by" json:"created_by"`
+	Provider    string    `db:"provider" json:"provider"`
+	Model       string    `db:"model" json:"model"`
+	PeriodStart time.Time `db:"period_start" json:"period_start"`
+	PeriodEnd   time.Time `db:"period_end" json:"period_end"`
+	Summary     string    `db:"summary" json:"summary"`
+	Done        string    `db:"done" json:"done"`
+	InProgress  string    `db:"in_progress" json:"in_progress"`
+	Blocked     string    `db:"blocked" json:"blocked"`
+	Risks       string    `db:"risks" json:"risks"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 }
 
 type ProjectStatusColumn struct {
-	ID            int64     `db:"id" json:"id"`
-	ProjectID     int32     `db:"project_id" json:"project_id"`
-	Name          string    `db:"name" json:"name"`
-	PositionIndex int16     `db:"position_index" json:"position_index"`
-	CreatedAt     time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	ID             int64     `db:"id" json:"id"`
+	ProjectID      int32     `db:"project_id" json:"project_id"`
+	Name           string    `db:"name" json:"name"`
+	PositionIndex  int16     `db:"position_index" json:"position_index"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	WipLimit       null.Int  `db:"wip_limit" json:"wip_limit"`
+	RequiredFields []string  `db:"required_fields" json:"required_fields"`
+	IsDone         bool      `db:"is_done" json:"is_done"`
+	DeletedAt      null.Time `db:"deleted_at" json:"-"`
 }
 
 type RefreshToken struct {
@@ -66,6 +196,20 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime `db:"revoked_at" json:"revoked_at"`
 }
 
+type Sprint struct {
+	ID          int64       `db:"id" json:"id"`
+	ProjectID   int64       `db:"project_id" json:"project_id"`
+	Name        string      `db:"name" json:"name"`
+	Goal        null.String `db:"goal" json:"goal"`
+	StartDate   time.Time   `db:"start_date" json:"start_date"`
+	EndDate     time.Time   `db:"end_date" json:"end_date"`
+	Status      string      `db:"status" json:"status"`
+	StartedAt   null.Time   `db:"started_at" json:"started_at"`
+	CompletedAt null.Time   `db:"completed_at" json:"completed_at"`
+	CreatedAt   time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time   `db:"updated_at" json:"updated_at"`
+}
+
 type Team struct {
 	ID          int64       `db:"id" json:"id"`
 	Name        string      `db:"name" json:"name"`
@@ -92,6 +236,17 @@ type TeamsLlmApiKey struct {
 	LastUsedAt   sql.NullTime `db:"last_used_at" json:"last_used_at"`
 }
 
+type TimeEntry struct {
+	ID        int64       `db:"id" json:"id"`
+	IssueID   int64       `db:"issue_id" json:"issue_id"`
+	UserID    int64       `db:"user_id" json:"user_id"`
+	StartedAt time.Time   `db:"started_at" json:"started_at"`
+	EndedAt   null.Time   `db:"ended_at" json:"ended_at"`
+	Minutes   null.Int    `db:"minutes" json:"minutes"`
+	Note      null.String `db:"note" json:"note"`
+	CreatedAt time.Time   `db:"created_at" json:"created_at"`
+}
+
 type User struct {
 	ID           int64     `db:"id" json:"id"`
 	Email        string    `db:"email" json:"email"`
@@ -7,27 +7,41 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
 )
 
 const createProjectStatusColumn = `-- name: CreateProjectStatusColumn :one
-INSERT INTO project_status_columns (project_id, name, position_index, created_at, updated_at)
+INSERT INTO project_status_columns (project_id, name, position_index, wip_limit, required_fields, is_done, created_at, updated_at)
     VALUES ($1, $2, COALESCE((
             SELECT
                 MAX(position_index + 1)
             FROM project_status_columns
             WHERE
-                project_id = $1), 0), NOW(), NOW())
+                project_id = $1
+                AND deleted_at IS NULL), 0), $3, COALESCE($4::text[], '{}'), $5, NOW(), NOW())
 RETURNING
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 `
 
 type CreateProjectStatusColumnParams struct {
-	ProjectID int32  `db:"project_id" json:"project_id"`
-	Name      string `db:"name" json:"name"`
+	ProjectID      int32    `db:"project_id" json:"project_id"`
+	Name           string   `db:"name" json:"name"`
+	WipLimit       null.Int `db:"wip_limit" json:"wip_limit"`
+	RequiredFields []string `db:"required_fields" json:"required_fields"`
+	IsDone         bool     `db:"is_done" json:"is_done"`
 }
 
 func (q *Queries) CreateProjectStatusColumn(ctx context.Context, arg CreateProjectStatusColumnParams) (ProjectStatusColumn, error) {
-	row := q.db.QueryRowContext(ctx, createProjectStatusColumn, arg.ProjectID, arg.Name)
+	row := q.db.QueryRowContext(ctx, createProjectStatusColumn,
+		arg.ProjectID,
+		arg.Name,
+		arg.WipLimit,
+		pq.Array(arg.RequiredFields),
+		arg.IsDone,
+	)
 	var i ProjectStatusColumn
 	err := row.Scan(
 		&i.ID,
@@ -36,19 +50,35 @@ func (q *Queries) CreateProjectStatusColumn(ctx context.Context, arg CreateProje
 		&i.PositionIndex,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WipLimit,
+		pq.Array(&i.RequiredFields),
+		&i.IsDone,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const deleteProjectStatusColumn = `-- name: DeleteProjectStatusColumn :one
-DELETE FROM project_status_columns
-WHERE id = $1
+UPDATE
+    project_status_columns
+SET
+    deleted_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+    AND ($2::timestamp[] IS NULL
+        OR updated_at = ANY ($2::timestamp[]))
 RETURNING
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 `
 
-func (q *Queries) DeleteProjectStatusColumn(ctx context.Context, id int64) (ProjectStatusColumn, error) {
-	row := q.db.QueryRowContext(ctx, deleteProjectStatusColumn, id)
+type DeleteProjectStatusColumnParams struct {
+	ID          int64       `db:"id" json:"id"`
+	IfUpdatedAt []time.Time `db:"if_updated_at" json:"if_updated_at"`
+}
+
+func (q *Queries) DeleteProjectStatusColumn(ctx context.Context, arg DeleteProjectStatusColumnParams) (ProjectStatusColumn, error) {
+	row := q.db.QueryRowContext(ctx, deleteProjectStatusColumn, arg.ID, pq.Array(arg.IfUpdatedAt))
 	var i ProjectStatusColumn
 	err := row.Scan(
 		&i.ID,
@@ -57,15 +87,21 @@ func (q *Queries) DeleteProjectStatusColumn(ctx context.Context, id int64) (Proj
 		&i.PositionIndex,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WipLimit,
+		pq.Array(&i.RequiredFields),
+		&i.IsDone,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getAllProjectStatusColumns = `-- name: GetAllProjectStatusColumns :many
 SELECT
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 FROM
     project_status_columns
+WHERE
+    deleted_at IS NULL
 ORDER BY
     project_id,
     position_index
@@ -87,6 +123,10 @@ func (q *Queries) GetAllProjectStatusColumns(ctx context.Context) ([]ProjectStat
 			&i.PositionIndex,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WipLimit,
+			pq.Array(&i.RequiredFields),
+			&i.IsDone,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -101,6 +141,50 @@ func (q *Queries) GetAllProjectStatusColumns(ctx context.Context) ([]ProjectStat
 	return items, nil
 }
 
+const getColumnIssueCountsByProjectID = `-- name: GetColumnIssueCountsByProjectID :many
+SELECT
+    psc.id AS column_id,
+    COUNT(issues.id) AS issue_count
+FROM
+    project_status_columns psc
+    LEFT JOIN issues ON issues.column_id = psc.id
+        AND issues.deleted_at IS NULL
+        AND issues.archived_at IS NULL
+WHERE
+    psc.project_id = $1
+    AND psc.deleted_at IS NULL
+GROUP BY
+    psc.id
+`
+
+type GetColumnIssueCountsByProjectIDRow struct {
+	ColumnID   int64 `db:"column_id" json:"column_id"`
+	IssueCount int64 `db:"issue_count" json:"issue_count"`
+}
+
+func (q *Queries) GetColumnIssueCountsByProjectID(ctx context.Context, projectID int32) ([]GetColumnIssueCountsByProjectIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getColumnIssueCountsByProjectID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetColumnIssueCountsByProjectIDRow
+	for rows.Next() {
+		var i GetColumnIssueCountsByProjectIDRow
+		if err := rows.Scan(&i.ColumnID, &i.IssueCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNextColumnForReassignment = `-- name: GetNextColumnForReassignment :one
 SELECT
     id
@@ -109,6 +193,7 @@ FROM
 WHERE
     project_id = $1
     AND position_index = $2+ 1
+    AND deleted_at IS NULL
 LIMIT 1
 `
 
@@ -126,11 +211,12 @@ func (q *Queries) GetNextColumnForReassignment(ctx context.Context, arg GetNextC
 
 const getProjectStatusColumnByID = `-- name: GetProjectStatusColumnByID :one
 SELECT
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 FROM
     project_status_columns
 WHERE
     id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectStatusColumnByID(ctx context.Context, id int64) (ProjectStatusColumn, error) {
@@ -143,6 +229,10 @@ func (q *Queries) GetProjectStatusColumnByID(ctx context.Context, id int64) (Pro
 		&i.PositionIndex,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WipLimit,
+		pq.Array(&i.RequiredFields),
+		&i.IsDone,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -154,6 +244,7 @@ FROM
     project_status_columns
 WHERE
     project_id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectStatusColumnCountByProjectID(ctx context.Context, projectID int32) (int64, error) {
@@ -165,11 +256,12 @@ func (q *Queries) GetProjectStatusColumnCountByProjectID(ctx context.Context, pr
 
 const getProjectStatusColumnsByProjectID = `-- name: GetProjectStatusColumnsByProjectID :many
 SELECT
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 FROM
     project_status_columns
 WHERE
     project_id = $1
+    AND deleted_at IS NULL
 ORDER BY
     position_index
 `
@@ -190,6 +282,107 @@ func (q *Queries) GetProjectStatusColumnsByProjectID(ctx context.Context, projec
 			&i.PositionIndex,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WipLimit,
+			pq.Array(&i.RequiredFields),
+			&i.IsDone,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockColumnForWIPCheck = `-- name: LockColumnForWIPCheck :one
+SELECT
+    psc.wip_limit,
+    p.wip_limit_mode,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            issues
+        WHERE
+            issues.column_id = psc.id
+            AND issues.deleted_at IS NULL
+            AND issues.archived_at IS NULL) AS issue_count
+FROM
+    project_status_columns psc
+    JOIN projects p ON p.id = psc.project_id
+WHERE
+    psc.id = $1
+FOR UPDATE OF psc
+`
+
+type LockColumnForWIPCheckRow struct {
+	WipLimit     null.Int `db:"wip_limit" json:"wip_limit"`
+	WipLimitMode string   `db:"wip_limit_mode" json:"wip_limit_mode"`
+	IssueCount   int64    `db:"issue_count" json:"issue_count"`
+}
+
+func (q *Queries) LockColumnForWIPCheck(ctx context.Context, id int64) (LockColumnForWIPCheckRow, error) {
+	row := q.db.QueryRowContext(ctx, lockColumnForWIPCheck, id)
+	var i LockColumnForWIPCheckRow
+	err := row.Scan(&i.WipLimit, &i.WipLimitMode, &i.IssueCount)
+	return i, err
+}
+
+const lockProjectStatusColumn = `-- name: LockProjectStatusColumn :one
+SELECT
+    id
+FROM
+    project_status_columns
+WHERE
+    id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockProjectStatusColumn(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, lockProjectStatusColumn, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const lockProjectStatusColumnsByProjectID = `-- name: LockProjectStatusColumnsByProjectID :many
+SELECT
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
+FROM
+    project_status_columns
+WHERE
+    project_id = $1
+    AND deleted_at IS NULL
+ORDER BY
+    id
+FOR UPDATE
+`
+
+func (q *Queries) LockProjectStatusColumnsByProjectID(ctx context.Context, projectID int32) ([]ProjectStatusColumn, error) {
+	rows, err := q.db.QueryContext(ctx, lockProjectStatusColumnsByProjectID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProjectStatusColumn
+	for rows.Next() {
+		var i ProjectStatusColumn
+		if err := rows.Scan(
+			&i.ID,
+			&i.ProjectID,
+			&i.Name,
+			&i.PositionIndex,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.WipLimit,
+			pq.Array(&i.RequiredFields),
+			&i.IsDone,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -204,6 +397,26 @@ func (q *Queries) GetProjectStatusColumnsByProjectID(ctx context.Context, projec
 	return items, nil
 }
 
+const setProjectStatusColumnPosition = `-- name: SetProjectStatusColumnPosition :exec
+UPDATE
+    project_status_columns
+SET
+    position_index = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+`
+
+type SetProjectStatusColumnPositionParams struct {
+	ID            int64 `db:"id" json:"id"`
+	PositionIndex int16 `db:"position_index" json:"position_index"`
+}
+
+func (q *Queries) SetProjectStatusColumnPosition(ctx context.Context, arg SetProjectStatusColumnPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setProjectStatusColumnPosition, arg.ID, arg.PositionIndex)
+	return err
+}
+
 const shiftColumnsLeft = `-- name: ShiftColumnsLeft :exec
 UPDATE
     project_status_columns
@@ -212,6 +425,7 @@ SET
 WHERE
     project_id = $1
     AND position_index > $2
+    AND deleted_at IS NULL
 `
 
 type ShiftColumnsLeftParams struct {
@@ -224,27 +438,65 @@ func (q *Queries) ShiftColumnsLeft(ctx context.Context, arg ShiftColumnsLeftPara
 	return err
 }
 
+const shiftColumnsRight = `-- name: ShiftColumnsRight :exec
+UPDATE
+    project_status_columns
+SET
+    position_index = position_index + 1
+WHERE
+    project_id = $1
+    AND position_index >= $2
+    AND deleted_at IS NULL
+`
+
+type ShiftColumnsRightParams struct {
+	ProjectID     int32 `db:"project_id" json:"project_id"`
+	PositionIndex int16 `db:"position_index" json:"position_index"`
+}
+
+func (q *Queries) ShiftColumnsRight(ctx context.Context, arg ShiftColumnsRightParams) error {
+	_, err := q.db.ExecContext(ctx, shiftColumnsRight, arg.ProjectID, arg.PositionIndex)
+	return err
+}
+
 const updateProjectStatusColumn = `-- name: UpdateProjectStatusColumn :one
 UPDATE
     project_status_columns
 SET
-    name = $2,
-    position_index = $3,
+    name = $1,
+    position_index = $2,
+    wip_limit = $3,
+    required_fields = COALESCE($4::text[], '{}'),
+    is_done = $5,
     updated_at = NOW()
 WHERE
-    id = $1
+    id = $6
+    AND ($7::timestamp[] IS NULL
+        OR updated_at = ANY ($7::timestamp[]))
 RETURNING
-    id, project_id, name, position_index, created_at, updated_at
+    id, project_id, name, position_index, created_at, updated_at, wip_limit, required_fields, is_done, deleted_at
 `
 
 type UpdateProjectStatusColumnParams struct {
-	ID            int64  `db:"id" json:"id"`
-	Name          string `db:"name" json:"name"`
-	PositionIndex int16  `db:"position_index" json:"position_index"`
+	Name           string      `db:"name" json:"name"`
+	PositionIndex  int16       `db:"position_index" json:"position_index"`
+	WipLimit       null.Int    `db:"wip_limit" json:"wip_limit"`
+	RequiredFields []string    `db:"required_fields" json:"required_fields"`
+	IsDone         bool        `db:"is_done" json:"is_done"`
+	ID             int64       `db:"id" json:"id"`
+	IfUpdatedAt    []time.Time `db:"if_updated_at" json:"if_updated_at"`
 }
 
 func (q *Queries) UpdateProjectStatusColumn(ctx context.Context, arg UpdateProjectStatusColumnParams) (ProjectStatusColumn, error) {
-	row := q.db.QueryRowContext(ctx, updateProjectStatusColumn, arg.ID, arg.Name, arg.PositionIndex)
+	row := q.db.QueryRowContext(ctx, updateProjectStatusColumn,
+		arg.Name,
+		arg.PositionIndex,
+		arg.WipLimit,
+		pq.Array(arg.RequiredFields),
+		arg.IsDone,
+		arg.ID,
+		pq.Array(arg.IfUpdatedAt),
+	)
 	var i ProjectStatusColumn
 	err := row.Scan(
 		&i.ID,
@@ -253,6 +505,10 @@ func (q *Queries) UpdateProjectStatusColumn(ctx context.Context, arg UpdateProje
 		&i.PositionIndex,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WipLimit,
+		pq.Array(&i.RequiredFields),
+		&i.IsDone,
+		&i.DeletedAt,
 	)
 	return i, err
 }
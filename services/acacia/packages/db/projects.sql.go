@@ -7,22 +7,59 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
 )
 
+const archiveProject = `-- name: ArchiveProject :one
+UPDATE
+    projects
+SET
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
+`
+
+func (q *Queries) ArchiveProject(ctx context.Context, id int64) (Project, error) {
+	row := q.db.QueryRowContext(ctx, archiveProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
 const createProject = `-- name: CreateProject :one
-INSERT INTO projects (name, team_id, created_at, updated_at)
-    VALUES ($1, $2, NOW(), NOW())
+INSERT INTO projects (name, team_id, key_prefix, created_at, updated_at)
+    VALUES ($1, $2, $3, NOW(), NOW())
 RETURNING
-    id, name, created_at, updated_at, team_id
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
 `
 
 type CreateProjectParams struct {
-	Name   string `db:"name" json:"name"`
-	TeamID int64  `db:"team_id" json:"team_id"`
+	Name      string `db:"name" json:"name"`
+	TeamID    int64  `db:"team_id" json:"team_id"`
+	KeyPrefix string `db:"key_prefix" json:"key_prefix"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
-	row := q.db.QueryRowContext(ctx, createProject, arg.Name, arg.TeamID)
+	row := q.db.QueryRowContext(ctx, createProject, arg.Name, arg.TeamID, arg.KeyPrefix)
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -30,19 +67,36 @@ func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
 const deleteProject = `-- name: DeleteProject :one
-DELETE FROM projects
-WHERE id = $1
+UPDATE
+    projects
+SET
+    deleted_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+    AND ($2::timestamp[] IS NULL
+        OR updated_at = ANY ($2::timestamp[]))
 RETURNING
-    id, name, created_at, updated_at, team_id
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
 `
 
-func (q *Queries) DeleteProject(ctx context.Context, id int64) (Project, error) {
-	row := q.db.QueryRowContext(ctx, deleteProject, id)
+type DeleteProjectParams struct {
+	ID          int64       `db:"id" json:"id"`
+	IfUpdatedAt []time.Time `db:"if_updated_at" json:"if_updated_at"`
+}
+
+func (q *Queries) DeleteProject(ctx context.Context, arg DeleteProjectParams) (Project, error) {
+	row := q.db.QueryRowContext(ctx, deleteProject, arg.ID, pq.Array(arg.IfUpdatedAt))
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -50,17 +104,23 @@ func (q *Queries) DeleteProject(ctx context.Context, id int64) (Project, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
 const getProjectByID = `-- name: GetProjectByID :one
 SELECT
-    id, name, created_at, updated_at, team_id
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
 FROM
     projects
 WHERE
     id = $1
+    AND deleted_at IS NULL
 `
 
 func (q *Queries) GetProjectByID(ctx context.Context, id int64) (Project, error) {
@@ -72,22 +132,91 @@ func (q *Queries) GetProjectByID(ctx context.Context, id int64) (Project, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
 
 const getProjectIssues = `-- name: GetProjectIssues :many
 SELECT
-    issues.id, issues.name, issues.description, issues.created_at, issues.updated_at, issues.column_id
+    issues.id, issues.name, issues.description, issues.created_at, issues.updated_at, issues.column_id, issues.search_vector, issues.reporter_id, issues.priority, issues.due_date, issues.rank, issues.parent_id, issues.number, issues.estimate_points, issues.estimate_minutes, issues.sprint_id, issues.milestone_id, issues.deleted_at, issues.archived_at
 FROM
     project_status_columns
     JOIN issues ON project_status_columns.id = issues.column_id
 WHERE
     project_id = $1
+    AND issues.deleted_at IS NULL
+    AND ($2::text IS NULL
+        OR issues.priority = $2::text)
+    AND ($3::bigint IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                issue_labels il
+            WHERE
+                il.issue_id = issues.id
+                AND il.label_id = $3::bigint))
+    AND ($4::date IS NULL
+        OR issues.due_date >= $4::date)
+    AND ($5::date IS NULL
+        OR issues.due_date < $5::date)
+    AND ($6::bigint IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                issue_custom_field_values v
+            WHERE
+                v.issue_id = issues.id
+                AND v.field_id = $6::bigint
+                AND (lower(v.text_value) = lower($7::text)
+                    OR v.number_value = $8::float8
+                    OR v.date_value = $9::date
+                    OR v.user_id = $10::bigint
+                    OR v.option_values @> ARRAY[$11::text])))
+    AND ($12::boolean
+        OR issues.archived_at IS NULL)
+ORDER BY
+    project_status_columns.position_index,
+    issues.rank,
+    issues.id
 `
 
-func (q *Queries) GetProjectIssues(ctx context.Context, projectID int32) ([]Issue, error) {
-	rows, err := q.db.QueryContext(ctx, getProjectIssues, projectID)
+type GetProjectIssuesParams struct {
+	ProjectID         int32           `db:"project_id" json:"project_id"`
+	Priority          null.String     `db:"priority" json:"priority"`
+	LabelID           null.Int        `db:"label_id" json:"label_id"`
+	DueAfter          null.Time       `db:"due_after" json:"due_after"`
+	DueBefore         null.Time       `db:"due_before" json:"due_before"`
+	CustomFieldID     null.Int        `db:"custom_field_id" json:"custom_field_id"`
+	CustomFieldText   null.String     `db:"custom_field_text" json:"custom_field_text"`
+	CustomFieldNumber sql.NullFloat64 `db:"custom_field_number" json:"custom_field_number"`
+	CustomFieldDate   null.Time       `db:"custom_field_date" json:"custom_field_date"`
+	CustomFieldUserID null.Int        `db:"custom_field_user_id" json:"custom_field_user_id"`
+	CustomFieldOption null.String     `db:"custom_field_option" json:"custom_field_option"`
+	IncludeArchived   bool            `db:"include_archived" json:"include_archived"`
+}
+
+func (q *Queries) GetProjectIssues(ctx context.Context, arg GetProjectIssuesParams) ([]Issue, error) {
+	rows, err := q.db.QueryContext(ctx, getProjectIssues,
+		arg.ProjectID,
+		arg.Priority,
+		arg.LabelID,
+		arg.DueAfter,
+		arg.DueBefore,
+		arg.CustomFieldID,
+		arg.CustomFieldText,
+		arg.CustomFieldNumber,
+		arg.CustomFieldDate,
+		arg.CustomFieldUserID,
+		arg.CustomFieldOption,
+		arg.IncludeArchived,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -102,6 +231,19 @@ func (q *Queries) GetProjectIssues(ctx context.Context, projectID int32) ([]Issu
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.ColumnID,
+			&i.SearchVector,
+			&i.ReporterID,
+			&i.Priority,
+			&i.DueDate,
+			&i.Rank,
+			&i.ParentID,
+			&i.Number,
+			&i.EstimatePoints,
+			&i.EstimateMinutes,
+			&i.SprintID,
+			&i.MilestoneID,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -118,16 +260,24 @@ func (q *Queries) GetProjectIssues(ctx context.Context, projectID int32) ([]Issu
 
 const getProjects = `-- name: GetProjects :many
 SELECT
-    p.id, p.name, p.created_at, p.updated_at, p.team_id
+    p.id, p.name, p.created_at, p.updated_at, p.team_id, p.wip_limit_mode, p.key_prefix, p.next_issue_number, p.deleted_at, p.archived_at
 FROM
     projects p
     JOIN team_members tm ON p.team_id = tm.team_id
 WHERE
     tm.user_id = $1
+    AND p.deleted_at IS NULL
+    AND ($2::boolean
+        OR p.archived_at IS NULL)
 `
 
-func (q *Queries) GetProjects(ctx context.Context, userID int64) ([]Project, error) {
-	rows, err := q.db.QueryContext(ctx, getProjects, userID)
+type GetProjectsParams struct {
+	UserID          int64 `db:"user_id" json:"user_id"`
+	IncludeArchived bool  `db:"include_archived" json:"include_archived"`
+}
+
+func (q *Queries) GetProjects(ctx context.Context, arg GetProjectsParams) ([]Project, error) {
+	rows, err := q.db.QueryContext(ctx, getProjects, arg.UserID, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -141,6 +291,11 @@ func (q *Queries) GetProjects(ctx context.Context, userID int64) ([]Project, err
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.TeamID,
+			&i.WipLimitMode,
+			&i.KeyPrefix,
+			&i.NextIssueNumber,
+			&i.DeletedAt,
+			&i.ArchivedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -155,25 +310,66 @@ func (q *Queries) GetProjects(ctx context.Context, userID int64) ([]Project, err
 	return items, nil
 }
 
-const updateProject = `-- name: UpdateProject :one
+const unarchiveProject = `-- name: UnarchiveProject :one
 UPDATE
     projects
 SET
-    name = $2,
+    archived_at = NULL,
     updated_at = NOW()
 WHERE
     id = $1
+    AND deleted_at IS NULL
 RETURNING
-    id, name, created_at, updated_at, team_id
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
+`
+
+func (q *Queries) UnarchiveProject(ctx context.Context, id int64) (Project, error) {
+	row := q.db.QueryRowContext(ctx, unarchiveProject, id)
+	var i Project
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
+	)
+	return i, err
+}
+
+const updateProject = `-- name: UpdateProject :one
+UPDATE
+    projects
+SET
+    name = $1,
+    wip_limit_mode = COALESCE($2::text, wip_limit_mode),
+    updated_at = NOW()
+WHERE
+    id = $3
+    AND ($4::timestamp[] IS NULL
+        OR updated_at = ANY ($4::timestamp[]))
+RETURNING
+    id, name, created_at, updated_at, team_id, wip_limit_mode, key_prefix, next_issue_number, deleted_at, archived_at
 `
 
 type UpdateProjectParams struct {
-	ID   int64  `db:"id" json:"id"`
-	Name string `db:"name" json:"name"`
+	Name         string      `db:"name" json:"name"`
+	WipLimitMode null.String `db:"wip_limit_mode" json:"wip_limit_mode"`
+	ID           int64       `db:"id" json:"id"`
+	IfUpdatedAt  []time.Time `db:"if_updated_at" json:"if_updated_at"`
 }
 
 func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (Project, error) {
-	row := q.db.QueryRowContext(ctx, updateProject, arg.ID, arg.Name)
+	row := q.db.QueryRowContext(ctx, updateProject,
+		arg.Name,
+		arg.WipLimitMode,
+		arg.ID,
+		pq.Array(arg.IfUpdatedAt),
+	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
@@ -181,6 +377,11 @@ func (q *Queries) UpdateProject(ctx context.Context, arg UpdateProjectParams) (P
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.TeamID,
+		&i.WipLimitMode,
+		&i.KeyPrefix,
+		&i.NextIssueNumber,
+		&i.DeletedAt,
+		&i.ArchivedAt,
 	)
 	return i, err
 }
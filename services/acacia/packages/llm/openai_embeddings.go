@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openai/openai-go"
+)
+
+const openAIEmbeddingModel = openai.EmbeddingModelTextEmbedding3Small
+
+// Embed returns embeddings for the inputs using text-embedding-3-small
+func (p *OpenAIProvider) Embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	if len(inputs) == 0 {
+		return [][]float64{}, nil
+	}
+
+	response, err := p.client.Embeddings.New(ctx, openai.EmbeddingNewParams{
+		Input: openai.EmbeddingNewParamsInputUnion{
+			OfArrayOfStrings: inputs,
+		},
+		Model: openAIEmbeddingModel,
+	})
+	if err != nil {
+		var openaiErr *openai.Error
+		if errors.As(err, &openaiErr) {
+			switch openaiErr.StatusCode {
+			case 401:
+				return nil, ErrInvalidAPIKey
+			case 429:
+				return nil, ErrRateLimitExceeded
+			}
+		}
+		return nil, err
+	}
+
+	if len(response.Data) != len(inputs) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(inputs), len(response.Data))
+	}
+
+	vectors := make([][]float64, len(inputs))
+	for _, embedding := range response.Data {
+		if embedding.Index < 0 || int(embedding.Index) >= len(inputs) {
+			return nil, fmt.Errorf("embedding index %d out of range", embedding.Index)
+		}
+		vectors[embedding.Index] = embedding.Embedding
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openai/openai-go"
+)
+
+// CompleteStructured generates a completion constrained to the given JSON schema
+func (p *OpenAIProvider) CompleteStructured(
+	ctx context.Context,
+	messages []Message,
+	model string,
+	schema StructuredOutputSchema,
+) (string, error) {
+	completion, err := p.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
+		Messages: convertToOpenAIMessages(messages),
+		Model:    openai.ChatModel(model),
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
+				JSONSchema: openai.ResponseFormatJSONSchemaJSONSchemaParam{
+					Name:        schema.Name,
+					Description: openai.String(schema.Description),
+					Schema:      schema.Schema,
+					Strict:      openai.Bool(true),
+				},
+			},
+		},
+	})
+	if err != nil {
+		var openaiErr *openai.Error
+		if errors.As(err, &openaiErr) {
+			switch openaiErr.StatusCode {
+			case 401:
+				return "", ErrInvalidAPIKey
+			case 429:
+				return "", ErrRateLimitExceeded
+			}
+		}
+		return "", err
+	}
+
+	if len(completion.Choices) == 0 {
+		return "", errors.New("empty completion response")
+	}
+
+	message := completion.Choices[0].Message
+	if message.Refusal != "" {
+		return "", errors.New("model refused to respond: " + message.Refusal)
+	}
+
+	return message.Content, nil
+}
@@ -8,10 +8,11 @@ import (
 )
 
 var (
-	ErrProviderNotSupported = errors.New("provider not supported")
-	ErrAPIKeyNotFound       = errors.New("API key not found for provider")
-	ErrInvalidAPIKey        = errors.New("invalid API key")
-	ErrRateLimitExceeded    = errors.New("rate limit exceeded")
+	ErrProviderNotSupported         = errors.New("provider not supported")
+	ErrAPIKeyNotFound               = errors.New("API key not found for provider")
+	ErrInvalidAPIKey                = errors.New("invalid API key")
+	ErrRateLimitExceeded            = errors.New("rate limit exceeded")
+	ErrStructuredOutputNotSupported = errors.New("provider does not support structured output")
 )
 
 // Message represents a chat message in a provider-agnostic format
@@ -42,6 +43,26 @@ type LLMResponseStreamer interface {
 	GetProviderName() string
 }
 
+// StructuredOutputSchema describes the JSON document a structured completion must return
+type StructuredOutputSchema struct {
+	Name        string         // Identifier for the schema (letters, digits, underscores and dashes)
+	Description string         // What the document is for, shown to the model
+	Schema      map[string]any // JSON schema of the expected document
+}
+
+// StructuredCompleter is implemented by providers that can return a JSON document
+// conforming to a schema instead of free-form text
+type StructuredCompleter interface {
+	// CompleteStructured generates a single non-streamed completion and returns the raw JSON document
+	CompleteStructured(ctx context.Context, messages []Message, model string, schema StructuredOutputSchema) (string, error)
+}
+
+// Embedder is implemented by providers that can turn text into embedding vectors
+type Embedder interface {
+	// Embed returns one vector per input, in input order, using the provider's default embedding model
+	Embed(ctx context.Context, inputs []string) ([][]float64, error)
+}
+
 // ProviderFactory creates provider instances with an API key
 type ProviderFactory interface {
 	New(apiKey string, logger *logrus.Logger, tools *ToolRegistry) LLMResponseStreamer
@@ -63,6 +84,11 @@ func NewProviderRegistry(logger *logrus.Logger) *ProviderRegistry {
 	}
 }
 
+// Register adds or replaces the factory used for the named provider
+func (r *ProviderRegistry) Register(name string, factory ProviderFactory) {
+	r.factories[name] = factory
+}
+
 // GetProvider creates a provider instance with the given API key
 func (r *ProviderRegistry) GetProvider(name string, apiKey string, tools *ToolRegistry) (LLMResponseStreamer, error) {
 	factory, ok := r.factories[name]
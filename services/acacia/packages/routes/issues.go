@@ -8,29 +8,66 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func IssuesRoutes(controller *api.IssuesController, authMiddlewares chi.Middlewares, authzMiddleware *auth.AuthorizationMiddleware) chi.Router {
+func IssuesRoutes(controller *api.IssuesController, commentsController *api.IssueCommentsController, timeEntriesController *api.TimeEntriesController, sprintsController *api.SprintsController, milestonesController *api.MilestonesController, authMiddlewares chi.Middlewares, authzMiddleware *auth.AuthorizationMiddleware) chi.Router {
 	r := chi.NewRouter()
 
 	// Apply authentication middleware to all routes
 	r.Use(authMiddlewares...)
 
+	// GET /issues/search - results are scoped to the user's teams by the query itself
+	r.Get("/search", httperr.WithCustomErrorHandler(controller.SearchIssues))
+
+	// POST /issues/bulk - access to each listed issue is checked as it is processed
+	r.Post("/bulk", httperr.WithCustomErrorHandler(controller.BulkIssues))
+
+	// GET /issues/key/{key} - the handler checks access once the key is resolved
+	r.Get("/key/{key}", httperr.WithCustomErrorHandler(controller.GetIssueByKey))
+
 	// POST /issues - check access to the column_id from request body
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckColumnAccessByBody()))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckColumnWritableByBody()))
 		r.Post("/", httperr.WithCustomErrorHandler(controller.CreateIssue))
 	})
 
 	// PUT /issues - check access to the issue id from request body
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckIssueAccessByBody()))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckIssueWritableByBody()))
 		r.Put("/", httperr.WithCustomErrorHandler(controller.UpdateIssue))
 	})
 
-	// Routes that require issue-level authorization via URL parameter
+	// Routes that require issue-level authorization via URL parameter; issues of archived projects are read-only
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckIssueAccessByURLParam("id")))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckIssueWritableByURLParam("id")))
 		r.Get("/{id}", httperr.WithCustomErrorHandler(controller.GetIssueByID))
 		r.Delete("/{id}", httperr.WithCustomErrorHandler(controller.DeleteIssue))
+		r.Post("/{id}/archive", httperr.WithCustomErrorHandler(controller.ArchiveIssue))
+		r.Post("/{id}/unarchive", httperr.WithCustomErrorHandler(controller.UnarchiveIssue))
+		r.Post("/{id}/move", httperr.WithCustomErrorHandler(controller.MoveIssue))
+		r.Post("/{id}/move-to-project", httperr.WithCustomErrorHandler(controller.MoveIssueToProject))
+		r.Get("/{id}/activity", httperr.WithCustomErrorHandler(controller.GetIssueActivity))
+		r.Put("/{id}/parent", httperr.WithCustomErrorHandler(controller.SetIssueParent))
+		r.Put("/{id}/sprint", httperr.WithCustomErrorHandler(sprintsController.SetIssueSprint))
+		r.Put("/{id}/milestone", httperr.WithCustomErrorHandler(milestonesController.SetIssueMilestone))
+		r.Get("/{id}/children", httperr.WithCustomErrorHandler(controller.GetIssueChildren))
+		r.Get("/{id}/links", httperr.WithCustomErrorHandler(controller.GetIssueLinks))
+		r.Post("/{id}/links", httperr.WithCustomErrorHandler(controller.CreateIssueLink))
+		r.Delete("/{id}/links/{link_id}", httperr.WithCustomErrorHandler(controller.DeleteIssueLink))
+		r.Post("/{id}/assignees", httperr.WithCustomErrorHandler(controller.AssignIssue))
+		r.Delete("/{id}/assignees/{user_id}", httperr.WithCustomErrorHandler(controller.UnassignIssue))
+		r.Post("/{id}/labels", httperr.WithCustomErrorHandler(controller.AddIssueLabel))
+		r.Delete("/{id}/labels/{label_id}", httperr.WithCustomErrorHandler(controller.RemoveIssueLabel))
+		r.Get("/{id}/comments", httperr.WithCustomErrorHandler(commentsController.GetIssueComments))
+		r.Post("/{id}/comments", httperr.WithCustomErrorHandler(commentsController.CreateIssueComment))
+		r.Put("/{id}/comments/{comment_id}", httperr.WithCustomErrorHandler(commentsController.UpdateIssueComment))
+		r.Delete("/{id}/comments/{comment_id}", httperr.WithCustomErrorHandler(commentsController.DeleteIssueComment))
+		r.Get("/{id}/time-entries", httperr.WithCustomErrorHandler(timeEntriesController.GetIssueTimeEntries))
+		r.Post("/{id}/time-entries", httperr.WithCustomErrorHandler(timeEntriesController.CreateTimeEntry))
+		r.Delete("/{id}/time-entries/{entry_id}", httperr.WithCustomErrorHandler(timeEntriesController.DeleteTimeEntry))
+		r.Post("/{id}/timer/start", httperr.WithCustomErrorHandler(timeEntriesController.StartTimer))
+		r.Post("/{id}/timer/stop", httperr.WithCustomErrorHandler(timeEntriesController.StopTimer))
 	})
 
 	return r
@@ -17,14 +17,20 @@ func ProjectStatusColumnsRoutes(controller *api.ProjectStatusColumnsController,
 	// POST /project-columns - check access to project_id from request body
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckProjectAccessByBody()))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckProjectWritableByBody()))
 		r.Post("/", httperr.WithCustomErrorHandler(controller.CreateProjectStatusColumn))
 	})
 
-	// Routes that require project status column-level authorization
+	// Routes that require project status column-level authorization; columns of archived projects are read-only
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckColumnAccessByURLParam("id")))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckColumnWritableByURLParam("id")))
+		r.Get("/{id}", httperr.WithCustomErrorHandler(controller.GetProjectStatusColumnByID))
 		r.Put("/{id}", httperr.WithCustomErrorHandler(controller.UpdateProjectStatusColumn))
 		r.Delete("/{id}", httperr.WithCustomErrorHandler(controller.DeleteProjectStatusColumn))
+		r.Post("/{id}/move", httperr.WithCustomErrorHandler(controller.MoveProjectStatusColumn))
+		r.Get("/{id}/transitions", httperr.WithCustomErrorHandler(controller.GetColumnTransitions))
+		r.Put("/{id}/transitions", httperr.WithCustomErrorHandler(controller.SetColumnTransitions))
 	})
 
 	// This route uses project_id parameter, so needs project-level authorization
@@ -8,7 +8,7 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func ProjectsRoutes(controller *api.ProjectsController, authMiddlewares chi.Middlewares, authzMiddleware *auth.AuthorizationMiddleware) chi.Router {
+func ProjectsRoutes(controller *api.ProjectsController, issueDraftsController *api.IssueDraftsController, reportsController *api.ProjectReportsController, customFieldsController *api.CustomFieldsController, timeEntriesController *api.TimeEntriesController, sprintsController *api.SprintsController, milestonesController *api.MilestonesController, authMiddlewares chi.Middlewares, authzMiddleware *auth.AuthorizationMiddleware) chi.Router {
 	r := chi.NewRouter()
 
 	// Apply authentication middleware to all routes
@@ -23,13 +23,43 @@ func ProjectsRoutes(controller *api.ProjectsController, authMiddlewares chi.Midd
 		r.Post("/", httperr.WithCustomErrorHandler(controller.CreateProject))
 	})
 
-	// Routes that require project-level authorization
+	// Archiving only requires project-level authorization so archived projects can be restored
 	r.Group(func(r chi.Router) {
 		r.Use(authzMiddleware.RequireAccess(auth.CheckProjectAccessByURLParam("id")))
+		r.Post("/{id}/archive", httperr.WithCustomErrorHandler(controller.ArchiveProject))
+		r.Post("/{id}/unarchive", httperr.WithCustomErrorHandler(controller.UnarchiveProject))
+	})
+
+	// Routes that require project-level authorization; archived projects are read-only
+	r.Group(func(r chi.Router) {
+		r.Use(authzMiddleware.RequireAccess(auth.CheckProjectAccessByURLParam("id")))
+		r.Use(authzMiddleware.RequireWritable(auth.CheckProjectWritableByURLParam("id")))
 		r.Get("/{id}", httperr.WithCustomErrorHandler(controller.GetProjectByID))
 		r.Get("/{id}/details", httperr.WithCustomErrorHandler(controller.GetProjectDetailsByID))
 		r.Put("/{id}", httperr.WithCustomErrorHandler(controller.UpdateProject))
 		r.Delete("/{id}", httperr.WithCustomErrorHandler(controller.DeleteProject))
+		r.Post("/{id}/issue-drafts", httperr.WithCustomErrorHandler(issueDraftsController.GenerateIssueDrafts))
+		r.Post("/{id}/issue-drafts/commit", httperr.WithCustomErrorHandler(issueDraftsController.CommitIssueDrafts))
+		r.Post("/{id}/report", httperr.WithCustomErrorHandler(reportsController.GenerateProjectReport))
+		r.Get("/{id}/reports", httperr.WithCustomErrorHandler(reportsController.GetProjectReports))
+		r.Get("/{id}/reports/{report_id}", httperr.WithCustomErrorHandler(reportsController.GetProjectReportByID))
+		r.Get("/{id}/custom-fields", httperr.WithCustomErrorHandler(customFieldsController.GetCustomFields))
+		r.Post("/{id}/custom-fields", httperr.WithCustomErrorHandler(customFieldsController.CreateCustomField))
+		r.Put("/{id}/custom-fields/{field_id}", httperr.WithCustomErrorHandler(customFieldsController.UpdateCustomField))
+		r.Delete("/{id}/custom-fields/{field_id}", httperr.WithCustomErrorHandler(customFieldsController.DeleteCustomField))
+		r.Get("/{id}/time-report", httperr.WithCustomErrorHandler(timeEntriesController.GetProjectTimeReport))
+		r.Get("/{id}/sprints", httperr.WithCustomErrorHandler(sprintsController.GetSprints))
+		r.Post("/{id}/sprints", httperr.WithCustomErrorHandler(sprintsController.CreateSprint))
+		r.Put("/{id}/sprints/{sprint_id}", httperr.WithCustomErrorHandler(sprintsController.UpdateSprint))
+		r.Delete("/{id}/sprints/{sprint_id}", httperr.WithCustomErrorHandler(sprintsController.DeleteSprint))
+		r.Post("/{id}/sprints/{sprint_id}/start", httperr.WithCustomErrorHandler(sprintsController.StartSprint))
+		r.Post("/{id}/sprints/{sprint_id}/complete", httperr.WithCustomErrorHandler(sprintsController.CompleteSprint))
+		r.Get("/{id}/milestones", httperr.WithCustomErrorHandler(milestonesController.GetMilestones))
+		r.Post("/{id}/milestones", httperr.WithCustomErrorHandler(milestonesController.CreateMilestone))
+		r.Get("/{id}/milestones/{milestone_id}", httperr.WithCustomErrorHandler(milestonesController.GetMilestone))
+		r.Put("/{id}/milestones/{milestone_id}", httperr.WithCustomErrorHandler(milestonesController.UpdateMilestone))
+		r.Delete("/{id}/milestones/{milestone_id}", httperr.WithCustomErrorHandler(milestonesController.DeleteMilestone))
+		r.Get("/{id}/milestones/{milestone_id}/release-notes", httperr.WithCustomErrorHandler(milestonesController.GetReleaseNotes))
 	})
 
 	return r
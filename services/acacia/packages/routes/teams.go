@@ -11,6 +11,8 @@ import (
 func TeamsRoutes(
 	controller *api.TeamsController,
 	teamLLMAPIKeysController *api.TeamLLMAPIKeysController,
+	labelsController *api.LabelsController,
+	trashController *api.TrashController,
 	authMiddlewares chi.Middlewares,
 	authzMiddleware *auth.AuthorizationMiddleware,
 ) chi.Router {
@@ -30,5 +32,24 @@ func TeamsRoutes(
 		r.Delete("/{id}/llm-api-keys/{keyId}", httperr.WithCustomErrorHandler(teamLLMAPIKeysController.DeleteAPIKey))
 	})
 
+	// Team labels routes - require team membership
+	r.Group(func(r chi.Router) {
+		r.Use(authzMiddleware.RequireAccess(auth.CheckTeamMembershipByURLParam("id")))
+		r.Post("/{id}/labels", httperr.WithCustomErrorHandler(labelsController.CreateLabel))
+		r.Get("/{id}/labels", httperr.WithCustomErrorHandler(labelsController.GetLabels))
+		r.Put("/{id}/labels/{label_id}", httperr.WithCustomErrorHandler(labelsController.UpdateLabel))
+		r.Delete("/{id}/labels/{label_id}", httperr.WithCustomErrorHandler(labelsController.DeleteLabel))
+	})
+
+	// Team trash routes - require team membership
+	r.Group(func(r chi.Router) {
+		r.Use(authzMiddleware.RequireAccess(auth.CheckTeamMembershipByURLParam("id")))
+		r.Get("/{id}/trash", httperr.WithCustomErrorHandler(trashController.GetTrash))
+		r.Post("/{id}/trash/projects/{item_id}/restore", httperr.WithCustomErrorHandler(trashController.RestoreProject))
+		r.Post("/{id}/trash/columns/{item_id}/restore", httperr.WithCustomErrorHandler(trashController.RestoreColumn))
+		r.Post("/{id}/trash/issues/{item_id}/restore", httperr.WithCustomErrorHandler(trashController.RestoreIssue))
+		r.Get("/{id}/trash/issues/{item_id}/activity", httperr.WithCustomErrorHandler(trashController.GetIssueActivity))
+	})
+
 	return r
 }
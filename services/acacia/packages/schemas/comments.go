@@ -0,0 +1,61 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
+type CreateCommentInput struct {
+	Body           string  `json:"body" validate:"required,max=10000"`
+	BodySerialized *string `json:"body_serialized"`
+	// ParentID makes the comment a reply to a top-level comment on the same issue
+	ParentID null.Int `json:"parent_id"`
+}
+
+type UpdateCommentInput struct {
+	Body string `json:"body" validate:"required,max=10000"`
+	// BodySerialized replaces the rich-text body; omit it to drop the previous one
+	BodySerialized *string `json:"body_serialized"`
+}
+
+// IssueComment is a comment with its author, mentioned users and, for top-level comments, its replies.
+// BodySerialized is only loaded by the REST API.
+type IssueComment struct {
+	ID             int64          `json:"id"`
+	IssueID        int64          `json:"issue_id"`
+	ParentID       null.Int       `json:"parent_id"`
+	Author         *IssueUser     `json:"author"`
+	Body           string         `json:"body"`
+	BodySerialized *string        `json:"body_serialized,omitempty"`
+	Mentions       []IssueUser    `json:"mentions"`
+	Replies        []IssueComment `json:"replies,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	// HasSerializedBody tells the API whether BodySerialized is stored for the comment
+	HasSerializedBody bool `json:"-"`
+}
+
+// HandleCommentValidationErrors converts validator errors to user-friendly messages
+func HandleCommentValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Body":
+			if e.Tag() == "required" {
+				return errors.New("Comment body is required")
+			}
+			return errors.New("Comment body must be at most 10000 characters")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
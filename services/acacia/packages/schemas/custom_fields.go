@@ -0,0 +1,71 @@
+package schemas
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Types of project custom fields
+const (
+	CustomFieldText        = "text"
+	CustomFieldNumber      = "number"
+	CustomFieldSelect      = "select"
+	CustomFieldMultiSelect = "multi_select"
+	CustomFieldDate        = "date"
+	CustomFieldUser        = "user"
+)
+
+// CreateCustomFieldInput defines a field on a project's issues.
+// Options lists the choices of select and multi_select fields and must be empty for the other types.
+type CreateCustomFieldInput struct {
+	Name      string   `json:"name" validate:"required,min=1,max=50"`
+	FieldType string   `json:"field_type" validate:"required,oneof=text number select multi_select date user"`
+	Options   []string `json:"options" validate:"max=100,unique,dive,required,max=100"`
+}
+
+// UpdateCustomFieldInput renames a field and replaces its options. The type cannot change.
+// Values using a removed option lose it.
+type UpdateCustomFieldInput struct {
+	Name    string   `json:"name" validate:"required,min=1,max=50"`
+	Options []string `json:"options" validate:"max=100,unique,dive,required,max=100"`
+}
+
+// CustomFieldValue is an issue's value for one custom field. Value is a string for text, select and
+// date (YYYY-MM-DD) fields, a number for number fields, a list of option names for multi_select fields
+// and an IssueUser for user fields.
+type CustomFieldValue struct {
+	FieldID   int64       `json:"field_id"`
+	Name      string      `json:"name"`
+	FieldType string      `json:"field_type"`
+	Value     interface{} `json:"value"`
+}
+
+// HandleCustomFieldValidationErrors converts validator errors to user-friendly messages
+func HandleCustomFieldValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Name":
+			if e.Tag() == "required" {
+				return errors.New("Custom field name is required")
+			}
+			return errors.New("Custom field name must be between 1 and 50 characters")
+		case "FieldType":
+			return errors.New("Custom field type must be one of: text, number, select, multi_select, date, user")
+		case "Options":
+			if e.Tag() == "unique" {
+				return errors.New("Custom field options must be unique")
+			}
+			return errors.New("A custom field can have at most 100 options")
+		default:
+			return errors.New("Custom field options must be between 1 and 100 characters")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,46 @@
+package schemas
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+// Date is an optional calendar date in a request body, written YYYY-MM-DD like the date filters and
+// custom date fields. A full RFC3339 timestamp is still accepted and keeps the date as written, so its
+// offset cannot move it to the day before or after once stored as a date.
+type Date null.Time
+
+// DateFrom returns a valid Date on the day of t
+func DateFrom(t time.Time) Date {
+	return Date(null.TimeFrom(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)))
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	parsed, err := time.Parse(time.DateOnly, text)
+	if err != nil {
+		if parsed, err = time.Parse(time.RFC3339, text); err != nil {
+			return err
+		}
+	}
+	*d = DateFrom(parsed)
+	return nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.Time.Format(time.DateOnly))
+}
@@ -0,0 +1,31 @@
+package schemas
+
+import (
+	"time"
+
+	"github.com/guregu/null"
+)
+
+const (
+	IssueActivityCreated    = "created"
+	IssueActivityUpdated    = "updated"
+	IssueActivityMoved      = "moved"
+	IssueActivityDeleted    = "deleted"
+	IssueActivityRestored   = "restored"
+	IssueActivityArchived   = "archived"
+	IssueActivityUnarchived = "unarchived"
+)
+
+// IssueActivity is one entry of an issue's history. Field, OldValue and NewValue are set for updates and moves;
+// moves record column IDs, or project IDs when Field is project_id. Updates of the assignee and label fields record
+// the ID of the user or label added as NewValue, or removed as OldValue. Actor is nil when the user who made the change no longer exists.
+type IssueActivity struct {
+	ID        int64       `json:"id"`
+	IssueID   int64       `json:"issue_id"`
+	Actor     *IssueUser  `json:"actor"`
+	Action    string      `json:"action"`
+	Field     null.String `json:"field"`
+	OldValue  null.String `json:"old_value"`
+	NewValue  null.String `json:"new_value"`
+	CreatedAt time.Time   `json:"created_at"`
+}
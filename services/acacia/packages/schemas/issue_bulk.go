@@ -0,0 +1,85 @@
+package schemas
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Operations applied by a bulk issue request
+const (
+	BulkIssueMove        = "move"
+	BulkIssueAssign      = "assign"
+	BulkIssueLabel       = "label"
+	BulkIssueSetPriority = "set_priority"
+	BulkIssueArchive     = "archive"
+	BulkIssueDelete      = "delete"
+)
+
+// Outcomes of one issue in a bulk request. Changes reported as rolled back succeeded but were undone
+// because another issue failed in all-or-nothing mode.
+const (
+	BulkIssueSucceeded  = "succeeded"
+	BulkIssueFailed     = "failed"
+	BulkIssueRolledBack = "rolled_back"
+)
+
+// BulkIssueInput applies one operation to every listed issue. The operation's argument must be set:
+// column_id for move, user_id for assign, label_id for label and priority for set_priority.
+// Moved issues go to the bottom of the target column. With AllOrNothing, one failure undoes every change.
+type BulkIssueInput struct {
+	IssueIDs     []int64 `json:"issue_ids" validate:"required,min=1,max=100,dive,min=1"`
+	Operation    string  `json:"operation" validate:"required,oneof=move assign label set_priority archive delete"`
+	ColumnID     int64   `json:"column_id" validate:"required_if=Operation move"`
+	UserID       int64   `json:"user_id" validate:"required_if=Operation assign"`
+	LabelID      int64   `json:"label_id" validate:"required_if=Operation label"`
+	Priority     string  `json:"priority" validate:"required_if=Operation set_priority,omitempty,oneof=none low medium high urgent"`
+	AllOrNothing bool    `json:"all_or_nothing"`
+}
+
+// BulkIssueResult reports what happened to one issue of a bulk request
+type BulkIssueResult struct {
+	IssueID    int64            `json:"issue_id"`
+	Status     string           `json:"status"`
+	Error      string           `json:"error,omitempty"`
+	WIPWarning *WIPLimitWarning `json:"wip_warning,omitempty"`
+}
+
+type BulkIssueResponse struct {
+	Results    []BulkIssueResult `json:"results"`
+	Succeeded  int               `json:"succeeded"`
+	Failed     int               `json:"failed"`
+	RolledBack bool              `json:"rolled_back"`
+}
+
+// HandleBulkIssueValidationErrors converts validator errors to user-friendly messages
+func HandleBulkIssueValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		// Errors on single IDs are reported as IssueIDs[i]
+		field, _, _ := strings.Cut(e.Field(), "[")
+		switch field {
+		case "IssueIDs":
+			return errors.New("issue_ids must list between 1 and 100 issue IDs")
+		case "Operation":
+			return errors.New("Operation must be one of: move, assign, label, set_priority, archive, delete")
+		case "ColumnID":
+			return errors.New("column_id is required to move issues")
+		case "UserID":
+			return errors.New("user_id is required to assign issues")
+		case "LabelID":
+			return errors.New("label_id is required to label issues")
+		case "Priority":
+			return errors.New("Priority must be one of: none, low, medium, high, urgent")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
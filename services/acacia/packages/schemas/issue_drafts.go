@@ -0,0 +1,73 @@
+package schemas
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type GenerateIssueDraftsInput struct {
+	Text     string `json:"text" validate:"required,min=1,max=20000"`
+	Provider string `json:"provider" validate:"required"`
+	Model    string `json:"model" validate:"required"`
+}
+
+// IssueDraft is an issue proposed by the model that has not been created yet
+type IssueDraft struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ColumnID    int64  `json:"column_id"`
+	ColumnName  string `json:"column_name"`
+}
+
+type IssueDraftsResponse struct {
+	Drafts []IssueDraft `json:"drafts"`
+}
+
+type IssueDraftInput struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description"`
+	ColumnID    int64  `json:"column_id" validate:"required"`
+}
+
+type CommitIssueDraftsInput struct {
+	Drafts []IssueDraftInput `json:"drafts" validate:"required,min=1,max=100,dive"`
+}
+
+// HandleIssueDraftValidationErrors converts validator errors to user-friendly messages
+func HandleIssueDraftValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Text":
+			if e.Tag() == "required" {
+				return errors.New("Text is required")
+			}
+			return errors.New("Text must be between 1 and 20000 characters")
+		case "Provider":
+			return errors.New("Provider is required")
+		case "Model":
+			return errors.New("Model is required")
+		case "Drafts":
+			if e.Tag() == "max" {
+				return errors.New("At most 100 drafts can be committed at once")
+			}
+			return errors.New("At least one draft is required")
+		case "Name":
+			if e.Tag() == "required" {
+				return errors.New("Draft name is required")
+			}
+			return errors.New("Draft name must be between 1 and 255 characters")
+		case "ColumnID":
+			return errors.New("Draft column ID is required")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,38 @@
+package schemas
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Issues are named by their project's key prefix and their number in the project, e.g. ACA-142.
+// A key prefix is 2 to 10 upper-case letters and digits starting with a letter.
+var (
+	keyPrefixPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+	issueKeyPattern  = regexp.MustCompile(`^([A-Z][A-Z0-9]{1,9})-([1-9][0-9]*)$`)
+)
+
+// IsValidKeyPrefix reports whether prefix can be used as a project's key prefix
+func IsValidKeyPrefix(prefix string) bool {
+	return keyPrefixPattern.MatchString(prefix)
+}
+
+// IssueKey formats an issue's key from its project's key prefix and its number
+func IssueKey(keyPrefix string, number int32) string {
+	return fmt.Sprintf("%s-%d", keyPrefix, number)
+}
+
+// ParseIssueKey splits a key like ACA-142 into its prefix and number. Keys are case-insensitive.
+func ParseIssueKey(key string) (string, int32, bool) {
+	match := issueKeyPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(key)))
+	if match == nil {
+		return "", 0, false
+	}
+	number, err := strconv.ParseInt(match[2], 10, 32)
+	if err != nil {
+		return "", 0, false
+	}
+	return match[1], int32(number), true
+}
@@ -0,0 +1,38 @@
+package schemas
+
+import "time"
+
+// Link types as seen from the issue the link is listed on. blocked_by, duplicated_by and cloned_by
+// are the reverse of blocks, duplicates and clones; relates has no direction.
+const (
+	IssueLinkBlocks       = "blocks"
+	IssueLinkBlockedBy    = "blocked_by"
+	IssueLinkRelates      = "relates"
+	IssueLinkDuplicates   = "duplicates"
+	IssueLinkDuplicatedBy = "duplicated_by"
+	IssueLinkClones       = "clones"
+	IssueLinkClonedBy     = "cloned_by"
+)
+
+// CreateIssueLinkInput links the issue in the URL to another issue of the same team
+type CreateIssueLinkInput struct {
+	LinkedIssueID int64  `json:"linked_issue_id" validate:"required,min=1"`
+	LinkType      string `json:"link_type" validate:"required,oneof=blocks blocked_by relates duplicates duplicated_by clones cloned_by"`
+}
+
+// IssueLinkDetails is a link as seen from one of its issues: "A blocks B" is listed as blocks on A and blocked_by on B
+type IssueLinkDetails struct {
+	ID        int64       `json:"id"`
+	LinkType  string      `json:"link_type"`
+	Issue     LinkedIssue `json:"issue"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// LinkedIssue is the issue on the other end of a link; Done is set when it is in a done column
+type LinkedIssue struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	ColumnID  int64  `json:"column_id"`
+	ProjectID int32  `json:"project_id"`
+	Done      bool   `json:"done"`
+}
@@ -1,10 +1,41 @@
 package schemas
 
+import (
+	"acacia/packages/db"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
 type CreateIssueInput struct {
-	Name                 string  `json:"name" validate:"required"`
-	Description          *string `json:"description" validate:"required"`
+	Name                  string  `json:"name" validate:"required"`
+	Description           *string `json:"description" validate:"required"`
 	DescriptionSerialized *string `json:"description_serialized"`
-	ColumnId             int64   `json:"column_id" validate:"required"`
+	ColumnId              int64   `json:"column_id" validate:"required"`
+	// CheckDuplicates rejects the request with 409 and a candidate list when similar issues exist
+	CheckDuplicates bool `json:"check_duplicates"`
+	// Force skips the duplicate check
+	Force bool `json:"force"`
+	// DuplicateOf links the new issue as a duplicate of an existing issue in the same project
+	DuplicateOf null.Int `json:"duplicate_of"`
+	// AssigneeIDs must all be members of the project's team
+	AssigneeIDs []int64 `json:"assignee_ids"`
+	// Priority defaults to "none"
+	Priority string `json:"priority"`
+	DueDate  Date   `json:"due_date"`
+	// LabelIDs must all belong to the project's team
+	LabelIDs []int64 `json:"label_ids"`
+	// ParentID makes the issue a sub-issue of an issue in the same project
+	ParentID null.Int `json:"parent_id"`
+	// EstimatePoints and EstimateMinutes are optional and cannot be negative
+	EstimatePoints  null.Int `json:"estimate_points"`
+	EstimateMinutes null.Int `json:"estimate_minutes"`
+	// CustomFields sets values of the project's custom fields, keyed by field ID
+	CustomFields map[int64]json.RawMessage `json:"custom_fields"`
 }
 
 type UpdateIssueInput struct {
@@ -13,6 +44,158 @@ type UpdateIssueInput struct {
 	Description           string  `json:"description"`
 	DescriptionSerialized *string `json:"description_serialized"`
 	ColumnId              int64   `json:"column_id"`
+	// Priority and DueDate are left unchanged when empty
+	Priority string `json:"priority"`
+	DueDate  Date   `json:"due_date"`
+	// ClearDueDate removes the due date
+	ClearDueDate bool `json:"clear_due_date"`
+	// EstimatePoints and EstimateMinutes are left unchanged when null
+	EstimatePoints  null.Int `json:"estimate_points"`
+	EstimateMinutes null.Int `json:"estimate_minutes"`
+	// ClearEstimate removes both estimates
+	ClearEstimate bool `json:"clear_estimate"`
+	// CustomFields sets values of the project's custom fields, keyed by field ID; null clears a value.
+	// Fields missing from the map are left unchanged.
+	CustomFields map[int64]json.RawMessage `json:"custom_fields"`
+}
+
+const (
+	IssuePriorityNone   = "none"
+	IssuePriorityLow    = "low"
+	IssuePriorityMedium = "medium"
+	IssuePriorityHigh   = "high"
+	IssuePriorityUrgent = "urgent"
+)
+
+// IssuePriorities lists the accepted priorities from lowest to highest
+var IssuePriorities = []string{
+	IssuePriorityNone,
+	IssuePriorityLow,
+	IssuePriorityMedium,
+	IssuePriorityHigh,
+	IssuePriorityUrgent,
+}
+
+// IsValidIssuePriority reports whether p is one of IssuePriorities
+func IsValidIssuePriority(p string) bool {
+	return slices.Contains(IssuePriorities, p)
+}
+
+// IssueFilter narrows issue listings by triage fields.
+// DueAfter is inclusive and DueBefore is exclusive; both are compared by date only.
+// Archived issues are left out unless IncludeArchived is set.
+type IssueFilter struct {
+	Priority        string    `json:"priority" validate:"omitempty,oneof=none low medium high urgent"`
+	LabelID         null.Int  `json:"label_id"`
+	DueAfter        null.Time `json:"due_after"`
+	DueBefore       null.Time `json:"due_before"`
+	IncludeArchived bool      `json:"include_archived"`
+}
+
+// ProjectIssueFilter narrows a project's issues by triage fields and, when CustomFieldID is set,
+// by the value of one of the project's custom fields
+type ProjectIssueFilter struct {
+	IssueFilter
+	CustomFieldID    null.Int `json:"custom_field_id"`
+	CustomFieldValue string   `json:"custom_field_value"`
+}
+
+// IssueWithLabels is an issue together with its key, its labels, its custom field values and,
+// when it has sub-issues, their progress.
+// Blocked is set while a blocking issue is not in a done column.
+type IssueWithLabels struct {
+	db.Issue
+	Key          string             `json:"key"`
+	Labels       []db.Label         `json:"labels"`
+	CustomFields []CustomFieldValue `json:"custom_fields"`
+	Progress     *IssueProgress     `json:"progress,omitempty"`
+	Blocked      bool               `json:"blocked"`
+}
+
+// IssueProgress counts an issue's sub-issues and how many of them are in a done column
+type IssueProgress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
+
+// SetIssueParentInput moves an issue under a parent in the same project; a null parent_id detaches it
+type SetIssueParentInput struct {
+	ParentID null.Int `json:"parent_id"`
+}
+
+// IssueChildren lists an issue's direct sub-issues oldest first
+type IssueChildren struct {
+	Children []db.Issue    `json:"children"`
+	Progress IssueProgress `json:"progress"`
+}
+
+// IssueWithWIPWarning is the response to creating, updating or moving an issue.
+// WIPWarning is only set when the target column went over its soft WIP limit.
+// CustomFields is set by the create and update endpoints.
+type IssueWithWIPWarning struct {
+	db.Issue
+	CustomFields []CustomFieldValue `json:"custom_fields,omitempty"`
+	WIPWarning   *WIPLimitWarning   `json:"wip_warning,omitempty"`
+}
+
+// IssueUser is the public profile of a reporter or assignee
+type IssueUser struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// IssueDetails is an issue together with its key, reporter, assignees, labels, custom field values,
+// sub-issues and links.
+// Blocked is set while a blocking issue is not in a done column.
+type IssueDetails struct {
+	db.Issue
+	Key          string             `json:"key"`
+	Reporter     *IssueUser         `json:"reporter"`
+	Assignees    []IssueUser        `json:"assignees"`
+	Labels       []db.Label         `json:"labels"`
+	CustomFields []CustomFieldValue `json:"custom_fields"`
+	Children     []db.Issue         `json:"children"`
+	Progress     *IssueProgress     `json:"progress,omitempty"`
+	Links        []IssueLinkDetails `json:"links"`
+	Blocked      bool               `json:"blocked"`
+}
+
+type AssignIssueInput struct {
+	UserID int64 `json:"user_id" validate:"required,min=1"`
+}
+
+type DuplicateCandidate struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Description null.String `json:"description"`
+	ColumnID    int64       `json:"column_id"`
+	// Score is the embedding similarity when available and the full-text score otherwise
+	Score          float64    `json:"score"`
+	TextScore      float64    `json:"text_score"`
+	EmbeddingScore null.Float `json:"embedding_score"`
+}
+
+// DuplicateIssuesResponse is returned with 409 Conflict when a duplicate check finds candidates
+type DuplicateIssuesResponse struct {
+	Message    string               `json:"message"`
+	Candidates []DuplicateCandidate `json:"candidates"`
+}
+
+// MoveIssueInput positions an issue within a column.
+// BeforeID is the issue that ends up directly above the moved issue and AfterID the one directly below.
+// With only one neighbour the issue is placed right next to it; with neither it goes to the bottom of the column.
+type MoveIssueInput struct {
+	ColumnID int64    `json:"column_id" validate:"required,min=1"`
+	BeforeID null.Int `json:"before_id"`
+	AfterID  null.Int `json:"after_id"`
+}
+
+// MoveIssueToProjectInput moves an issue into another project. Without ColumnID the issue goes to the target
+// project's column with the same name as its current one, or else to the project's first column.
+type MoveIssueToProjectInput struct {
+	ProjectID int64    `json:"project_id" validate:"required,min=1"`
+	ColumnID  null.Int `json:"column_id"`
 }
 
 type ReassignIssuesInput struct {
@@ -24,3 +207,79 @@ type ReassignIssueColumn struct {
 	IssueId        int64 `json:"issue_id" validate:"required"`
 	TargetColumnId int64 `json:"target_column" validate:"required"`
 }
+
+const (
+	IssueSearchSortRelevance = "relevance"
+	IssueSearchSortCreatedAt = "created_at"
+	IssueSearchSortUpdatedAt = "updated_at"
+
+	IssueSearchOrderAsc  = "asc"
+	IssueSearchOrderDesc = "desc"
+)
+
+// SearchIssuesInput holds the filters accepted by GET /issues/search and the search_issues tool
+type SearchIssuesInput struct {
+	Query         string    `json:"q" validate:"max=500"`
+	ProjectID     null.Int  `json:"project_id"`
+	ColumnID      null.Int  `json:"column_id"`
+	CreatedAfter  null.Time `json:"created_after"`
+	CreatedBefore null.Time `json:"created_before"`
+	UpdatedAfter  null.Time `json:"updated_after"`
+	UpdatedBefore null.Time `json:"updated_before"`
+	AssignedToMe  bool      `json:"assigned_to_me"`
+	IssueFilter
+	Sort   string `json:"sort" validate:"omitempty,oneof=relevance created_at updated_at"`
+	Order  string `json:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"min=0,max=100"`
+}
+
+// IssueSearchResult is one search hit. NameHighlight and Snippet are HTML: the issue's text is escaped
+// and the matched words are wrapped in <mark>.
+type IssueSearchResult struct {
+	ID            int64       `json:"id"`
+	Name          string      `json:"name"`
+	Description   null.String `json:"description"`
+	ColumnID      int64       `json:"column_id"`
+	ProjectID     int32       `json:"project_id"`
+	Priority      string      `json:"priority"`
+	DueDate       null.Time   `json:"due_date"`
+	Labels        []db.Label  `json:"labels"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	Rank          float64     `json:"rank"`
+	NameHighlight string      `json:"name_highlight"`
+	Snippet       string      `json:"snippet"`
+}
+
+type SearchIssuesResponse struct {
+	Issues     []IssueSearchResult `json:"issues"`
+	NextCursor *string             `json:"next_cursor"`
+}
+
+// HandleIssueSearchValidationErrors converts validator errors to user-friendly messages
+func HandleIssueSearchValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Query":
+			return errors.New("Search query must be at most 500 characters")
+		case "Sort":
+			return errors.New("Sort must be one of: relevance, created_at, updated_at")
+		case "Order":
+			return errors.New("Order must be one of: asc, desc")
+		case "Priority":
+			return errors.New("Priority must be one of: none, low, medium, high, urgent")
+		case "Limit":
+			return errors.New("Limit must be between 1 and 100")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,50 @@
+package schemas
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type CreateLabelInput struct {
+	Name  string `json:"name" validate:"required,min=1,max=100"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+type UpdateLabelInput struct {
+	Name  string `json:"name" validate:"required,min=1,max=100"`
+	Color string `json:"color" validate:"required,hexcolor"`
+}
+
+type AddIssueLabelInput struct {
+	LabelID int64 `json:"label_id" validate:"required,min=1"`
+}
+
+// HandleLabelValidationErrors converts validator errors to user-friendly messages
+func HandleLabelValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Name":
+			if e.Tag() == "required" {
+				return errors.New("Label name is required")
+			}
+			return errors.New("Label name must be between 1 and 100 characters")
+		case "Color":
+			if e.Tag() == "required" {
+				return errors.New("Label color is required")
+			}
+			return errors.New("Label color must be a hex color such as #d73a4a")
+		case "LabelID":
+			return errors.New("Label ID is required")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,76 @@
+package schemas
+
+import (
+	"errors"
+
+	"acacia/packages/db"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
+// MilestoneInput creates a milestone or replaces its name, description and target date
+type MilestoneInput struct {
+	Name        string    `json:"name" validate:"required,min=1,max=100"`
+	Description string    `json:"description" validate:"max=5000"`
+	TargetDate  null.Time `json:"target_date"`
+}
+
+// SetIssueMilestoneInput assigns an issue to a milestone, or removes it from its milestone when MilestoneID is null
+type SetIssueMilestoneInput struct {
+	MilestoneID null.Int `json:"milestone_id"`
+}
+
+// MilestoneSummary is a milestone with the number of its issues and how many are in a done column.
+// PercentComplete is rounded down and 0 for a milestone without issues.
+type MilestoneSummary struct {
+	db.Milestone
+	IssueCount      int64 `json:"issue_count"`
+	DoneIssueCount  int64 `json:"done_issue_count"`
+	PercentComplete int64 `json:"percent_complete"`
+}
+
+// ReleaseNotes list a milestone's issues in done columns grouped by label.
+// An issue with several labels appears in each of their groups.
+type ReleaseNotes struct {
+	Milestone MilestoneSummary    `json:"milestone"`
+	Groups    []ReleaseNotesGroup `json:"groups"`
+}
+
+// ReleaseNotesGroup holds the issues with one label ordered by number. The group of issues without
+// labels comes last and has no Label.
+type ReleaseNotesGroup struct {
+	Label  *db.Label          `json:"label"`
+	Issues []ReleaseNoteIssue `json:"issues"`
+}
+
+type ReleaseNoteIssue struct {
+	ID       int64  `json:"id"`
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Priority string `json:"priority"`
+}
+
+// HandleMilestoneValidationErrors converts validator errors to user-friendly messages
+func HandleMilestoneValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Name":
+			if e.Tag() == "required" {
+				return errors.New("Milestone name is required")
+			}
+			return errors.New("Milestone name must be between 1 and 100 characters")
+		case "Description":
+			return errors.New("Milestone description must be at most 5000 characters")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,37 @@
+package schemas
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
+type GenerateProjectReportInput struct {
+	Provider string `json:"provider" validate:"required"`
+	Model    string `json:"model" validate:"required"`
+	// Reporting window; defaults to the seven days before now
+	Since null.Time `json:"since"`
+	Until null.Time `json:"until"`
+}
+
+// HandleProjectReportValidationErrors converts validator errors to user-friendly messages
+func HandleProjectReportValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Provider":
+			return errors.New("Provider is required")
+		case "Model":
+			return errors.New("Model is required")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
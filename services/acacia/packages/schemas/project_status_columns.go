@@ -1,11 +1,50 @@
 package schemas
 
+// Fields a column can require before an issue enters it
+const (
+	ColumnRequiredFieldAssignee    = "assignee"
+	ColumnRequiredFieldDescription = "description"
+	ColumnRequiredFieldDueDate     = "due_date"
+	ColumnRequiredFieldLabels      = "labels"
+	ColumnRequiredFieldPriority    = "priority"
+)
+
 type CreateProjectStatusColumnInput struct {
 	ProjectID int32  `json:"project_id" validate:"required"`
 	Name      string `json:"name" validate:"required,min=1,max=255"`
+	// WIPLimit caps the number of issues in the column; omit for no limit
+	WIPLimit *int32 `json:"wip_limit" validate:"omitempty,min=1"`
+	// RequiredFields must be set on an issue before it can enter the column
+	RequiredFields []string `json:"required_fields" validate:"dive,oneof=assignee description due_date labels priority"`
+	// IsDone marks issues in the column as finished when rolling up sub-issue progress
+	IsDone bool `json:"is_done"`
 }
 
 type UpdateProjectStatusColumnInput struct {
 	Name          string `json:"name" validate:"required,min=1,max=255"`
 	PositionIndex int16  `json:"position_index" validate:"min=0"`
+	// WIPLimit replaces the column's limit; omit or send null to remove it
+	WIPLimit *int32 `json:"wip_limit" validate:"omitempty,min=1"`
+	// RequiredFields replaces the column's required fields; omit to require none
+	RequiredFields []string `json:"required_fields" validate:"dive,oneof=assignee description due_date labels priority"`
+	// IsDone replaces the column's done flag; omit to clear it
+	IsDone bool `json:"is_done"`
+}
+
+type MoveProjectStatusColumnInput struct {
+	PositionIndex *int16 `json:"position_index" validate:"required,min=0"`
+}
+
+// SetColumnTransitionsInput replaces the columns issues may move to from a column.
+// An empty list removes the restriction so issues can move to any column.
+type SetColumnTransitionsInput struct {
+	ToColumnIDs []int64 `json:"to_column_ids" validate:"max=100,dive,min=1"`
+}
+
+// WIPLimitWarning reports that a change took a column over its WIP limit in a project using soft limits
+type WIPLimitWarning struct {
+	ColumnID   int64  `json:"column_id"`
+	WIPLimit   int64  `json:"wip_limit"`
+	IssueCount int64  `json:"issue_count"`
+	Message    string `json:"message"`
 }
@@ -2,17 +2,42 @@ package schemas
 
 import "acacia/packages/db"
 
+// WIP limit modes of a project. Soft limits only warn when a column goes over its limit,
+// hard limits reject the change.
+const (
+	WIPLimitModeSoft = "soft"
+	WIPLimitModeHard = "hard"
+)
+
 type CreateProjectInput struct {
 	Name   string `json:"name" validate:"required,min=1,max=255"`
 	TeamID int64  `json:"team_id" validate:"required,min=1"`
+	// KeyPrefix names the project's issues, e.g. ACA for ACA-142. It is derived from the name when omitted.
+	KeyPrefix string `json:"key_prefix"`
 }
 
 type UpdateProjectInput struct {
 	Name string `json:"name" validate:"required,min=1,max=255"`
+	// WIPLimitMode is left unchanged when omitted
+	WIPLimitMode string `json:"wip_limit_mode" validate:"omitempty,oneof=soft hard"`
+	// KeyPrefix cannot be changed once the project exists. It may be omitted or repeat the current prefix.
+	KeyPrefix string `json:"key_prefix"`
+}
+
+// ProjectColumnDetails is a column on the board together with its WIP usage and workflow rules.
+// IssueCount always counts every issue in the column, regardless of the details filter.
+// An empty AllowedTransitions means issues can move to any column.
+type ProjectColumnDetails struct {
+	db.ProjectStatusColumn
+	IssueCount         int64   `json:"issue_count"`
+	OverWIPLimit       bool    `json:"over_wip_limit"`
+	AllowedTransitions []int64 `json:"allowed_transitions"`
 }
 
 type GetProjectDetailsResponse struct {
 	db.Project
-	Columns []db.ProjectStatusColumn `json:"columns"`
-	Issues  []db.Issue               `json:"issues"`
+	Columns      []ProjectColumnDetails `json:"columns"`
+	CustomFields []db.CustomField       `json:"custom_fields"`
+	Sprints      []SprintSummary        `json:"sprints"`
+	Issues       []IssueWithLabels      `json:"issues"`
 }
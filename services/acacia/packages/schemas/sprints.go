@@ -0,0 +1,91 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+
+	"acacia/packages/db"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
+// Sprint statuses. A sprint is planned until it is started and completed when it ends.
+const (
+	SprintPlanned   = "planned"
+	SprintActive    = "active"
+	SprintCompleted = "completed"
+)
+
+// Where CompleteSprint moves the issues that are not in a done column
+const (
+	SprintMoveToNext    = "next"
+	SprintMoveToBacklog = "backlog"
+)
+
+// SprintInput creates a sprint or replaces its name, goal and dates.
+// The dates are compared by date only and EndDate cannot be before StartDate.
+type SprintInput struct {
+	Name      string    `json:"name" validate:"required,min=1,max=100"`
+	Goal      string    `json:"goal" validate:"max=2000"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+// CompleteSprintInput chooses where unfinished issues go when a sprint is completed:
+// the next planned sprint (the default) or the backlog. Without a planned sprint they go to the backlog.
+type CompleteSprintInput struct {
+	MoveTo string `json:"move_to" validate:"omitempty,oneof=next backlog"`
+}
+
+// SetIssueSprintInput assigns an issue to a sprint, or returns it to the backlog when SprintID is null
+type SetIssueSprintInput struct {
+	SprintID null.Int `json:"sprint_id"`
+}
+
+// SprintSummary is a sprint with the number of its issues and estimate points, in total and in done columns
+type SprintSummary struct {
+	db.Sprint
+	IssueCount     int64 `json:"issue_count"`
+	DoneIssueCount int64 `json:"done_issue_count"`
+	Points         int64 `json:"points"`
+	DonePoints     int64 `json:"done_points"`
+}
+
+// CompletedSprint is a completed sprint and where its unfinished issues went.
+// MovedToSprintID is null when they were returned to the backlog.
+type CompletedSprint struct {
+	Sprint          SprintSummary `json:"sprint"`
+	MovedIssueCount int           `json:"moved_issue_count"`
+	MovedToSprintID null.Int      `json:"moved_to_sprint_id"`
+}
+
+// HandleSprintValidationErrors converts validator errors to user-friendly messages
+func HandleSprintValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Name":
+			if e.Tag() == "required" {
+				return errors.New("Sprint name is required")
+			}
+			return errors.New("Sprint name must be between 1 and 100 characters")
+		case "Goal":
+			return errors.New("Sprint goal must be at most 2000 characters")
+		case "StartDate":
+			return errors.New("Sprint start date is required")
+		case "EndDate":
+			return errors.New("Sprint end date is required")
+		case "MoveTo":
+			return errors.New("move_to must be one of: next, backlog")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
@@ -0,0 +1,95 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
+)
+
+// CreateTimeEntryInput logs time spent on an issue by the current user.
+// StartedAt defaults to Minutes before the entry is logged.
+type CreateTimeEntryInput struct {
+	Minutes   int32     `json:"minutes" validate:"required,min=1,max=1440"`
+	StartedAt null.Time `json:"started_at"`
+	Note      string    `json:"note" validate:"max=500"`
+}
+
+// StartTimerInput starts a timer on an issue for the current user
+type StartTimerInput struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+// TimeEntry is time logged on an issue. A running timer has no EndedAt and no Minutes yet.
+type TimeEntry struct {
+	ID        int64       `json:"id"`
+	IssueID   int64       `json:"issue_id"`
+	User      IssueUser   `json:"user"`
+	StartedAt time.Time   `json:"started_at"`
+	EndedAt   null.Time   `json:"ended_at"`
+	Minutes   null.Int    `json:"minutes"`
+	Note      null.String `json:"note"`
+	Running   bool        `json:"running"`
+}
+
+// IssueTimeEntries lists an issue's time entries oldest first together with its estimates.
+// LoggedMinutes excludes running timers.
+type IssueTimeEntries struct {
+	Entries         []TimeEntry `json:"entries"`
+	LoggedMinutes   int64       `json:"logged_minutes"`
+	EstimatePoints  null.Int    `json:"estimate_points"`
+	EstimateMinutes null.Int    `json:"estimate_minutes"`
+}
+
+// ProjectTimeReport is the time logged on a project's issues grouped by user and week.
+// Weeks start on Monday; From is inclusive and To is exclusive.
+type ProjectTimeReport struct {
+	ProjectID int64            `json:"project_id"`
+	From      null.Time        `json:"from"`
+	To        null.Time        `json:"to"`
+	Users     []TimeReportUser `json:"users"`
+	Totals    ProjectTimeTotal `json:"totals"`
+}
+
+// TimeReportUser is the time a user logged on the project per week, oldest week first
+type TimeReportUser struct {
+	User          IssueUser        `json:"user"`
+	Weeks         []TimeReportWeek `json:"weeks"`
+	LoggedMinutes int64            `json:"logged_minutes"`
+}
+
+type TimeReportWeek struct {
+	WeekStart string `json:"week_start"`
+	Minutes   int64  `json:"minutes"`
+}
+
+// ProjectTimeTotal sums the estimates of all the project's issues and the time logged in the report's range
+type ProjectTimeTotal struct {
+	IssueCount          int64 `json:"issue_count"`
+	EstimatedIssueCount int64 `json:"estimated_issue_count"`
+	EstimatePoints      int64 `json:"estimate_points"`
+	EstimateMinutes     int64 `json:"estimate_minutes"`
+	LoggedMinutes       int64 `json:"logged_minutes"`
+}
+
+// HandleTimeEntryValidationErrors converts validator errors to user-friendly messages
+func HandleTimeEntryValidationErrors(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errors.New("Validation failed")
+	}
+
+	for _, e := range validationErrors {
+		switch e.Field() {
+		case "Minutes":
+			return errors.New("Minutes must be between 1 and 1440")
+		case "Note":
+			return errors.New("Note must be at most 500 characters")
+		default:
+			return errors.New("Validation failed")
+		}
+	}
+
+	return errors.New("Validation failed")
+}
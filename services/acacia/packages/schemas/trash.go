@@ -0,0 +1,37 @@
+package schemas
+
+import (
+	"time"
+
+	"acacia/packages/db"
+)
+
+// Trash lists a team's deleted projects, columns and issues, most recently deleted first.
+// Columns and issues of a deleted project are not listed separately; they come back with the project.
+type Trash struct {
+	RetentionDays int              `json:"retention_days"`
+	Projects      []TrashedProject `json:"projects"`
+	Columns       []TrashedColumn  `json:"columns"`
+	Issues        []TrashedIssue   `json:"issues"`
+}
+
+// TrashedProject is a deleted project with the time it will be purged together with its columns and issues
+type TrashedProject struct {
+	db.Project
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TrashedColumn is a deleted column with the time it will be purged
+type TrashedColumn struct {
+	db.ProjectStatusColumn
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+// TrashedIssue is a deleted issue with the time it will be purged
+type TrashedIssue struct {
+	db.Issue
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
@@ -1,7 +1,6 @@
 package services
 
 import (
-	"acacia/packages/crypto"
 	"acacia/packages/db"
 	"acacia/packages/llm"
 	"context"
@@ -19,26 +18,20 @@ var (
 )
 
 type ConversationService struct {
-	queries           *db.Queries
-	providerRegistry  *llm.ProviderRegistry
-	encryptionService *crypto.EncryptionService
-	toolRegistry      *llm.ToolRegistry
-	logger            *logrus.Logger
+	queries   *db.Queries
+	providers *TeamProviderResolver
+	logger    *logrus.Logger
 }
 
 func NewConversationService(
 	queries *db.Queries,
-	providerRegistry *llm.ProviderRegistry,
-	encryptionService *crypto.EncryptionService,
-	toolRegistry *llm.ToolRegistry,
+	providers *TeamProviderResolver,
 	logger *logrus.Logger,
 ) *ConversationService {
 	return &ConversationService{
-		queries:           queries,
-		providerRegistry:  providerRegistry,
-		encryptionService: encryptionService,
-		toolRegistry:      toolRegistry,
-		logger:            logger,
+		queries:   queries,
+		providers: providers,
+		logger:    logger,
 	}
 }
 
@@ -75,26 +68,6 @@ func (s *ConversationService) ReplyToMessage(
 		return nil, fmt.Errorf("failed to get conversation: %w", err)
 	}
 
-	// Get team's encrypted API key for this provider
-	apiKeyRecord, err := s.queries.GetTeamLLMAPIKeyByTeamID(ctx, db.GetTeamLLMAPIKeyByTeamIDParams{
-		TeamID:   conversation.TeamID,
-		Provider: conversation.Provider,
-	})
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, ErrAPIKeyNotFound
-		}
-		s.logger.WithError(err).Error("Failed to get API key")
-		return nil, fmt.Errorf("failed to get API key: %w", err)
-	}
-
-	// Decrypt the API key
-	decryptedKey, err := s.encryptionService.Decrypt(apiKeyRecord.EncryptedKey)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to decrypt API key")
-		return nil, fmt.Errorf("failed to decrypt API key: %w", err)
-	}
-
 	// Get conversation message history
 	dbMessages, err := s.queries.GetMessagesByConversationID(ctx, conversationID)
 	if err != nil {
@@ -111,11 +84,10 @@ func (s *ConversationService) ReplyToMessage(
 		})
 	}
 
-	// Get LLM provider instance
-	provider, err := s.providerRegistry.GetProvider(conversation.Provider, decryptedKey, s.toolRegistry)
+	// Get LLM provider instance using the team's API key
+	provider, err := s.providers.Resolve(ctx, conversation.TeamID, conversation.Provider)
 	if err != nil {
-		s.logger.WithError(err).WithField("provider", conversation.Provider).Error("Failed to get provider")
-		return nil, fmt.Errorf("failed to get provider: %w", err)
+		return nil, err
 	}
 
 	// Start streaming from LLM provider with tool registry
@@ -126,13 +98,6 @@ func (s *ConversationService) ReplyToMessage(
 		return nil, fmt.Errorf("failed to start streaming: %w", err)
 	}
 
-	// Update last used timestamp for API key
-	go func() {
-		if err := s.queries.UpdateLastUsedAt(context.Background(), apiKeyRecord.ID); err != nil {
-			s.logger.WithError(err).Warn("Failed to update API key last used timestamp")
-		}
-	}()
-
 	// Create output channel and goroutine to save assistant response after streaming
 	outChan := make(chan llm.StreamChunk)
 	go func() {
@@ -0,0 +1,502 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+var (
+	ErrCustomFieldNotFound          = errors.New("custom field not found in this project")
+	ErrInvalidCustomFieldValue      = errors.New("invalid custom field value")
+	ErrCustomFieldOptionsRequired   = errors.New("select and multi_select fields need at least one option")
+	ErrCustomFieldOptionsNotAllowed = errors.New("only select and multi_select fields have options")
+)
+
+// maxCustomFieldTextLength bounds the length of text values
+const maxCustomFieldTextLength = 1000
+
+// CustomFieldError explains why a custom field value was rejected.
+// It unwraps to ErrInvalidCustomFieldValue and its message is meant for end users.
+type CustomFieldError struct {
+	Message string
+}
+
+func (e *CustomFieldError) Error() string {
+	return e.Message
+}
+
+func (e *CustomFieldError) Unwrap() error {
+	return ErrInvalidCustomFieldValue
+}
+
+type CustomFieldService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewCustomFieldService(queries *db.Queries, database *sql.DB) *CustomFieldService {
+	return &CustomFieldService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// List returns the project's custom fields ordered by name
+func (s *CustomFieldService) List(ctx context.Context, projectID int64) ([]db.CustomField, error) {
+	fields, err := s.queries.GetCustomFieldsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	if fields == nil {
+		fields = []db.CustomField{}
+	}
+	return fields, nil
+}
+
+// Create defines a custom field on the project's issues
+func (s *CustomFieldService) Create(ctx context.Context, projectID int64, input schemas.CreateCustomFieldInput) (db.CustomField, error) {
+	options, err := customFieldOptions(input.FieldType, input.Options)
+	if err != nil {
+		return db.CustomField{}, err
+	}
+
+	return s.queries.CreateCustomField(ctx, db.CreateCustomFieldParams{
+		ProjectID: projectID,
+		Name:      input.Name,
+		FieldType: input.FieldType,
+		Options:   options,
+	})
+}
+
+// Update renames the field and replaces its options. Values using a removed option lose it,
+// and multi_select values left without options are cleared.
+func (s *CustomFieldService) Update(ctx context.Context, projectID int64, fieldID int64, input schemas.UpdateCustomFieldInput) (db.CustomField, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return db.CustomField{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	field, err := getCustomField(ctx, qtx, projectID, fieldID)
+	if err != nil {
+		return db.CustomField{}, err
+	}
+
+	options, err := customFieldOptions(field.FieldType, input.Options)
+	if err != nil {
+		return db.CustomField{}, err
+	}
+
+	updated, err := qtx.UpdateCustomField(ctx, db.UpdateCustomFieldParams{
+		Name:      input.Name,
+		Options:   options,
+		ID:        fieldID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return db.CustomField{}, err
+	}
+
+	if len(options) > 0 {
+		if err := qtx.RemoveDeletedCustomFieldOptions(ctx, fieldID); err != nil {
+			return db.CustomField{}, fmt.Errorf("failed to remove deleted options from values: %w", err)
+		}
+		if err := qtx.DeleteEmptyCustomFieldValues(ctx, fieldID); err != nil {
+			return db.CustomField{}, fmt.Errorf("failed to clear empty values: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return db.CustomField{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, nil
+}
+
+// Delete removes the field together with its values on every issue
+func (s *CustomFieldService) Delete(ctx context.Context, projectID int64, fieldID int64) error {
+	deleted, err := s.queries.DeleteCustomField(ctx, db.DeleteCustomFieldParams{
+		ID:        fieldID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	if deleted == 0 {
+		return ErrCustomFieldNotFound
+	}
+	return nil
+}
+
+// GetValues returns the issue's custom field values ordered by field name
+func (s *CustomFieldService) GetValues(ctx context.Context, issueID int64) ([]schemas.CustomFieldValue, error) {
+	byIssue, err := s.GetForIssues(ctx, []int64{issueID})
+	if err != nil {
+		return nil, err
+	}
+	if values := byIssue[issueID]; values != nil {
+		return values, nil
+	}
+	return []schemas.CustomFieldValue{}, nil
+}
+
+// GetForIssues loads the custom field values of several issues in one query, keyed by issue ID.
+// Issues without values are absent from the map.
+func (s *CustomFieldService) GetForIssues(ctx context.Context, issueIDs []int64) (map[int64][]schemas.CustomFieldValue, error) {
+	return customFieldValues(ctx, s.queries, issueIDs)
+}
+
+func customFieldValues(ctx context.Context, q *db.Queries, issueIDs []int64) (map[int64][]schemas.CustomFieldValue, error) {
+	byIssue := make(map[int64][]schemas.CustomFieldValue)
+	if len(issueIDs) == 0 {
+		return byIssue, nil
+	}
+
+	rows, err := q.GetCustomFieldValuesByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field values: %w", err)
+	}
+
+	for _, row := range rows {
+		byIssue[row.IssueID] = append(byIssue[row.IssueID], schemas.CustomFieldValue{
+			FieldID:   row.FieldID,
+			Name:      row.Name,
+			FieldType: row.FieldType,
+			Value:     customFieldResponseValue(row),
+		})
+	}
+	return byIssue, nil
+}
+
+func getCustomField(ctx context.Context, q *db.Queries, projectID int64, fieldID int64) (db.CustomField, error) {
+	field, err := q.GetCustomFieldByID(ctx, db.GetCustomFieldByIDParams{
+		ID:        fieldID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.CustomField{}, ErrCustomFieldNotFound
+		}
+		return db.CustomField{}, fmt.Errorf("failed to get custom field: %w", err)
+	}
+	return field, nil
+}
+
+// customFieldOptions checks that only select fields have options, and that they do. Never returns nil.
+func customFieldOptions(fieldType string, options []string) ([]string, error) {
+	hasOptions := fieldType == schemas.CustomFieldSelect || fieldType == schemas.CustomFieldMultiSelect
+	switch {
+	case hasOptions && len(options) == 0:
+		return nil, ErrCustomFieldOptionsRequired
+	case !hasOptions && len(options) > 0:
+		return nil, ErrCustomFieldOptionsNotAllowed
+	case options == nil:
+		return []string{}, nil
+	}
+	return options, nil
+}
+
+// customFieldChange is a custom field whose value was set or cleared, with the values formatted as text
+type customFieldChange struct {
+	field    string
+	oldValue null.String
+	newValue null.String
+}
+
+// setCustomFields validates the values against the custom fields of the issue's project and writes them.
+// values is keyed by field ID and a null value clears the field. Returns the fields whose value changed.
+func setCustomFields(ctx context.Context, q *db.Queries, issue db.Issue, values map[int64]json.RawMessage) ([]customFieldChange, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	column, err := q.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column: %w", err)
+	}
+
+	fields, err := q.GetCustomFieldsByProjectID(ctx, int64(column.ProjectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	byID := make(map[int64]db.CustomField, len(fields))
+	for _, field := range fields {
+		byID[field.ID] = field
+	}
+
+	rows, err := q.GetCustomFieldValuesByIssueIDs(ctx, []int64{issue.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom field values: %w", err)
+	}
+	current := make(map[int64]null.String, len(rows))
+	for _, row := range rows {
+		current[row.FieldID] = formatCustomFieldValue(row.FieldType, db.IssueCustomFieldValue{
+			TextValue:    row.TextValue,
+			NumberValue:  row.NumberValue,
+			DateValue:    row.DateValue,
+			UserID:       row.UserID,
+			OptionValues: row.OptionValues,
+		})
+	}
+
+	// Apply in a stable order so the activity entries are too
+	fieldIDs := make([]int64, 0, len(values))
+	for fieldID := range values {
+		fieldIDs = append(fieldIDs, fieldID)
+	}
+	slices.Sort(fieldIDs)
+
+	var changes []customFieldChange
+	for _, fieldID := range fieldIDs {
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, &CustomFieldError{Message: fmt.Sprintf("Custom field %d does not belong to the issue's project", fieldID)}
+		}
+
+		value, err := parseCustomFieldValue(field, values[fieldID])
+		if err != nil {
+			return nil, err
+		}
+
+		var newValue null.String
+		if value == nil {
+			err := q.DeleteIssueCustomFieldValue(ctx, db.DeleteIssueCustomFieldValueParams{
+				IssueID: issue.ID,
+				FieldID: fieldID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to clear custom field: %w", err)
+			}
+		} else {
+			if value.UserID.Valid {
+				if err := checkCustomFieldUser(ctx, q, issue.ID, field, value.UserID.Int64); err != nil {
+					return nil, err
+				}
+			}
+			value.IssueID = issue.ID
+			value.FieldID = fieldID
+			if err := q.SetIssueCustomFieldValue(ctx, *value); err != nil {
+				return nil, fmt.Errorf("failed to set custom field: %w", err)
+			}
+			newValue = formatCustomFieldValue(field.FieldType, db.IssueCustomFieldValue(*value))
+		}
+
+		if current[fieldID] != newValue {
+			changes = append(changes, customFieldChange{
+				field:    "custom:" + field.Name,
+				oldValue: current[fieldID],
+				newValue: newValue,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// recordCustomFieldChanges adds an update to the issue's activity for every changed custom field
+func recordCustomFieldChanges(ctx context.Context, q *db.Queries, actorID int64, issueID int64, changes []customFieldChange) error {
+	for _, change := range changes {
+		err := recordActivity(ctx, q, issueID, actorID, schemas.IssueActivityUpdated, change.field, change.oldValue, change.newValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseCustomFieldValue checks a JSON value against the field's type. Returns nil when the value clears the field:
+// null, an empty text or an empty multi_select list.
+func parseCustomFieldValue(field db.CustomField, raw json.RawMessage) (*db.SetIssueCustomFieldValueParams, error) {
+	if len(raw) == 0 || bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+		return nil, nil
+	}
+
+	invalid := func(expected string) error {
+		return &CustomFieldError{Message: fmt.Sprintf("Custom field %q must be %s", field.Name, expected)}
+	}
+
+	value := &db.SetIssueCustomFieldValueParams{}
+	switch field.FieldType {
+	case schemas.CustomFieldText:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, invalid("a string")
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, nil
+		}
+		if len(text) > maxCustomFieldTextLength {
+			return nil, invalid(fmt.Sprintf("at most %d characters", maxCustomFieldTextLength))
+		}
+		value.TextValue = null.StringFrom(text)
+	case schemas.CustomFieldNumber:
+		var number float64
+		if err := json.Unmarshal(raw, &number); err != nil {
+			return nil, invalid("a number")
+		}
+		value.NumberValue = sql.NullFloat64{Float64: number, Valid: true}
+	case schemas.CustomFieldDate:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, invalid("a YYYY-MM-DD date")
+		}
+		date, err := time.Parse(time.DateOnly, text)
+		if err != nil {
+			return nil, invalid("a YYYY-MM-DD date")
+		}
+		value.DateValue = null.TimeFrom(date)
+	case schemas.CustomFieldUser:
+		var userID int64
+		if err := json.Unmarshal(raw, &userID); err != nil {
+			return nil, invalid("a user ID")
+		}
+		value.UserID = null.IntFrom(userID)
+	case schemas.CustomFieldSelect:
+		var option string
+		if err := json.Unmarshal(raw, &option); err != nil || !slices.Contains(field.Options, option) {
+			return nil, invalid("one of: " + strings.Join(field.Options, ", "))
+		}
+		value.OptionValues = []string{option}
+	case schemas.CustomFieldMultiSelect:
+		var chosen []string
+		if err := json.Unmarshal(raw, &chosen); err != nil {
+			return nil, invalid("a list of options chosen from: " + strings.Join(field.Options, ", "))
+		}
+		options := []string{}
+		for _, option := range chosen {
+			if !slices.Contains(field.Options, option) {
+				return nil, invalid("a list of options chosen from: " + strings.Join(field.Options, ", "))
+			}
+			if !slices.Contains(options, option) {
+				options = append(options, option)
+			}
+		}
+		if len(options) == 0 {
+			return nil, nil
+		}
+		value.OptionValues = options
+	}
+	return value, nil
+}
+
+// checkCustomFieldUser verifies that the user chosen for a user field is a member of the issue's team
+func checkCustomFieldUser(ctx context.Context, q *db.Queries, issueID int64, field db.CustomField, userID int64) error {
+	teamID, err := q.GetTeamIDByIssue(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get issue team: %w", err)
+	}
+
+	isMember, err := q.CheckUserTeamMembership(ctx, db.CheckUserTeamMembershipParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check team membership: %w", err)
+	}
+	if !isMember {
+		return &CustomFieldError{Message: fmt.Sprintf("Custom field %q must be a member of the project's team", field.Name)}
+	}
+	return nil
+}
+
+// formatCustomFieldValue renders a stored value as text for the issue's activity
+func formatCustomFieldValue(fieldType string, value db.IssueCustomFieldValue) null.String {
+	switch fieldType {
+	case schemas.CustomFieldText:
+		return value.TextValue
+	case schemas.CustomFieldNumber:
+		if !value.NumberValue.Valid {
+			return null.String{}
+		}
+		return null.StringFrom(strconv.FormatFloat(value.NumberValue.Float64, 'f', -1, 64))
+	case schemas.CustomFieldDate:
+		return formatActivityDate(value.DateValue)
+	case schemas.CustomFieldUser:
+		return formatActivityID(value.UserID)
+	}
+	if len(value.OptionValues) == 0 {
+		return null.String{}
+	}
+	return null.StringFrom(strings.Join(value.OptionValues, ", "))
+}
+
+// customFieldResponseValue shapes a stored value as described on schemas.CustomFieldValue
+func customFieldResponseValue(row db.GetCustomFieldValuesByIssueIDsRow) interface{} {
+	switch row.FieldType {
+	case schemas.CustomFieldText:
+		return row.TextValue.String
+	case schemas.CustomFieldNumber:
+		return row.NumberValue.Float64
+	case schemas.CustomFieldDate:
+		return row.DateValue.Time.Format(time.DateOnly)
+	case schemas.CustomFieldUser:
+		return schemas.IssueUser{
+			ID:    row.UserID.Int64,
+			Name:  row.UserName.String,
+			Email: row.UserEmail.String,
+		}
+	case schemas.CustomFieldSelect:
+		if len(row.OptionValues) == 0 {
+			return nil
+		}
+		return row.OptionValues[0]
+	}
+	return row.OptionValues
+}
+
+// customFieldFilterParams narrows the project's issues to those whose value of the filtered field matches.
+// Text matches ignore case; select and multi_select fields match issues that chose the option.
+func customFieldFilterParams(ctx context.Context, q *db.Queries, projectID int64, filter schemas.ProjectIssueFilter, params *db.GetProjectIssuesParams) error {
+	field, err := getCustomField(ctx, q, projectID, filter.CustomFieldID.Int64)
+	if err != nil {
+		return err
+	}
+
+	invalid := &CustomFieldError{Message: fmt.Sprintf("Invalid filter value for custom field %q", field.Name)}
+	value := strings.TrimSpace(filter.CustomFieldValue)
+	if value == "" {
+		return invalid
+	}
+
+	params.CustomFieldID = filter.CustomFieldID
+	switch field.FieldType {
+	case schemas.CustomFieldText:
+		params.CustomFieldText = null.StringFrom(value)
+	case schemas.CustomFieldNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return invalid
+		}
+		params.CustomFieldNumber = sql.NullFloat64{Float64: number, Valid: true}
+	case schemas.CustomFieldDate:
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return invalid
+		}
+		params.CustomFieldDate = null.TimeFrom(date)
+	case schemas.CustomFieldUser:
+		userID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return invalid
+		}
+		params.CustomFieldUserID = null.IntFrom(userID)
+	default:
+		params.CustomFieldOption = null.StringFrom(value)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+type IssueActivityService struct {
+	queries *db.Queries
+}
+
+func NewIssueActivityService(queries *db.Queries) *IssueActivityService {
+	return &IssueActivityService{
+		queries: queries,
+	}
+}
+
+// List returns the issue's history oldest first
+func (s *IssueActivityService) List(ctx context.Context, issueID int64) ([]schemas.IssueActivity, error) {
+	rows, err := s.queries.GetIssueActivity(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue activity: %w", err)
+	}
+
+	activity := make([]schemas.IssueActivity, 0, len(rows))
+	for _, row := range rows {
+		entry := schemas.IssueActivity{
+			ID:        row.ID,
+			IssueID:   row.IssueID,
+			Action:    row.Action,
+			Field:     row.Field,
+			OldValue:  row.OldValue,
+			NewValue:  row.NewValue,
+			CreatedAt: row.CreatedAt,
+		}
+		if row.ActorID.Valid {
+			entry.Actor = &schemas.IssueUser{
+				ID:    row.ActorID.Int64,
+				Name:  row.ActorName.String,
+				Email: row.ActorEmail.String,
+			}
+		}
+		activity = append(activity, entry)
+	}
+	return activity, nil
+}
+
+// recordActivity appends an entry to the issue's history. Pass the queries of the transaction making
+// the change so the entry is committed or rolled back together with it. An actorID of 0 records no actor.
+func recordActivity(ctx context.Context, q *db.Queries, issueID int64, actorID int64, action string, field string, oldValue null.String, newValue null.String) error {
+	err := q.CreateIssueActivity(ctx, db.CreateIssueActivityParams{
+		IssueID:  issueID,
+		ActorID:  null.NewInt(actorID, actorID != 0),
+		Action:   action,
+		Field:    null.NewString(field, field != ""),
+		OldValue: oldValue,
+		NewValue: newValue,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record issue activity: %w", err)
+	}
+	return nil
+}
+
+// recordChanges appends an entry for every field that differs between the two versions of the issue.
+// A change of column is recorded as a move.
+func recordChanges(ctx context.Context, q *db.Queries, actorID int64, before db.Issue, after db.Issue) error {
+	if before.ColumnID != after.ColumnID {
+		if err := recordMove(ctx, q, actorID, before.ID, before.ColumnID, after.ColumnID); err != nil {
+			return err
+		}
+	}
+
+	changes := []struct {
+		field    string
+		oldValue null.String
+		newValue null.String
+	}{
+		{"name", null.StringFrom(before.Name), null.StringFrom(after.Name)},
+		{"description", before.Description, after.Description},
+		{"priority", null.StringFrom(before.Priority), null.StringFrom(after.Priority)},
+		{"due_date", formatActivityDate(before.DueDate), formatActivityDate(after.DueDate)},
+		{"parent_id", formatActivityID(before.ParentID), formatActivityID(after.ParentID)},
+		{"estimate_points", formatActivityID(before.EstimatePoints), formatActivityID(after.EstimatePoints)},
+		{"estimate_minutes", formatActivityID(before.EstimateMinutes), formatActivityID(after.EstimateMinutes)},
+		{"sprint_id", formatActivityID(before.SprintID), formatActivityID(after.SprintID)},
+		{"milestone_id", formatActivityID(before.MilestoneID), formatActivityID(after.MilestoneID)},
+	}
+
+	for _, change := range changes {
+		if change.oldValue == change.newValue {
+			continue
+		}
+		err := recordActivity(ctx, q, after.ID, actorID, schemas.IssueActivityUpdated, change.field, change.oldValue, change.newValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordMove appends a move between two columns, identified by their IDs
+func recordMove(ctx context.Context, q *db.Queries, actorID int64, issueID int64, fromColumnID int64, toColumnID int64) error {
+	return recordActivity(ctx, q, issueID, actorID, schemas.IssueActivityMoved, "column_id",
+		null.StringFrom(strconv.FormatInt(fromColumnID, 10)),
+		null.StringFrom(strconv.FormatInt(toColumnID, 10)),
+	)
+}
+
+func formatActivityID(id null.Int) null.String {
+	if !id.Valid {
+		return null.String{}
+	}
+	return null.StringFrom(strconv.FormatInt(id.Int64, 10))
+}
+
+func formatActivityDate(date null.Time) null.String {
+	if !date.Valid {
+		return null.String{}
+	}
+	return null.StringFrom(date.Time.Format(time.DateOnly))
+}
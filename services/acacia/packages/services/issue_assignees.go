@@ -0,0 +1,172 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+// maxIssueAssignees bounds how many people can be assigned when an issue is created
+const maxIssueAssignees = 20
+
+var (
+	ErrAssigneeNotTeamMember = errors.New("assignee is not a member of the project's team")
+	ErrAssigneeNotFound      = errors.New("user is not assigned to this issue")
+	ErrTooManyAssignees      = errors.New("too many assignees")
+)
+
+type IssueAssigneeService struct {
+	queries *db.Queries
+}
+
+func NewIssueAssigneeService(queries *db.Queries) *IssueAssigneeService {
+	return &IssueAssigneeService{
+		queries: queries,
+	}
+}
+
+// Assign adds the user to the issue's assignees and records it in the issue's activity; assigning twice is a no-op
+func (s *IssueService) Assign(ctx context.Context, issueID int64, actorID int64, userID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := assignIssue(ctx, s.queries.WithTx(tx), issueID, actorID, userID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// assignIssue does the work of Assign with the queries of the caller's transaction
+func assignIssue(ctx context.Context, q *db.Queries, issueID int64, actorID int64, userID int64) error {
+	teamID, err := q.GetTeamIDByIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkAssignable(ctx, q, teamID, userID); err != nil {
+		return err
+	}
+
+	added, err := q.AddIssueAssignee(ctx, db.AddIssueAssigneeParams{
+		IssueID: issueID,
+		UserID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to assign issue: %w", err)
+	}
+	if added == 0 {
+		return nil
+	}
+	if err := q.TouchIssues(ctx, []int64{issueID}); err != nil {
+		return fmt.Errorf("failed to touch issue: %w", err)
+	}
+
+	return recordActivity(ctx, q, issueID, actorID, schemas.IssueActivityUpdated, "assignee", null.String{}, formatActivityID(null.IntFrom(userID)))
+}
+
+// Unassign removes the user from the issue's assignees and records it in the issue's activity.
+// Removing the last assignee of an issue in a column that requires one is a WorkflowViolation.
+func (s *IssueService) Unassign(ctx context.Context, issueID int64, actorID int64, userID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	removed, err := qtx.RemoveIssueAssignee(ctx, db.RemoveIssueAssigneeParams{
+		IssueID: issueID,
+		UserID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove assignee: %w", err)
+	}
+	if removed == 0 {
+		return ErrAssigneeNotFound
+	}
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if err := checkRequiredFields(ctx, qtx, issue); err != nil {
+		return err
+	}
+	if err := qtx.TouchIssues(ctx, []int64{issueID}); err != nil {
+		return fmt.Errorf("failed to touch issue: %w", err)
+	}
+
+	err = recordActivity(ctx, qtx, issueID, actorID, schemas.IssueActivityUpdated, "assignee", formatActivityID(null.IntFrom(userID)), null.String{})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetPeople returns the issue's reporter (nil when unknown) and assignees
+func (s *IssueAssigneeService) GetPeople(ctx context.Context, issue db.Issue) (*schemas.IssueUser, []schemas.IssueUser, error) {
+	var reporter *schemas.IssueUser
+	if issue.ReporterID.Valid {
+		user, err := s.queries.GetUserByID(ctx, issue.ReporterID.Int64)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, nil, fmt.Errorf("failed to get reporter: %w", err)
+		}
+		if err == nil {
+			reporter = &schemas.IssueUser{
+				ID:    user.ID,
+				Name:  user.Name,
+				Email: user.Email,
+			}
+		}
+	}
+
+	rows, err := s.queries.GetIssueAssignees(ctx, issue.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get assignees: %w", err)
+	}
+
+	assignees := make([]schemas.IssueUser, 0, len(rows))
+	for _, row := range rows {
+		assignees = append(assignees, schemas.IssueUser{
+			ID:    row.ID,
+			Name:  row.Name,
+			Email: row.Email,
+		})
+	}
+
+	return reporter, assignees, nil
+}
+
+// checkAssignable verifies that the user belongs to the team owning the issue
+func checkAssignable(ctx context.Context, q *db.Queries, teamID int64, userID int64) error {
+	isMember, err := q.CheckUserTeamMembership(ctx, db.CheckUserTeamMembershipParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check team membership: %w", err)
+	}
+	if !isMember {
+		return ErrAssigneeNotTeamMember
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/llm"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guregu/null"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrProjectHasNoColumns  = errors.New("project has no columns")
+	ErrColumnNotInProject   = errors.New("column does not belong to this project")
+	ErrInvalidModelResponse = errors.New("model returned an invalid response")
+)
+
+const issueBreakdownSystemPrompt = `You are a project planning assistant for a Kanban board.
+Break the user's text down into small, independently deliverable issues.
+Each issue needs a short imperative title (at most 120 characters) and a description with enough context and acceptance criteria to start work.
+Place each issue in the most fitting board column. Only use the column names listed below.
+Board columns, in workflow order:
+%s`
+
+// issueBreakdownSchema is the structured output the model must return
+var issueBreakdownSchema = llm.StructuredOutputSchema{
+	Name:        "issue_breakdown",
+	Description: "Draft issues derived from the user's text",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"issues": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"title":       map[string]any{"type": "string"},
+						"description": map[string]any{"type": "string"},
+						"column":      map[string]any{"type": "string"},
+					},
+					"required":             []string{"title", "description", "column"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"issues"},
+		"additionalProperties": false,
+	},
+}
+
+type issueBreakdownResponse struct {
+	Issues []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Column      string `json:"column"`
+	} `json:"issues"`
+}
+
+// IssueBreakdownService turns free text into draft issues with the team's LLM
+// and creates the drafts the user accepts
+type IssueBreakdownService struct {
+	queries   *db.Queries
+	db        *sql.DB
+	providers *TeamProviderResolver
+	logger    *logrus.Logger
+}
+
+func NewIssueBreakdownService(queries *db.Queries, database *sql.DB, providers *TeamProviderResolver, logger *logrus.Logger) *IssueBreakdownService {
+	return &IssueBreakdownService{
+		queries:   queries,
+		db:        database,
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// GenerateDrafts asks the team's model to split the text into draft issues for the project.
+// Nothing is persisted; drafts are returned for the user to edit.
+func (s *IssueBreakdownService) GenerateDrafts(ctx context.Context, projectID int64, input schemas.GenerateIssueDraftsInput) ([]schemas.IssueDraft, error) {
+	project, err := s.queries.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := s.queries.GetProjectStatusColumnsByProjectID(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return nil, ErrProjectHasNoColumns
+	}
+
+	provider, err := s.providers.Resolve(ctx, project.TeamID, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	completer, ok := provider.(llm.StructuredCompleter)
+	if !ok {
+		return nil, llm.ErrStructuredOutputNotSupported
+	}
+
+	columnNames := make([]string, 0, len(columns))
+	for _, column := range columns {
+		columnNames = append(columnNames, "- "+column.Name)
+	}
+
+	messages := []llm.Message{
+		{Role: "system", Content: fmt.Sprintf(issueBreakdownSystemPrompt, strings.Join(columnNames, "\n"))},
+		{Role: "user", Content: input.Text},
+	}
+
+	raw, err := completer.CompleteStructured(ctx, messages, input.Model, issueBreakdownSchema)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to generate issue breakdown")
+		return nil, err
+	}
+
+	var parsed issueBreakdownResponse
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		s.logger.WithError(err).Warn("Failed to parse issue breakdown response")
+		return nil, ErrInvalidModelResponse
+	}
+
+	drafts := make([]schemas.IssueDraft, 0, len(parsed.Issues))
+	for _, issue := range parsed.Issues {
+		title := strings.TrimSpace(issue.Title)
+		if title == "" {
+			continue
+		}
+		// varchar(255) counts characters, and cutting bytes could split one
+		if runes := []rune(title); len(runes) > 255 {
+			title = string(runes[:255])
+		}
+
+		// Unknown column names fall back to the first column of the board
+		column := columns[0]
+		for _, candidate := range columns {
+			if strings.EqualFold(candidate.Name, strings.TrimSpace(issue.Column)) {
+				column = candidate
+				break
+			}
+		}
+
+		drafts = append(drafts, schemas.IssueDraft{
+			Name:        title,
+			Description: strings.TrimSpace(issue.Description),
+			ColumnID:    column.ID,
+			ColumnName:  column.Name,
+		})
+	}
+
+	return drafts, nil
+}
+
+// CommitDrafts creates all accepted drafts in a single transaction with the user as reporter.
+// Every draft must target a column of the given project, and drafts cannot take a column over a hard WIP limit
+// or land in a column whose required fields they lack.
+func (s *IssueBreakdownService) CommitDrafts(ctx context.Context, projectID int64, userID int64, drafts []schemas.IssueDraftInput) ([]db.Issue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	columns, err := qtx.GetProjectStatusColumnsByProjectID(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project columns: %w", err)
+	}
+
+	projectColumns := make(map[int64]bool, len(columns))
+	for _, column := range columns {
+		projectColumns[column.ID] = true
+	}
+
+	issues := make([]db.Issue, 0, len(drafts))
+	for _, draft := range drafts {
+		if !projectColumns[draft.ColumnID] {
+			return nil, ErrColumnNotInProject
+		}
+
+		// Soft limit warnings are not reported for drafts; the board shows the over-limit columns
+		if _, err := checkWIPLimit(ctx, qtx, draft.ColumnID); err != nil {
+			return nil, err
+		}
+
+		issue, err := qtx.CreateIssue(ctx, db.CreateIssueParams{
+			Name:        draft.Name,
+			ColumnID:    draft.ColumnID,
+			Description: null.NewString(draft.Description, draft.Description != ""),
+			ReporterID:  null.IntFrom(userID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create issue: %w", err)
+		}
+		if err := checkRequiredFields(ctx, qtx, issue); err != nil {
+			return nil, err
+		}
+		err = recordActivity(ctx, qtx, issue.ID, userID, schemas.IssueActivityCreated, "", null.String{}, null.StringFrom(issue.Name))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return issues, nil
+}
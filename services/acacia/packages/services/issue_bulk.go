@@ -0,0 +1,124 @@
+package services
+
+import (
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/guregu/null"
+)
+
+var ErrBulkIssueNotAccessible = errors.New("issue not found or not accessible")
+
+// BulkIssueOutcome is what happened to one issue of a bulk request.
+// RolledBack is set on issues that succeeded when an all-or-nothing request was undone.
+type BulkIssueOutcome struct {
+	IssueID    int64
+	Err        error
+	WIPWarning *schemas.WIPLimitWarning
+	RolledBack bool
+}
+
+// Bulk applies the operation to every issue in one transaction, in the order given; repeated IDs are applied once.
+// Each issue is checked against the caller's access and the archived state of its project and runs under its own
+// savepoint, so a failing issue leaves the others applied. With input.AllOrNothing any failure rolls back every issue.
+// Issue failures are reported in the outcomes; the returned error is only set when the transaction itself fails.
+func (s *IssueService) Bulk(ctx context.Context, actorID int64, input schemas.BulkIssueInput) ([]BulkIssueOutcome, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	var outcomes []BulkIssueOutcome
+	failed := false
+	for _, issueID := range input.IssueIDs {
+		if slices.ContainsFunc(outcomes, func(o BulkIssueOutcome) bool { return o.IssueID == issueID }) {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT bulk_issue"); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+
+		warning, err := applyBulkOperation(ctx, qtx, actorID, issueID, input)
+		if err != nil {
+			failed = true
+			if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_issue"); rbErr != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", rbErr)
+			}
+		} else if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT bulk_issue"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+
+		outcomes = append(outcomes, BulkIssueOutcome{
+			IssueID:    issueID,
+			Err:        err,
+			WIPWarning: warning,
+		})
+	}
+
+	if failed && input.AllOrNothing {
+		for i := range outcomes {
+			outcomes[i].RolledBack = outcomes[i].Err == nil
+			outcomes[i].WIPWarning = nil
+		}
+		return outcomes, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return outcomes, nil
+}
+
+// applyBulkOperation checks access to one issue and applies the bulk operation to it
+func applyBulkOperation(ctx context.Context, q *db.Queries, actorID int64, issueID int64, input schemas.BulkIssueInput) (*schemas.WIPLimitWarning, error) {
+	if err := auth.CheckIssueAccess(ctx, q, issueID); err != nil {
+		return nil, ErrBulkIssueNotAccessible
+	}
+	if err := auth.CheckIssueWritable(ctx, q, issueID); err != nil {
+		return nil, err
+	}
+
+	switch input.Operation {
+	case schemas.BulkIssueMove:
+		_, warning, err := moveIssue(ctx, q, issueID, actorID, schemas.MoveIssueInput{ColumnID: input.ColumnID})
+		return warning, err
+	case schemas.BulkIssueAssign:
+		return nil, assignIssue(ctx, q, issueID, actorID, input.UserID)
+	case schemas.BulkIssueLabel:
+		return nil, labelIssue(ctx, q, issueID, actorID, input.LabelID)
+	case schemas.BulkIssueSetPriority:
+		current, err := q.GetIssueByID(ctx, issueID)
+		if err != nil {
+			return nil, err
+		}
+		issue, err := q.UpdateIssue(ctx, db.UpdateIssueParams{
+			ID:       issueID,
+			Name:     current.Name,
+			ColumnID: current.ColumnID,
+			Priority: null.StringFrom(input.Priority),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set issue priority: %w", err)
+		}
+		if err := checkRequiredFields(ctx, q, issue); err != nil {
+			return nil, err
+		}
+		return nil, recordChanges(ctx, q, actorID, current, issue)
+	case schemas.BulkIssueArchive:
+		_, err := setIssueArchived(ctx, q, issueID, actorID, true)
+		return nil, err
+	case schemas.BulkIssueDelete:
+		return nil, deleteIssue(ctx, q, issueID, actorID, nil)
+	}
+	return nil, fmt.Errorf("unknown bulk operation %q", input.Operation)
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+var (
+	ErrCommentNotFound       = errors.New("comment not found")
+	ErrCommentParentNotFound = errors.New("parent comment not found on this issue")
+	ErrCommentReplyToReply   = errors.New("replies cannot be replied to")
+	ErrCommentNotAuthor      = errors.New("only the author can change a comment")
+)
+
+type IssueCommentService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewIssueCommentService(queries *db.Queries, database *sql.DB) *IssueCommentService {
+	return &IssueCommentService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// List returns the issue's top-level comments oldest first, each with its replies oldest first
+func (s *IssueCommentService) List(ctx context.Context, issueID int64) ([]schemas.IssueComment, error) {
+	rows, err := s.queries.GetIssueComments(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	commentIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		commentIDs = append(commentIDs, row.ID)
+	}
+	mentions, err := s.getMentions(ctx, s.queries, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]schemas.IssueComment, 0, len(rows))
+	position := make(map[int64]int)
+	for _, row := range rows {
+		comment := schemas.IssueComment{
+			ID:                row.ID,
+			IssueID:           row.IssueID,
+			ParentID:          row.ParentID,
+			Body:              row.Body,
+			Mentions:          mentionsOf(mentions, row.ID),
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+			HasSerializedBody: row.HasSerializedBody,
+		}
+		if row.AuthorID.Valid {
+			comment.Author = &schemas.IssueUser{
+				ID:    row.AuthorID.Int64,
+				Name:  row.AuthorName.String,
+				Email: row.AuthorEmail.String,
+			}
+		}
+
+		if !row.ParentID.Valid {
+			position[row.ID] = len(comments)
+			comments = append(comments, comment)
+			continue
+		}
+		if i, ok := position[row.ParentID.Int64]; ok {
+			comments[i].Replies = append(comments[i].Replies, comment)
+		}
+	}
+
+	return comments, nil
+}
+
+// Create adds a comment or, when input.ParentID is set, a reply to a top-level comment of the same issue.
+// Mentions of team members in the body are recorded with the comment.
+func (s *IssueCommentService) Create(ctx context.Context, issueID int64, authorID int64, input schemas.CreateCommentInput) (*schemas.IssueComment, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	if input.ParentID.Valid {
+		parent, err := qtx.GetIssueCommentByID(ctx, db.GetIssueCommentByIDParams{
+			ID:      input.ParentID.Int64,
+			IssueID: issueID,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, ErrCommentParentNotFound
+			}
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parent.ParentID.Valid {
+			return nil, ErrCommentReplyToReply
+		}
+	}
+
+	comment, err := qtx.CreateIssueComment(ctx, db.CreateIssueCommentParams{
+		IssueID:           issueID,
+		ParentID:          input.ParentID,
+		AuthorID:          null.IntFrom(authorID),
+		Body:              input.Body,
+		HasSerializedBody: input.BodySerialized != nil && *input.BodySerialized != "",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	if err := s.setMentions(ctx, qtx, comment); err != nil {
+		return nil, err
+	}
+
+	result, err := s.build(ctx, qtx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// Update replaces the comment's body and mentions. Only the author can edit a comment.
+// Also reports whether the comment had a serialized body before, so a body that was dropped can be removed from storage.
+func (s *IssueCommentService) Update(ctx context.Context, issueID int64, commentID int64, userID int64, body string, hasSerializedBody bool) (*schemas.IssueComment, bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	previous, err := checkCommentAuthor(ctx, qtx, issueID, commentID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	comment, err := qtx.UpdateIssueComment(ctx, db.UpdateIssueCommentParams{
+		ID:                commentID,
+		Body:              body,
+		HasSerializedBody: hasSerializedBody,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	if err := s.setMentions(ctx, qtx, comment); err != nil {
+		return nil, false, err
+	}
+
+	result, err := s.build(ctx, qtx, comment)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, previous.HasSerializedBody, nil
+}
+
+// Delete removes the comment together with its replies. Only the author can delete a comment.
+// Returns the IDs of the removed comments that had a serialized body, so it can be removed from storage.
+func (s *IssueCommentService) Delete(ctx context.Context, issueID int64, commentID int64, userID int64) ([]int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := checkCommentAuthor(ctx, qtx, issueID, commentID, userID); err != nil {
+		return nil, err
+	}
+
+	rows, err := qtx.GetIssueComments(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+
+	var serialized []int64
+	for _, row := range rows {
+		removed := row.ID == commentID || row.ParentID.Int64 == commentID
+		if removed && row.HasSerializedBody {
+			serialized = append(serialized, row.ID)
+		}
+	}
+
+	if _, err := qtx.DeleteIssueComment(ctx, commentID); err != nil {
+		return nil, fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// checkCommentAuthor verifies that the comment belongs to the issue and that the user wrote it, and returns it
+func checkCommentAuthor(ctx context.Context, q *db.Queries, issueID int64, commentID int64, userID int64) (db.IssueComment, error) {
+	comment, err := q.GetIssueCommentByID(ctx, db.GetIssueCommentByIDParams{
+		ID:      commentID,
+		IssueID: issueID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return comment, ErrCommentNotFound
+		}
+		return comment, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if !comment.AuthorID.Valid || comment.AuthorID.Int64 != userID {
+		return comment, ErrCommentNotAuthor
+	}
+	return comment, nil
+}
+
+// setMentions replaces the comment's mentions with the team members mentioned in its body
+func (s *IssueCommentService) setMentions(ctx context.Context, q *db.Queries, comment db.IssueComment) error {
+	userIDs, err := resolveMentions(ctx, q, comment.IssueID, comment.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := q.DeleteCommentMentions(ctx, comment.ID); err != nil {
+		return fmt.Errorf("failed to clear mentions: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		err := q.AddCommentMention(ctx, db.AddCommentMentionParams{
+			CommentID: comment.ID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add mention: %w", err)
+		}
+	}
+	return nil
+}
+
+// build loads the author and mentions of a single comment
+func (s *IssueCommentService) build(ctx context.Context, q *db.Queries, comment db.IssueComment) (*schemas.IssueComment, error) {
+	mentions, err := s.getMentions(ctx, q, []int64{comment.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &schemas.IssueComment{
+		ID:                comment.ID,
+		IssueID:           comment.IssueID,
+		ParentID:          comment.ParentID,
+		Body:              comment.Body,
+		Mentions:          mentionsOf(mentions, comment.ID),
+		CreatedAt:         comment.CreatedAt,
+		UpdatedAt:         comment.UpdatedAt,
+		HasSerializedBody: comment.HasSerializedBody,
+	}
+
+	if comment.AuthorID.Valid {
+		author, err := q.GetUserByID(ctx, comment.AuthorID.Int64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment author: %w", err)
+		}
+		result.Author = &schemas.IssueUser{
+			ID:    author.ID,
+			Name:  author.Name,
+			Email: author.Email,
+		}
+	}
+
+	return result, nil
+}
+
+// getMentions loads the mentioned users of several comments in one query, keyed by comment ID
+func (s *IssueCommentService) getMentions(ctx context.Context, q *db.Queries, commentIDs []int64) (map[int64][]schemas.IssueUser, error) {
+	byComment := make(map[int64][]schemas.IssueUser)
+	if len(commentIDs) == 0 {
+		return byComment, nil
+	}
+
+	rows, err := q.GetMentionsByCommentIDs(ctx, commentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	for _, row := range rows {
+		byComment[row.CommentID] = append(byComment[row.CommentID], schemas.IssueUser{
+			ID:    row.ID,
+			Name:  row.Name,
+			Email: row.Email,
+		})
+	}
+	return byComment, nil
+}
+
+// mentionsOf returns the comment's mentions, never nil
+func mentionsOf(mentions map[int64][]schemas.IssueUser, commentID int64) []schemas.IssueUser {
+	if users := mentions[commentID]; users != nil {
+		return users
+	}
+	return []schemas.IssueUser{}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/llm"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/guregu/null"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// duplicateCandidateLimit is how many full-text matches are considered per check
+	duplicateCandidateLimit = 20
+	// maxDuplicateCandidates is how many candidates are returned to the client
+	maxDuplicateCandidates = 5
+	minDuplicateTextScore  = 0.1
+	// minDuplicateEmbeddingScore applies instead of the text threshold when embeddings are available
+	minDuplicateEmbeddingScore = 0.75
+	// duplicateEmbeddingProvider is the team key used for embeddings when one is configured
+	duplicateEmbeddingProvider = "openai"
+	maxDuplicateEmbeddingInput = 2000
+)
+
+// IssueDuplicateService finds existing issues in a project that look like a new one.
+// Candidates come from full-text search and are re-scored with embeddings when the team
+// has an embedding-capable provider key.
+type IssueDuplicateService struct {
+	queries   *db.Queries
+	providers *TeamProviderResolver
+	logger    *logrus.Logger
+}
+
+func NewIssueDuplicateService(queries *db.Queries, providers *TeamProviderResolver, logger *logrus.Logger) *IssueDuplicateService {
+	return &IssueDuplicateService{
+		queries:   queries,
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// FindCandidates returns likely duplicates of an issue about to be created in the column, best match first
+func (s *IssueDuplicateService) FindCandidates(ctx context.Context, columnID int64, name string, description string) ([]schemas.DuplicateCandidate, error) {
+	column, err := s.queries.GetProjectStatusColumnByID(ctx, columnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column: %w", err)
+	}
+
+	content := name + "\n\n" + description
+
+	rows, err := s.queries.FindSimilarIssuesInProject(ctx, db.FindSimilarIssuesInProjectParams{
+		Content:        content,
+		ProjectID:      column.ProjectID,
+		CandidateLimit: duplicateCandidateLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar issues: %w", err)
+	}
+
+	candidates := make([]schemas.DuplicateCandidate, 0, len(rows))
+	for _, row := range rows {
+		candidates = append(candidates, schemas.DuplicateCandidate{
+			ID:          row.ID,
+			Name:        row.Name,
+			Description: row.Description,
+			ColumnID:    row.ColumnID,
+			Score:       row.Score,
+			TextScore:   row.Score,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	embedded := s.scoreWithEmbeddings(ctx, int64(column.ProjectID), content, candidates)
+
+	filtered := make([]schemas.DuplicateCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if embedded && candidate.Score < minDuplicateEmbeddingScore {
+			continue
+		}
+		if !embedded && candidate.Score < minDuplicateTextScore {
+			continue
+		}
+		filtered = append(filtered, candidate)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].Score > filtered[j].Score
+	})
+	if len(filtered) > maxDuplicateCandidates {
+		filtered = filtered[:maxDuplicateCandidates]
+	}
+
+	return filtered, nil
+}
+
+// scoreWithEmbeddings sets EmbeddingScore and Score on the candidates in place.
+// It reports false, leaving candidates untouched, when embeddings are unavailable.
+func (s *IssueDuplicateService) scoreWithEmbeddings(ctx context.Context, projectID int64, content string, candidates []schemas.DuplicateCandidate) bool {
+	project, err := s.queries.GetProjectByID(ctx, projectID)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get project for duplicate embeddings")
+		return false
+	}
+
+	provider, err := s.providers.Resolve(ctx, project.TeamID, duplicateEmbeddingProvider)
+	if err != nil {
+		if !errors.Is(err, ErrAPIKeyNotFound) {
+			s.logger.WithError(err).Warn("Failed to resolve provider for duplicate embeddings")
+		}
+		return false
+	}
+
+	embedder, ok := provider.(llm.Embedder)
+	if !ok {
+		return false
+	}
+
+	inputs := make([]string, 0, len(candidates)+1)
+	inputs = append(inputs, truncate(content, maxDuplicateEmbeddingInput))
+	for _, candidate := range candidates {
+		inputs = append(inputs, truncate(candidate.Name+"\n\n"+candidate.Description.String, maxDuplicateEmbeddingInput))
+	}
+
+	vectors, err := embedder.Embed(ctx, inputs)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to embed issues for duplicate check, using full-text scores")
+		return false
+	}
+
+	for i := range candidates {
+		similarity := cosineSimilarity(vectors[0], vectors[i+1])
+		candidates[i].EmbeddingScore = null.FloatFrom(similarity)
+		candidates[i].Score = similarity
+	}
+
+	return true
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+var (
+	ErrParentNotFound     = errors.New("parent issue not found")
+	ErrParentOtherProject = errors.New("parent issue belongs to another project")
+	ErrParentCycle        = errors.New("an issue cannot be nested under itself or one of its sub-issues")
+)
+
+type IssueHierarchyService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewIssueHierarchyService(queries *db.Queries, database *sql.DB) *IssueHierarchyService {
+	return &IssueHierarchyService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// SetParent nests the issue under the parent, or detaches it when parentID is null, and records the change
+// in the issue's activity. Returns sql.ErrNoRows when the issue does not exist.
+func (s *IssueHierarchyService) SetParent(ctx context.Context, issueID int64, actorID int64, parentID null.Int) (*db.Issue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		if err := checkParent(ctx, qtx, issue.ColumnID, issueID, parentID.Int64); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := qtx.SetIssueParent(ctx, db.SetIssueParentParams{
+		ID:       issueID,
+		ParentID: parentID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set parent: %w", err)
+	}
+
+	if err := recordChanges(ctx, qtx, actorID, issue, updated); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// GetChildren returns the issue's direct sub-issues and how many of them are done
+func (s *IssueHierarchyService) GetChildren(ctx context.Context, issueID int64) (*schemas.IssueChildren, error) {
+	children, err := s.queries.GetIssueChildren(ctx, null.IntFrom(issueID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sub-issues: %w", err)
+	}
+	if children == nil {
+		children = []db.Issue{}
+	}
+
+	progress, err := subIssueProgress(ctx, s.queries, []int64{issueID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.IssueChildren{
+		Children: children,
+		Progress: progress[issueID],
+	}, nil
+}
+
+// checkParent verifies that the parent is in the same project as the column and, for an existing issue,
+// that nesting the issue under it creates no cycle. The project's columns are locked first so concurrent
+// changes cannot nest two issues under each other. issueID is 0 for an issue that is being created.
+func checkParent(ctx context.Context, q *db.Queries, columnID int64, issueID int64, parentID int64) error {
+	column, err := q.GetProjectStatusColumnByID(ctx, columnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column: %w", err)
+	}
+
+	if _, err := q.LockProjectStatusColumnsByProjectID(ctx, column.ProjectID); err != nil {
+		return fmt.Errorf("failed to lock project columns: %w", err)
+	}
+
+	parent, err := q.GetIssueByID(ctx, parentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrParentNotFound
+		}
+		return fmt.Errorf("failed to get parent issue: %w", err)
+	}
+
+	parentColumn, err := q.GetProjectStatusColumnByID(ctx, parent.ColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get parent column: %w", err)
+	}
+	if parentColumn.ProjectID != column.ProjectID {
+		return ErrParentOtherProject
+	}
+
+	if issueID == 0 {
+		return nil
+	}
+
+	// The parent's ancestors include the parent itself
+	cycle, err := q.IsIssueAncestor(ctx, db.IsIssueAncestorParams{
+		IssueID:    parentID,
+		AncestorID: issueID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check issue ancestors: %w", err)
+	}
+	if cycle {
+		return ErrParentCycle
+	}
+	return nil
+}
+
+// subIssueProgress rolls up the sub-issues of several issues in one query, keyed by parent ID.
+// Issues without sub-issues are absent from the map.
+func subIssueProgress(ctx context.Context, q *db.Queries, issueIDs []int64) (map[int64]schemas.IssueProgress, error) {
+	byParent := make(map[int64]schemas.IssueProgress)
+	if len(issueIDs) == 0 {
+		return byParent, nil
+	}
+
+	rows, err := q.GetSubIssueProgress(ctx, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sub-issue progress: %w", err)
+	}
+
+	for _, row := range rows {
+		byParent[row.ParentID.Int64] = schemas.IssueProgress{
+			Done:  row.Done,
+			Total: row.Total,
+		}
+	}
+	return byParent, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidIssueKey   = errors.New("invalid issue key")
+	ErrIssueKeyNotFound  = errors.New("no issue has this key")
+	ErrIssueKeyAmbiguous = errors.New("issue key matches issues in more than one team")
+)
+
+type IssueKeyService struct {
+	queries *db.Queries
+}
+
+func NewIssueKeyService(queries *db.Queries) *IssueKeyService {
+	return &IssueKeyService{
+		queries: queries,
+	}
+}
+
+// Resolve returns the ID of the issue with the given key, e.g. ACA-142, among the projects of the
+// user's teams. Key prefixes are only unique within a team, so a user in two teams using the same
+// prefix gets ErrIssueKeyAmbiguous for numbers both projects have.
+func (s *IssueKeyService) Resolve(ctx context.Context, userID int64, key string) (int64, error) {
+	prefix, number, ok := schemas.ParseIssueKey(key)
+	if !ok {
+		return 0, ErrInvalidIssueKey
+	}
+
+	issueIDs, err := s.queries.GetIssueIDsByKey(ctx, db.GetIssueIDsByKeyParams{
+		UserID:    userID,
+		KeyPrefix: prefix,
+		Number:    number,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve issue key: %w", err)
+	}
+	switch len(issueIDs) {
+	case 0:
+		return 0, ErrIssueKeyNotFound
+	case 1:
+		return issueIDs[0], nil
+	}
+	return 0, ErrIssueKeyAmbiguous
+}
+
+// Get returns the key of the issue
+func (s *IssueKeyService) Get(ctx context.Context, issueID int64) (string, error) {
+	key, err := s.queries.GetIssueKey(ctx, issueID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get issue key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+var (
+	ErrLabelNotFound      = errors.New("label not found")
+	ErrLabelNotInTeam     = errors.New("label belongs to another team")
+	ErrIssueLabelNotFound = errors.New("label is not applied to this issue")
+)
+
+type IssueLabelService struct {
+	queries *db.Queries
+}
+
+func NewIssueLabelService(queries *db.Queries) *IssueLabelService {
+	return &IssueLabelService{
+		queries: queries,
+	}
+}
+
+// AddLabel applies the label to the issue and records it in the issue's activity; adding twice is a no-op
+func (s *IssueService) AddLabel(ctx context.Context, issueID int64, actorID int64, labelID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := labelIssue(ctx, s.queries.WithTx(tx), issueID, actorID, labelID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// labelIssue does the work of AddLabel with the queries of the caller's transaction
+func labelIssue(ctx context.Context, q *db.Queries, issueID int64, actorID int64, labelID int64) error {
+	teamID, err := q.GetTeamIDByIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkLabelUsable(ctx, q, teamID, labelID); err != nil {
+		return err
+	}
+
+	added, err := q.AddIssueLabel(ctx, db.AddIssueLabelParams{
+		IssueID: issueID,
+		LabelID: labelID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to label issue: %w", err)
+	}
+	if added == 0 {
+		return nil
+	}
+	if err := q.TouchIssues(ctx, []int64{issueID}); err != nil {
+		return fmt.Errorf("failed to touch issue: %w", err)
+	}
+
+	return recordActivity(ctx, q, issueID, actorID, schemas.IssueActivityUpdated, "label", null.String{}, formatActivityID(null.IntFrom(labelID)))
+}
+
+// RemoveLabel takes the label off the issue and records it in the issue's activity.
+// Removing the last label of an issue in a column that requires labels is a WorkflowViolation.
+func (s *IssueService) RemoveLabel(ctx context.Context, issueID int64, actorID int64, labelID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	removed, err := qtx.RemoveIssueLabel(ctx, db.RemoveIssueLabelParams{
+		IssueID: issueID,
+		LabelID: labelID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove label: %w", err)
+	}
+	if removed == 0 {
+		return ErrIssueLabelNotFound
+	}
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if err := checkRequiredFields(ctx, qtx, issue); err != nil {
+		return err
+	}
+	if err := qtx.TouchIssues(ctx, []int64{issueID}); err != nil {
+		return fmt.Errorf("failed to touch issue: %w", err)
+	}
+
+	err = recordActivity(ctx, qtx, issueID, actorID, schemas.IssueActivityUpdated, "label", formatActivityID(null.IntFrom(labelID)), null.String{})
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the issue's labels ordered by name
+func (s *IssueLabelService) Get(ctx context.Context, issueID int64) ([]db.Label, error) {
+	labels, err := s.queries.GetIssueLabels(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue labels: %w", err)
+	}
+	if labels == nil {
+		labels = []db.Label{}
+	}
+	return labels, nil
+}
+
+// GetForIssues loads the labels of several issues in one query, keyed by issue ID.
+// Issues without labels are absent from the map.
+func (s *IssueLabelService) GetForIssues(ctx context.Context, issueIDs []int64) (map[int64][]db.Label, error) {
+	byIssue := make(map[int64][]db.Label)
+	if len(issueIDs) == 0 {
+		return byIssue, nil
+	}
+
+	rows, err := s.queries.GetLabelsByIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue labels: %w", err)
+	}
+
+	for _, row := range rows {
+		byIssue[row.IssueID] = append(byIssue[row.IssueID], db.Label{
+			ID:        row.ID,
+			TeamID:    row.TeamID,
+			Name:      row.Name,
+			Color:     row.Color,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+
+	return byIssue, nil
+}
+
+// checkLabelUsable verifies that the label exists and belongs to the team owning the issue
+func checkLabelUsable(ctx context.Context, q *db.Queries, teamID int64, labelID int64) error {
+	label, err := q.GetLabelByID(ctx, labelID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrLabelNotFound
+		}
+		return fmt.Errorf("failed to get label: %w", err)
+	}
+	if label.TeamID != teamID {
+		return ErrLabelNotInTeam
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrLinkTargetNotFound = errors.New("linked issue not found")
+	ErrLinkOtherTeam      = errors.New("linked issue belongs to another team")
+	ErrLinkToSelf         = errors.New("an issue cannot be linked to itself")
+	ErrIssueLinkNotFound  = errors.New("link not found on this issue")
+)
+
+// reverseLinkTypes maps the reverse view of a directed link type to the type that is stored
+var reverseLinkTypes = map[string]string{
+	schemas.IssueLinkBlockedBy:    schemas.IssueLinkBlocks,
+	schemas.IssueLinkDuplicatedBy: schemas.IssueLinkDuplicates,
+	schemas.IssueLinkClonedBy:     schemas.IssueLinkClones,
+}
+
+type IssueLinkService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewIssueLinkService(queries *db.Queries, database *sql.DB) *IssueLinkService {
+	return &IssueLinkService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// List returns the links of the issue in both directions, oldest first
+func (s *IssueLinkService) List(ctx context.Context, issueID int64) ([]schemas.IssueLinkDetails, error) {
+	rows, err := s.queries.GetIssueLinks(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue links: %w", err)
+	}
+
+	links := make([]schemas.IssueLinkDetails, 0, len(rows))
+	for _, row := range rows {
+		links = append(links, schemas.IssueLinkDetails{
+			ID:       row.ID,
+			LinkType: linkTypeFrom(row.LinkType, row.IssueID == issueID),
+			Issue: schemas.LinkedIssue{
+				ID:        row.OtherIssueID,
+				Name:      row.OtherIssueName,
+				ColumnID:  row.OtherColumnID,
+				ProjectID: row.OtherProjectID,
+				Done:      row.OtherIsDone,
+			},
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return links, nil
+}
+
+// Create links the issue to another issue of the same team, possibly in another project.
+// Reverse types such as blocked_by are stored as the forward type with the issues swapped.
+// Both issues count as updated, since their links are part of what they look like.
+func (s *IssueLinkService) Create(ctx context.Context, issueID int64, input schemas.CreateIssueLinkInput) (*schemas.IssueLinkDetails, error) {
+	if input.LinkedIssueID == issueID {
+		return nil, ErrLinkToSelf
+	}
+
+	teamID, err := s.queries.GetTeamIDByIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	linkedTeamID, err := s.queries.GetTeamIDByIssue(ctx, input.LinkedIssueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrLinkTargetNotFound
+		}
+		return nil, fmt.Errorf("failed to get linked issue team: %w", err)
+	}
+	if linkedTeamID != teamID {
+		return nil, ErrLinkOtherTeam
+	}
+
+	params := db.CreateIssueLinkParams{
+		IssueID:       issueID,
+		LinkedIssueID: input.LinkedIssueID,
+		LinkType:      input.LinkType,
+	}
+	if stored, ok := reverseLinkTypes[input.LinkType]; ok {
+		params = db.CreateIssueLinkParams{
+			IssueID:       input.LinkedIssueID,
+			LinkedIssueID: issueID,
+			LinkType:      stored,
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	link, err := qtx.CreateIssueLink(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.TouchIssues(ctx, []int64{link.IssueID, link.LinkedIssueID}); err != nil {
+		return nil, fmt.Errorf("failed to touch linked issues: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	linked, err := s.queries.GetIssueByID(ctx, input.LinkedIssueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked issue: %w", err)
+	}
+	column, err := s.queries.GetProjectStatusColumnByID(ctx, linked.ColumnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked issue column: %w", err)
+	}
+
+	return &schemas.IssueLinkDetails{
+		ID:       link.ID,
+		LinkType: input.LinkType,
+		Issue: schemas.LinkedIssue{
+			ID:        linked.ID,
+			Name:      linked.Name,
+			ColumnID:  linked.ColumnID,
+			ProjectID: column.ProjectID,
+			Done:      column.IsDone,
+		},
+		CreatedAt: link.CreatedAt,
+	}, nil
+}
+
+// Delete removes a link the issue is on either end of; both of its issues count as updated
+func (s *IssueLinkService) Delete(ctx context.Context, issueID int64, linkID int64) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	link, err := qtx.DeleteIssueLink(ctx, db.DeleteIssueLinkParams{
+		ID:      linkID,
+		IssueID: issueID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrIssueLinkNotFound
+		}
+		return fmt.Errorf("failed to delete issue link: %w", err)
+	}
+	if err := qtx.TouchIssues(ctx, []int64{link.IssueID, link.LinkedIssueID}); err != nil {
+		return fmt.Errorf("failed to touch linked issues: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// IsBlocked reports whether the issue is blocked by an issue that is not in a done column
+func (s *IssueLinkService) IsBlocked(ctx context.Context, issueID int64) (bool, error) {
+	blocked, err := blockedIssues(ctx, s.queries, []int64{issueID})
+	if err != nil {
+		return false, err
+	}
+	return blocked[issueID], nil
+}
+
+// linkTypeFrom names a stored link type as seen from one of its issues
+func linkTypeFrom(stored string, outgoing bool) string {
+	if outgoing {
+		return stored
+	}
+	for reverse, forward := range reverseLinkTypes {
+		if forward == stored {
+			return reverse
+		}
+	}
+	return stored
+}
+
+// blockedIssues reports which of the issues are blocked by a live issue that is not in a done column.
+// Archived blockers no longer block.
+func blockedIssues(ctx context.Context, q *db.Queries, issueIDs []int64) (map[int64]bool, error) {
+	blocked := make(map[int64]bool)
+	if len(issueIDs) == 0 {
+		return blocked, nil
+	}
+
+	ids, err := q.GetBlockedIssueIDs(ctx, issueIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blocked issues: %w", err)
+	}
+	for _, id := range ids {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+)
+
+const defaultIssueSearchLimit = 20
+
+var ErrInvalidSearchCursor = errors.New("invalid search cursor")
+
+// issueSearchCursor is the keyset position of the last row on a page.
+// Sort and order are included so a cursor cannot be replayed against a different ordering.
+type issueSearchCursor struct {
+	Sort    string  `json:"s"`
+	Order   string  `json:"o"`
+	SortKey float64 `json:"k"`
+	ID      int64   `json:"id"`
+}
+
+type IssueSearchService struct {
+	queries      *db.Queries
+	labelService *IssueLabelService
+}
+
+func NewIssueSearchService(queries *db.Queries) *IssueSearchService {
+	return &IssueSearchService{
+		queries:      queries,
+		labelService: NewIssueLabelService(queries),
+	}
+}
+
+// Search runs a full-text search over the issues visible to the user.
+// Results are ordered by relevance when a query is given and by creation date otherwise.
+func (s *IssueSearchService) Search(ctx context.Context, userID int64, input schemas.SearchIssuesInput) (*schemas.SearchIssuesResponse, error) {
+	sortBy := input.Sort
+	if sortBy == "" {
+		sortBy = schemas.IssueSearchSortRelevance
+		if input.Query == "" {
+			sortBy = schemas.IssueSearchSortCreatedAt
+		}
+	}
+
+	order := input.Order
+	if order == "" {
+		order = schemas.IssueSearchOrderDesc
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultIssueSearchLimit
+	}
+
+	var assigneeID null.Int
+	if input.AssignedToMe {
+		assigneeID = null.IntFrom(userID)
+	}
+
+	params := db.SearchIssuesParams{
+		Query:           input.Query,
+		UserID:          userID,
+		ProjectID:       input.ProjectID,
+		ColumnID:        input.ColumnID,
+		CreatedAfter:    input.CreatedAfter,
+		CreatedBefore:   input.CreatedBefore,
+		UpdatedAfter:    input.UpdatedAfter,
+		UpdatedBefore:   input.UpdatedBefore,
+		AssigneeID:      assigneeID,
+		Priority:        null.NewString(input.Priority, input.Priority != ""),
+		LabelID:         input.LabelID,
+		DueAfter:        input.DueAfter,
+		DueBefore:       input.DueBefore,
+		IncludeArchived: input.IncludeArchived,
+		SortBy:          sortBy,
+		SortDesc:        order == schemas.IssueSearchOrderDesc,
+		// Fetch one extra row to know whether another page exists
+		PageLimit: int32(limit + 1),
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeIssueSearchCursor(input.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Sort != sortBy || cursor.Order != order {
+			return nil, ErrInvalidSearchCursor
+		}
+		params.CursorKey = sql.NullFloat64{Float64: cursor.SortKey, Valid: true}
+		params.CursorID = null.IntFrom(cursor.ID)
+	}
+
+	rows, err := s.queries.SearchIssues(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+
+	var nextCursor *string
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		encoded, err := encodeIssueSearchCursor(issueSearchCursor{
+			Sort:    sortBy,
+			Order:   order,
+			SortKey: last.SortKey,
+			ID:      last.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = &encoded
+	}
+
+	issueIDs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		issueIDs = append(issueIDs, row.ID)
+	}
+	labels, err := s.labelService.GetForIssues(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]schemas.IssueSearchResult, 0, len(rows))
+	for _, row := range rows {
+		issueLabels := labels[row.ID]
+		if issueLabels == nil {
+			issueLabels = []db.Label{}
+		}
+
+		results = append(results, schemas.IssueSearchResult{
+			ID:            row.ID,
+			Name:          row.Name,
+			Description:   row.Description,
+			ColumnID:      row.ColumnID,
+			ProjectID:     row.ProjectID,
+			Priority:      row.Priority,
+			DueDate:       row.DueDate,
+			Labels:        issueLabels,
+			CreatedAt:     row.CreatedAt,
+			UpdatedAt:     row.UpdatedAt,
+			Rank:          row.Rank,
+			NameHighlight: row.NameHighlight,
+			Snippet:       row.Snippet,
+		})
+	}
+
+	return &schemas.SearchIssuesResponse{
+		Issues:     results,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func encodeIssueSearchCursor(c issueSearchCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode search cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeIssueSearchCursor(s string) (issueSearchCursor, error) {
+	var c issueSearchCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidSearchCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == 0 {
+		return c, ErrInvalidSearchCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,697 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+)
+
+var (
+	ErrDuplicateTargetNotFound     = errors.New("duplicate target issue not found")
+	ErrDuplicateTargetOtherProject = errors.New("duplicate target issue belongs to another project")
+	ErrMoveNeighbourNotInColumn    = errors.New("neighbour issue is not in the target column")
+	ErrInvalidMoveNeighbours       = errors.New("neighbour issues are not in order")
+	ErrIssueAlreadyInProject       = errors.New("issue already belongs to this project")
+)
+
+type IssueService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewIssueService(queries *db.Queries, database *sql.DB) *IssueService {
+	return &IssueService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// Create inserts the issue with its assignees and labels and, when duplicateOf is set, links it as a duplicate,
+// all in one transaction. The duplicate target and the parent must be in the same project as the new issue.
+// The returned warning is set when the issue took its column over a soft WIP limit.
+// The issue must have every field its column requires, otherwise a *WorkflowViolation is returned.
+// The reporter is recorded as the actor of the issue's first activity entry.
+// customFields holds values of the project's custom fields keyed by field ID; an invalid value returns a *CustomFieldError.
+// beforeCommit, when set, runs with the new issue just before the transaction commits; if it fails nothing is created.
+func (s *IssueService) Create(ctx context.Context, params db.CreateIssueParams, duplicateOf null.Int, assigneeIDs []int64, labelIDs []int64, customFields map[int64]json.RawMessage, beforeCommit func(issue *db.Issue) error) (*db.Issue, *schemas.WIPLimitWarning, error) {
+	if len(assigneeIDs) > maxIssueAssignees {
+		return nil, nil, ErrTooManyAssignees
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	if duplicateOf.Valid {
+		if err := checkSameProject(ctx, qtx, params.ColumnID, duplicateOf.Int64); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if params.ParentID.Valid {
+		if err := checkParent(ctx, qtx, params.ColumnID, 0, params.ParentID.Int64); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	warning, err := checkWIPLimit(ctx, qtx, params.ColumnID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issue, err := qtx.CreateIssue(ctx, params)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+
+	err = recordActivity(ctx, qtx, issue.ID, params.ReporterID.Int64, schemas.IssueActivityCreated, "", null.String{}, null.StringFrom(issue.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var teamID int64
+	if len(assigneeIDs) > 0 || len(labelIDs) > 0 {
+		teamID, err = qtx.GetTeamIDByProjectStatusColumn(ctx, params.ColumnID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get column team: %w", err)
+		}
+	}
+
+	for _, userID := range assigneeIDs {
+		if err := checkAssignable(ctx, qtx, teamID, userID); err != nil {
+			return nil, nil, err
+		}
+		_, err := qtx.AddIssueAssignee(ctx, db.AddIssueAssigneeParams{
+			IssueID: issue.ID,
+			UserID:  userID,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to assign issue: %w", err)
+		}
+	}
+
+	for _, labelID := range labelIDs {
+		if err := checkLabelUsable(ctx, qtx, teamID, labelID); err != nil {
+			return nil, nil, err
+		}
+		_, err := qtx.AddIssueLabel(ctx, db.AddIssueLabelParams{
+			IssueID: issue.ID,
+			LabelID: labelID,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to label issue: %w", err)
+		}
+	}
+
+	if duplicateOf.Valid {
+		_, err = qtx.CreateIssueLink(ctx, db.CreateIssueLinkParams{
+			IssueID:       issue.ID,
+			LinkedIssueID: duplicateOf.Int64,
+			LinkType:      schemas.IssueLinkDuplicates,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to link duplicate issue: %w", err)
+		}
+		if err := qtx.TouchIssues(ctx, []int64{duplicateOf.Int64}); err != nil {
+			return nil, nil, fmt.Errorf("failed to touch duplicate issue: %w", err)
+		}
+	}
+
+	if _, err := setCustomFields(ctx, qtx, issue, customFields); err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkRequiredFields(ctx, qtx, issue); err != nil {
+		return nil, nil, err
+	}
+
+	if beforeCommit != nil {
+		if err := beforeCommit(&issue); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &issue, warning, nil
+}
+
+// checkSameProject verifies that the target issue lives in the same project as the column
+func checkSameProject(ctx context.Context, q *db.Queries, columnID int64, targetIssueID int64) error {
+	target, err := q.GetIssueByID(ctx, targetIssueID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrDuplicateTargetNotFound
+		}
+		return fmt.Errorf("failed to get duplicate target: %w", err)
+	}
+
+	column, err := q.GetProjectStatusColumnByID(ctx, columnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column: %w", err)
+	}
+
+	targetColumn, err := q.GetProjectStatusColumnByID(ctx, target.ColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get duplicate target column: %w", err)
+	}
+
+	if column.ProjectID != targetColumn.ProjectID {
+		return ErrDuplicateTargetOtherProject
+	}
+
+	return nil
+}
+
+// Move places the issue in the target column between its new neighbours, as described on
+// schemas.MoveIssueInput. Only the moved issue's rank is rewritten. The target column must be
+// in the issue's project. Moving into another column is subject to the workflow rules and the column's WIP limit
+// and is recorded in the issue's activity; reordering within a column is not.
+func (s *IssueService) Move(ctx context.Context, issueID int64, actorID int64, input schemas.MoveIssueInput) (*db.Issue, *schemas.WIPLimitWarning, error) {
+	if input.BeforeID.Int64 == issueID || input.AfterID.Int64 == issueID {
+		return nil, nil, ErrInvalidMoveNeighbours
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	moved, warning, err := moveIssue(ctx, s.queries.WithTx(tx), issueID, actorID, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return moved, warning, nil
+}
+
+// moveIssue does the work of Move with the queries of the caller's transaction
+func moveIssue(ctx context.Context, q *db.Queries, issueID int64, actorID int64, input schemas.MoveIssueInput) (*db.Issue, *schemas.WIPLimitWarning, error) {
+	issue, err := q.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkColumnInIssueProject(ctx, q, issue.ColumnID, input.ColumnID); err != nil {
+		return nil, nil, err
+	}
+
+	// Serialise moves into the same column so concurrent moves cannot pick the same rank
+	if _, err := q.LockProjectStatusColumn(ctx, input.ColumnID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock column: %w", err)
+	}
+
+	var warning *schemas.WIPLimitWarning
+	if issue.ColumnID != input.ColumnID {
+		if err := checkTransition(ctx, q, issue.ColumnID, input.ColumnID); err != nil {
+			return nil, nil, err
+		}
+		warning, err = checkWIPLimit(ctx, q, input.ColumnID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	lower, upper, err := moveBounds(ctx, q, issueID, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The query takes the midpoint of the bounds, or steps one past the only bound
+	moved, err := q.MoveIssue(ctx, db.MoveIssueParams{
+		ID:        issueID,
+		ColumnID:  input.ColumnID,
+		LowerRank: lower,
+		UpperRank: upper,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to move issue: %w", err)
+	}
+
+	if issue.ColumnID != input.ColumnID {
+		if err := checkRequiredFields(ctx, q, moved); err != nil {
+			return nil, nil, err
+		}
+		if err := recordMove(ctx, q, actorID, issueID, issue.ColumnID, input.ColumnID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &moved, warning, nil
+}
+
+// Update applies the changes to the issue and its custom fields and records every changed field in its activity.
+// Changing its column is subject to the workflow rules and the target column's WIP limit.
+// The updated issue must have every field its column requires, otherwise a *WorkflowViolation is returned.
+func (s *IssueService) Update(ctx context.Context, actorID int64, params db.UpdateIssueParams, customFields map[int64]json.RawMessage) (*db.Issue, *schemas.WIPLimitWarning, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	current, err := qtx.GetIssueByID(ctx, params.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changesColumn := params.ColumnID != 0 && params.ColumnID != current.ColumnID
+
+	var warning *schemas.WIPLimitWarning
+	if changesColumn {
+		// Moving to another project goes through MoveToProject
+		if err := checkColumnInIssueProject(ctx, qtx, current.ColumnID, params.ColumnID); err != nil {
+			return nil, nil, err
+		}
+		// The issue goes to the bottom of the column; the lock keeps concurrent moves from taking the same rank
+		if _, err := qtx.LockProjectStatusColumn(ctx, params.ColumnID); err != nil {
+			return nil, nil, fmt.Errorf("failed to lock column: %w", err)
+		}
+		if err := checkTransition(ctx, qtx, current.ColumnID, params.ColumnID); err != nil {
+			return nil, nil, err
+		}
+		warning, err = checkWIPLimit(ctx, qtx, params.ColumnID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	issue, err := qtx.UpdateIssue(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkRequiredFields(ctx, qtx, issue); err != nil {
+		return nil, nil, err
+	}
+
+	if err := recordChanges(ctx, qtx, actorID, current, issue); err != nil {
+		return nil, nil, err
+	}
+
+	changes, err := setCustomFields(ctx, qtx, issue, customFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := recordCustomFieldChanges(ctx, qtx, actorID, issue.ID, changes); err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &issue, warning, nil
+}
+
+// MoveToProject moves the issue into another project and records the move in its activity.
+// The issue gets a new key in the target project and lands at the bottom of the target column, which must be
+// in the target project; the column's WIP limit and required fields apply but its workflow transitions do not.
+// Values of the source project's custom fields, the sprint, the milestone and the parent are dropped and
+// sub-issues are detached. Labels, assignees and links to issues outside the target project's team are removed.
+// The caller checks access to the target project.
+func (s *IssueService) MoveToProject(ctx context.Context, issueID int64, actorID int64, input schemas.MoveIssueToProjectInput) (*db.Issue, *schemas.WIPLimitWarning, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, err := qtx.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get column: %w", err)
+	}
+	if int64(source.ProjectID) == input.ProjectID {
+		return nil, nil, ErrIssueAlreadyInProject
+	}
+
+	target, err := qtx.GetProjectByID(ctx, input.ProjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columnID, err := targetColumn(ctx, qtx, source, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The lock keeps concurrent moves from taking the same rank or together overrunning the WIP limit
+	if _, err := qtx.LockProjectStatusColumn(ctx, columnID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock column: %w", err)
+	}
+
+	warning, err := checkWIPLimit(ctx, qtx, columnID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	moved, err := qtx.MoveIssueToProject(ctx, db.MoveIssueToProjectParams{
+		ColumnID: columnID,
+		ID:       issueID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to move issue to project: %w", err)
+	}
+
+	if err := qtx.DetachIssueChildren(ctx, null.IntFrom(issueID)); err != nil {
+		return nil, nil, fmt.Errorf("failed to detach sub-issues: %w", err)
+	}
+	if err := qtx.DeleteIssueCustomFieldValues(ctx, issueID); err != nil {
+		return nil, nil, fmt.Errorf("failed to clear custom field values: %w", err)
+	}
+	err = qtx.RemoveIssueLabelsOutsideTeam(ctx, db.RemoveIssueLabelsOutsideTeamParams{
+		IssueID: issueID,
+		TeamID:  target.TeamID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to remove labels: %w", err)
+	}
+	err = qtx.RemoveIssueAssigneesOutsideTeam(ctx, db.RemoveIssueAssigneesOutsideTeamParams{
+		IssueID: issueID,
+		TeamID:  target.TeamID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to remove assignees: %w", err)
+	}
+	unlinked, err := qtx.RemoveIssueLinksOutsideTeam(ctx, db.RemoveIssueLinksOutsideTeamParams{
+		IssueID: issueID,
+		TeamID:  target.TeamID,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to remove links: %w", err)
+	}
+	if err := qtx.TouchIssues(ctx, unlinked); err != nil {
+		return nil, nil, fmt.Errorf("failed to touch unlinked issues: %w", err)
+	}
+
+	if err := checkRequiredFields(ctx, qtx, moved); err != nil {
+		return nil, nil, err
+	}
+
+	err = recordActivity(ctx, qtx, issueID, actorID, schemas.IssueActivityMoved, "project_id",
+		null.StringFrom(strconv.FormatInt(int64(source.ProjectID), 10)),
+		null.StringFrom(strconv.FormatInt(input.ProjectID, 10)),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := recordChanges(ctx, qtx, actorID, issue, moved); err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &moved, warning, nil
+}
+
+// targetColumn picks the column of the target project an issue moving out of source lands in
+func targetColumn(ctx context.Context, q *db.Queries, source db.ProjectStatusColumn, input schemas.MoveIssueToProjectInput) (int64, error) {
+	columns, err := q.GetProjectStatusColumnsByProjectID(ctx, int32(input.ProjectID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get target project columns: %w", err)
+	}
+	if len(columns) == 0 {
+		return 0, ErrProjectHasNoColumns
+	}
+
+	if input.ColumnID.Valid {
+		for _, column := range columns {
+			if column.ID == input.ColumnID.Int64 {
+				return column.ID, nil
+			}
+		}
+		return 0, ErrColumnNotInProject
+	}
+
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, source.Name) {
+			return column.ID, nil
+		}
+	}
+	return columns[0].ID, nil
+}
+
+// Delete removes the issue and records the deletion in its activity, which is kept.
+// Returns sql.ErrNoRows when the issue does not exist or, with ifUpdatedAt given, was changed since.
+func (s *IssueService) Delete(ctx context.Context, issueID int64, actorID int64, ifUpdatedAt []time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := deleteIssue(ctx, s.queries.WithTx(tx), issueID, actorID, ifUpdatedAt); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// deleteIssue does the work of Delete with the queries of the caller's transaction
+func deleteIssue(ctx context.Context, q *db.Queries, issueID int64, actorID int64, ifUpdatedAt []time.Time) error {
+	issue, err := q.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := q.DeleteIssue(ctx, db.DeleteIssueParams{
+		ID:          issueID,
+		IfUpdatedAt: ifUpdatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete issue: %w", err)
+	}
+	if deleted == 0 {
+		return sql.ErrNoRows
+	}
+
+	// Nobody can reach a deleted issue's timer, and a running one would block its user from timing anything else
+	if err := q.StopIssueTimeEntries(ctx, issueID); err != nil {
+		return fmt.Errorf("failed to stop running timers: %w", err)
+	}
+
+	return recordActivity(ctx, q, issueID, actorID, schemas.IssueActivityDeleted, "", null.StringFrom(issue.Name), null.String{})
+}
+
+// SetArchived archives or unarchives the issue and records the change in its activity when the state changes.
+// An unarchived issue counts towards its column's WIP limit again, so unarchiving checks the limit.
+// Returns sql.ErrNoRows when the issue does not exist and ErrWIPLimitExceeded when the column is at its
+// hard WIP limit.
+func (s *IssueService) SetArchived(ctx context.Context, issueID int64, actorID int64, archived bool) (*schemas.IssueWithWIPWarning, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	issue, err := setIssueArchived(ctx, s.queries.WithTx(tx), issueID, actorID, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return issue, nil
+}
+
+// setIssueArchived does the work of SetArchived with the queries of the caller's transaction
+func setIssueArchived(ctx context.Context, q *db.Queries, issueID int64, actorID int64, archived bool) (*schemas.IssueWithWIPWarning, error) {
+	before, err := q.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue db.Issue
+	var warning *schemas.WIPLimitWarning
+	action := schemas.IssueActivityArchived
+	if archived {
+		issue, err = q.ArchiveIssue(ctx, issueID)
+	} else {
+		if before.ArchivedAt.Valid {
+			warning, err = checkWIPLimit(ctx, q, before.ColumnID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		issue, err = q.UnarchiveIssue(ctx, issueID)
+		action = schemas.IssueActivityUnarchived
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to set issue archived state: %w", err)
+	}
+
+	if before.ArchivedAt.Valid != archived {
+		err = recordActivity(ctx, q, issueID, actorID, action, "", null.String{}, null.String{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &schemas.IssueWithWIPWarning{Issue: issue, WIPWarning: warning}, nil
+}
+
+// moveBounds resolves the ranks the moved issue must sit between; a NULL bound is open-ended.
+// Issues with equal ranks are ordered by ID; when the bounds tie the column is renumbered to split them.
+func moveBounds(ctx context.Context, q *db.Queries, issueID int64, input schemas.MoveIssueInput) (sql.NullString, sql.NullString, error) {
+	lower, upper, err := neighbourBounds(ctx, q, issueID, input)
+	if err != nil {
+		return lower, upper, err
+	}
+
+	if lower.Valid && upper.Valid && !rankLess(lower.String, upper.String) && !rankLess(upper.String, lower.String) {
+		if err := q.RenumberColumnRanks(ctx, input.ColumnID); err != nil {
+			return lower, upper, fmt.Errorf("failed to renumber column ranks: %w", err)
+		}
+		if lower, upper, err = neighbourBounds(ctx, q, issueID, input); err != nil {
+			return lower, upper, err
+		}
+	}
+
+	if lower.Valid && upper.Valid && !rankLess(lower.String, upper.String) {
+		return lower, upper, ErrInvalidMoveNeighbours
+	}
+
+	return lower, upper, nil
+}
+
+// neighbourBounds looks up the ranks of the issues the moved issue goes between. Deleted issues are skipped;
+// archived issues keep their place in the column so unarchiving puts them back where they were.
+func neighbourBounds(ctx context.Context, q *db.Queries, issueID int64, input schemas.MoveIssueInput) (sql.NullString, sql.NullString, error) {
+	var lower, upper sql.NullString
+
+	if input.BeforeID.Valid {
+		rank, err := neighbourRank(ctx, q, input.BeforeID.Int64, input.ColumnID)
+		if err != nil {
+			return lower, upper, err
+		}
+		lower = sql.NullString{String: rank, Valid: true}
+	}
+	if input.AfterID.Valid {
+		rank, err := neighbourRank(ctx, q, input.AfterID.Int64, input.ColumnID)
+		if err != nil {
+			return lower, upper, err
+		}
+		upper = sql.NullString{String: rank, Valid: true}
+	}
+
+	var err error
+	switch {
+	case lower.Valid && upper.Valid:
+		// Both bounds are given
+	case lower.Valid:
+		upper, err = optionalRank(q.GetNextIssueRank(ctx, db.GetNextIssueRankParams{
+			ColumnID:    input.ColumnID,
+			IssueID:     issueID,
+			Rank:        lower.String,
+			NeighbourID: input.BeforeID.Int64,
+		}))
+	case upper.Valid:
+		lower, err = optionalRank(q.GetPreviousIssueRank(ctx, db.GetPreviousIssueRankParams{
+			ColumnID:    input.ColumnID,
+			IssueID:     issueID,
+			Rank:        upper.String,
+			NeighbourID: input.AfterID.Int64,
+		}))
+	default:
+		lower, err = optionalRank(q.GetLastIssueRank(ctx, db.GetLastIssueRankParams{
+			ColumnID: input.ColumnID,
+			IssueID:  issueID,
+		}))
+	}
+	if err != nil {
+		return lower, upper, fmt.Errorf("failed to get neighbour rank: %w", err)
+	}
+
+	return lower, upper, nil
+}
+
+func neighbourRank(ctx context.Context, q *db.Queries, neighbourID int64, columnID int64) (string, error) {
+	rank, err := q.GetIssueRankInColumn(ctx, db.GetIssueRankInColumnParams{
+		ID:       neighbourID,
+		ColumnID: columnID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrMoveNeighbourNotInColumn
+		}
+		return "", fmt.Errorf("failed to get neighbour rank: %w", err)
+	}
+	return rank, nil
+}
+
+// optionalRank turns a missing row into an open bound
+func optionalRank(rank string, err error) (sql.NullString, error) {
+	if err == sql.ErrNoRows {
+		return sql.NullString{}, nil
+	}
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: rank, Valid: true}, nil
+}
+
+// rankLess compares two numeric ranks exactly
+func rankLess(a, b string) bool {
+	ra, okA := new(big.Rat).SetString(a)
+	rb, okB := new(big.Rat).SetString(b)
+	return okA && okB && ra.Cmp(rb) < 0
+}
+
+// checkColumnInIssueProject verifies that the target column belongs to the same project as the issue's column
+func checkColumnInIssueProject(ctx context.Context, q *db.Queries, issueColumnID int64, targetColumnID int64) error {
+	column, err := q.GetProjectStatusColumnByID(ctx, issueColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column: %w", err)
+	}
+
+	target, err := q.GetProjectStatusColumnByID(ctx, targetColumnID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrColumnNotInProject
+		}
+		return fmt.Errorf("failed to get target column: %w", err)
+	}
+
+	if column.ProjectID != target.ProjectID {
+		return ErrColumnNotInProject
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches "@alice" and "@alice@example.com" when the @ starts a word
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// parseMentions returns the lower-cased handles mentioned in the text, without duplicates, in order of appearance
+func parseMentions(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+	return handles
+}
+
+// resolveMentions maps the handles mentioned in the text to members of the issue's team.
+// A handle is a member's email address or the part of it before the @. Handles matching no member,
+// or several members, are ignored.
+func resolveMentions(ctx context.Context, q *db.Queries, issueID int64, text string) ([]int64, error) {
+	handles := parseMentions(text)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+
+	teamID, err := q.GetTeamIDByIssue(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue team: %w", err)
+	}
+
+	members, err := q.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	byHandle := make(map[string][]int64)
+	for _, member := range members {
+		email := strings.ToLower(member.Email)
+		byHandle[email] = append(byHandle[email], member.ID)
+		if local, _, ok := strings.Cut(email, "@"); ok {
+			byHandle[local] = append(byHandle[local], member.ID)
+		}
+	}
+
+	var userIDs []int64
+	seen := make(map[int64]bool)
+	for _, handle := range handles {
+		matches := byHandle[handle]
+		if len(matches) != 1 || seen[matches[0]] {
+			continue
+		}
+		seen[matches[0]] = true
+		userIDs = append(userIDs, matches[0])
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrMilestoneNotFound = errors.New("milestone not found in this project")
+	ErrMilestoneTaken    = errors.New("a milestone with this name already exists")
+)
+
+// markdownEscaper backslash-escapes the characters that would turn plain text into Markdown markup
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "!", `\!`, "|", `\|`, "~", `\~`, "&", `\&`,
+)
+
+type MilestoneService struct {
+	queries      *db.Queries
+	db           *sql.DB
+	labelService *IssueLabelService
+}
+
+func NewMilestoneService(queries *db.Queries, database *sql.DB) *MilestoneService {
+	return &MilestoneService{
+		queries:      queries,
+		db:           database,
+		labelService: NewIssueLabelService(queries),
+	}
+}
+
+// List returns the project's milestones by target date, undated ones last, with their completion
+func (s *MilestoneService) List(ctx context.Context, projectID int64) ([]schemas.MilestoneSummary, error) {
+	milestones, err := s.queries.GetMilestonesByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestones: %w", err)
+	}
+
+	ids := make([]int64, 0, len(milestones))
+	for _, milestone := range milestones {
+		ids = append(ids, milestone.ID)
+	}
+	counts, err := milestoneIssueCounts(ctx, s.queries, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]schemas.MilestoneSummary, 0, len(milestones))
+	for _, milestone := range milestones {
+		summaries = append(summaries, milestoneSummaryOf(milestone, counts[milestone.ID]))
+	}
+	return summaries, nil
+}
+
+// Get returns one of the project's milestones with its completion
+func (s *MilestoneService) Get(ctx context.Context, projectID int64, milestoneID int64) (*schemas.MilestoneSummary, error) {
+	milestone, err := s.get(ctx, s.queries, projectID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+	return s.summary(ctx, milestone)
+}
+
+// Create adds a milestone to the project. Names are unique within a project.
+func (s *MilestoneService) Create(ctx context.Context, projectID int64, input schemas.MilestoneInput) (*schemas.MilestoneSummary, error) {
+	milestone, err := s.queries.CreateMilestone(ctx, db.CreateMilestoneParams{
+		ProjectID:   projectID,
+		Name:        input.Name,
+		Description: null.NewString(input.Description, input.Description != ""),
+		TargetDate:  input.TargetDate,
+	})
+	if err != nil {
+		if isMilestoneNameTaken(err) {
+			return nil, ErrMilestoneTaken
+		}
+		return nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+	return &schemas.MilestoneSummary{Milestone: milestone}, nil
+}
+
+// Update replaces the milestone's name, description and target date
+func (s *MilestoneService) Update(ctx context.Context, projectID int64, milestoneID int64, input schemas.MilestoneInput) (*schemas.MilestoneSummary, error) {
+	milestone, err := s.queries.UpdateMilestone(ctx, db.UpdateMilestoneParams{
+		Name:        input.Name,
+		Description: null.NewString(input.Description, input.Description != ""),
+		TargetDate:  input.TargetDate,
+		ID:          milestoneID,
+		ProjectID:   projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrMilestoneNotFound
+		}
+		if isMilestoneNameTaken(err) {
+			return nil, ErrMilestoneTaken
+		}
+		return nil, fmt.Errorf("failed to update milestone: %w", err)
+	}
+	return s.summary(ctx, milestone)
+}
+
+// Delete removes the milestone; its issues are kept without a milestone
+func (s *MilestoneService) Delete(ctx context.Context, projectID int64, milestoneID int64) error {
+	deleted, err := s.queries.DeleteMilestone(ctx, db.DeleteMilestoneParams{
+		ID:        milestoneID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete milestone: %w", err)
+	}
+	if deleted == 0 {
+		return ErrMilestoneNotFound
+	}
+	return nil
+}
+
+// SetIssueMilestone assigns the issue to a milestone of its project, or removes it from its milestone when
+// milestoneID is null, and records the change in the issue's activity. Returns sql.ErrNoRows when the issue
+// does not exist.
+func (s *MilestoneService) SetIssueMilestone(ctx context.Context, issueID int64, actorID int64, milestoneID null.Int) (*db.Issue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if milestoneID.Valid {
+		column, err := qtx.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get column: %w", err)
+		}
+		if _, err := s.get(ctx, qtx, int64(column.ProjectID), milestoneID.Int64); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := qtx.SetIssueMilestone(ctx, db.SetIssueMilestoneParams{
+		ID:          issueID,
+		MilestoneID: milestoneID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set milestone: %w", err)
+	}
+
+	if err := recordChanges(ctx, qtx, actorID, issue, updated); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// ReleaseNotes lists the milestone's issues in done columns grouped by label name.
+// Issues without labels are grouped last.
+func (s *MilestoneService) ReleaseNotes(ctx context.Context, projectID int64, milestoneID int64) (*schemas.ReleaseNotes, error) {
+	milestone, err := s.Get(ctx, projectID, milestoneID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.queries.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	issues, err := s.queries.GetMilestoneDoneIssues(ctx, null.IntFrom(milestoneID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed issues: %w", err)
+	}
+
+	issueIDs := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	labels, err := s.labelService.GetForIssues(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byLabel := make(map[int64]*schemas.ReleaseNotesGroup)
+	unlabeled := schemas.ReleaseNotesGroup{Issues: []schemas.ReleaseNoteIssue{}}
+	for _, issue := range issues {
+		note := schemas.ReleaseNoteIssue{
+			ID:       issue.ID,
+			Key:      schemas.IssueKey(project.KeyPrefix, issue.Number),
+			Name:     issue.Name,
+			Priority: issue.Priority,
+		}
+		if len(labels[issue.ID]) == 0 {
+			unlabeled.Issues = append(unlabeled.Issues, note)
+			continue
+		}
+		for _, label := range labels[issue.ID] {
+			group, ok := byLabel[label.ID]
+			if !ok {
+				group = &schemas.ReleaseNotesGroup{Label: &label, Issues: []schemas.ReleaseNoteIssue{}}
+				byLabel[label.ID] = group
+			}
+			group.Issues = append(group.Issues, note)
+		}
+	}
+
+	groups := make([]schemas.ReleaseNotesGroup, 0, len(byLabel)+1)
+	for _, group := range byLabel {
+		groups = append(groups, *group)
+	}
+	slices.SortFunc(groups, func(a, b schemas.ReleaseNotesGroup) int {
+		return cmp.Or(strings.Compare(a.Label.Name, b.Label.Name), cmp.Compare(a.Label.ID, b.Label.ID))
+	})
+	if len(unlabeled.Issues) > 0 {
+		groups = append(groups, unlabeled)
+	}
+
+	return &schemas.ReleaseNotes{
+		Milestone: *milestone,
+		Groups:    groups,
+	}, nil
+}
+
+// ReleaseNotesMarkdown renders release notes as a Markdown document with a section per label.
+// Names are escaped so they render as written; the milestone description is Markdown already.
+func ReleaseNotesMarkdown(notes *schemas.ReleaseNotes) string {
+	var b strings.Builder
+	milestone := notes.Milestone
+
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(milestone.Name))
+	if milestone.TargetDate.Valid {
+		fmt.Fprintf(&b, "Target date: %s\n\n", milestone.TargetDate.Time.Format(time.DateOnly))
+	}
+	if milestone.Description.Valid {
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(milestone.Description.String))
+	}
+	fmt.Fprintf(&b, "%d of %d issues done (%d%%)\n", milestone.DoneIssueCount, milestone.IssueCount, milestone.PercentComplete)
+
+	if len(notes.Groups) == 0 {
+		b.WriteString("\nNo issues have been completed yet.\n")
+		return b.String()
+	}
+
+	for _, group := range notes.Groups {
+		heading := "Other changes"
+		if group.Label != nil {
+			heading = escapeMarkdown(group.Label.Name)
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", heading)
+		for _, issue := range group.Issues {
+			fmt.Fprintf(&b, "- %s %s\n", issue.Key, escapeMarkdown(issue.Name))
+		}
+	}
+	return b.String()
+}
+
+// escapeMarkdown turns text into a single line of Markdown that renders as the text itself
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(strings.Join(strings.Fields(text), " "))
+}
+
+// get loads a milestone of the project, returning ErrMilestoneNotFound when it belongs to another project
+func (s *MilestoneService) get(ctx context.Context, q *db.Queries, projectID int64, milestoneID int64) (db.Milestone, error) {
+	milestone, err := q.GetMilestoneByID(ctx, db.GetMilestoneByIDParams{
+		ID:        milestoneID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Milestone{}, ErrMilestoneNotFound
+		}
+		return db.Milestone{}, fmt.Errorf("failed to get milestone: %w", err)
+	}
+	return milestone, nil
+}
+
+func (s *MilestoneService) summary(ctx context.Context, milestone db.Milestone) (*schemas.MilestoneSummary, error) {
+	counts, err := milestoneIssueCounts(ctx, s.queries, []int64{milestone.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := milestoneSummaryOf(milestone, counts[milestone.ID])
+	return &summary, nil
+}
+
+// milestoneIssueCounts counts the issues of several milestones in one query, keyed by milestone ID.
+// Milestones without issues are absent from the map.
+func milestoneIssueCounts(ctx context.Context, q *db.Queries, milestoneIDs []int64) (map[int64]schemas.IssueProgress, error) {
+	byMilestone := make(map[int64]schemas.IssueProgress)
+	if len(milestoneIDs) == 0 {
+		return byMilestone, nil
+	}
+
+	rows, err := q.GetMilestoneIssueCounts(ctx, milestoneIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count milestone issues: %w", err)
+	}
+	for _, row := range rows {
+		byMilestone[row.MilestoneID.Int64] = schemas.IssueProgress{
+			Done:  row.Done,
+			Total: row.Total,
+		}
+	}
+	return byMilestone, nil
+}
+
+func milestoneSummaryOf(milestone db.Milestone, progress schemas.IssueProgress) schemas.MilestoneSummary {
+	summary := schemas.MilestoneSummary{
+		Milestone:      milestone,
+		IssueCount:     progress.Total,
+		DoneIssueCount: progress.Done,
+	}
+	if progress.Total > 0 {
+		summary.PercentComplete = progress.Done * 100 / progress.Total
+	}
+	return summary
+}
+
+// isMilestoneNameTaken reports whether err is a violation of the unique milestone name per project constraint
+func isMilestoneNameTaken(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == db.PgErrUniqueViolation && pqErr.Constraint == "milestones_project_id_name_key"
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/llm"
+	"acacia/packages/schemas"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultProjectReportWindow = 7 * 24 * time.Hour
+	maxProjectReportWindow     = 90 * 24 * time.Hour
+)
+
+var ErrInvalidReportWindow = errors.New("invalid report window")
+
+const projectReportSystemPrompt = `You are a project manager writing a status report for a Kanban project.
+You receive the board columns in workflow order, every issue with its column, and the issues created or updated during the reporting window.
+Write concise markdown for each section:
+- summary: two or three sentences on overall progress during the window
+- done: work that reached a finished column
+- in_progress: work that is actively moving
+- blocked: work that looks stuck or waiting, and why
+- risks: schedule or scope risks worth raising
+Only use the data provided. Write "None." for sections with nothing to report.`
+
+// projectReportSchema is the structured output the model must return
+var projectReportSchema = llm.StructuredOutputSchema{
+	Name:        "project_status_report",
+	Description: "Written status report for a project",
+	Schema: map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"summary":     map[string]any{"type": "string"},
+			"done":        map[string]any{"type": "string"},
+			"in_progress": map[string]any{"type": "string"},
+			"blocked":     map[string]any{"type": "string"},
+			"risks":       map[string]any{"type": "string"},
+		},
+		"required":             []string{"summary", "done", "in_progress", "blocked", "risks"},
+		"additionalProperties": false,
+	},
+}
+
+type projectReportResponse struct {
+	Summary    string `json:"summary"`
+	Done       string `json:"done"`
+	InProgress string `json:"in_progress"`
+	Blocked    string `json:"blocked"`
+	Risks      string `json:"risks"`
+}
+
+// ProjectReportService writes project status reports with the team's LLM and stores them
+type ProjectReportService struct {
+	queries   *db.Queries
+	projects  *ProjectService
+	providers *TeamProviderResolver
+	logger    *logrus.Logger
+}
+
+func NewProjectReportService(queries *db.Queries, providers *TeamProviderResolver, logger *logrus.Logger) *ProjectReportService {
+	return &ProjectReportService{
+		queries:   queries,
+		projects:  NewProjectService(queries),
+		providers: providers,
+		logger:    logger,
+	}
+}
+
+// Generate summarizes the project's board and the changes within the window and stores the report
+func (s *ProjectReportService) Generate(ctx context.Context, projectID int64, userID int64, input schemas.GenerateProjectReportInput) (*db.ProjectReport, error) {
+	periodEnd := time.Now().UTC()
+	if input.Until.Valid {
+		periodEnd = input.Until.Time.UTC()
+	}
+	periodStart := periodEnd.Add(-defaultProjectReportWindow)
+	if input.Since.Valid {
+		periodStart = input.Since.Time.UTC()
+	}
+	if !periodStart.Before(periodEnd) || periodEnd.Sub(periodStart) > maxProjectReportWindow {
+		return nil, ErrInvalidReportWindow
+	}
+
+	details, err := s.projects.GetDetails(ctx, projectID, schemas.ProjectIssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := s.providers.Resolve(ctx, details.TeamID, input.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	completer, ok := provider.(llm.StructuredCompleter)
+	if !ok {
+		return nil, llm.ErrStructuredOutputNotSupported
+	}
+
+	messages := []llm.Message{
+		{Role: "system", Content: projectReportSystemPrompt},
+		{Role: "user", Content: buildProjectReportPrompt(details, periodStart, periodEnd)},
+	}
+
+	raw, err := completer.CompleteStructured(ctx, messages, input.Model, projectReportSchema)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to generate project report")
+		return nil, err
+	}
+
+	var parsed projectReportResponse
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		s.logger.WithError(err).Warn("Failed to parse project report response")
+		return nil, ErrInvalidModelResponse
+	}
+
+	report, err := s.queries.CreateProjectReport(ctx, db.CreateProjectReportParams{
+		ProjectID:   projectID,
+		CreatedBy:   null.IntFrom(userID),
+		Provider:    input.Provider,
+		Model:       input.Model,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		Summary:     parsed.Summary,
+		Done:        parsed.Done,
+		InProgress:  parsed.InProgress,
+		Blocked:     parsed.Blocked,
+		Risks:       parsed.Risks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save project report: %w", err)
+	}
+
+	return &report, nil
+}
+
+// buildProjectReportPrompt renders the board and the window's changes as plain text for the model
+func buildProjectReportPrompt(details *schemas.GetProjectDetailsResponse, periodStart, periodEnd time.Time) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Project: %s\n", details.Name)
+	fmt.Fprintf(&b, "Reporting window: %s to %s\n\n", periodStart.Format(time.RFC3339), periodEnd.Format(time.RFC3339))
+
+	issuesByColumn := make(map[int64][]schemas.IssueWithLabels, len(details.Columns))
+	for _, issue := range details.Issues {
+		issuesByColumn[issue.ColumnID] = append(issuesByColumn[issue.ColumnID], issue)
+	}
+
+	b.WriteString("Board:\n")
+	for _, column := range details.Columns {
+		fmt.Fprintf(&b, "## %s (%d issues)\n", column.Name, len(issuesByColumn[column.ID]))
+		for _, issue := range issuesByColumn[column.ID] {
+			fmt.Fprintf(&b, "- #%d %s (last updated %s)\n", issue.ID, issue.Name, issue.UpdatedAt.Format(time.RFC3339))
+		}
+	}
+
+	b.WriteString("\nChanges during the window:\n")
+	changes := 0
+	for _, issue := range details.Issues {
+		switch {
+		case inWindow(issue.CreatedAt, periodStart, periodEnd):
+			fmt.Fprintf(&b, "- created #%d %s\n", issue.ID, issue.Name)
+		case inWindow(issue.UpdatedAt, periodStart, periodEnd):
+			fmt.Fprintf(&b, "- updated #%d %s\n", issue.ID, issue.Name)
+		default:
+			continue
+		}
+		if issue.Description.Valid && issue.Description.String != "" {
+			fmt.Fprintf(&b, "  %s\n", truncate(issue.Description.String, 500))
+		}
+		changes++
+	}
+	if changes == 0 {
+		b.WriteString("No issues were created or updated.\n")
+	}
+
+	return b.String()
+}
+
+func inWindow(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
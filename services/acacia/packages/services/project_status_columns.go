@@ -2,13 +2,22 @@ package services
 
 import (
 	"acacia/packages/db"
+	"cmp"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/guregu/null"
 )
 
-var ErrCannotDeleteLastColumn = errors.New("cannot delete the last column in a project")
+var (
+	ErrCannotDeleteLastColumn = errors.New("cannot delete the last column in a project")
+	ErrInvalidColumnPosition  = errors.New("position is outside the project's columns")
+	ErrInvalidTransition      = errors.New("transition targets must be other columns of the same project")
+)
 
 type ProjectStatusColumnService struct {
 	queries *db.Queries
@@ -22,7 +31,11 @@ func NewProjectStatusColumnService(queries *db.Queries, database *sql.DB) *Proje
 	}
 }
 
-func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx context.Context, columnID int64) (*db.ProjectStatusColumn, error) {
+// DeleteProjectStatusColumnWithReorder moves the column to the team's trash and its issues to the next column,
+// recording each move in the issue's activity with the actor. The deleted column keeps its position so a
+// restore can put it back in place. With ifUpdatedAt given, a column changed since is left alone and sql.ErrNoRows
+// is returned.
+func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx context.Context, columnID int64, actorID int64, ifUpdatedAt []time.Time) (*db.ProjectStatusColumn, error) {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to begin transaction: %w", err)
@@ -37,6 +50,11 @@ func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx co
 		return nil, err
 	}
 
+	// Serialise with concurrent moves and deletes in the same project
+	if _, err := qtx.LockProjectStatusColumnsByProjectID(ctx, columnInfo.ProjectID); err != nil {
+		return nil, fmt.Errorf("failed to lock project columns: %w", err)
+	}
+
 	// Check if this is the last column in the project
 	columnCount, err := qtx.GetProjectStatusColumnCountByProjectID(ctx, columnInfo.ProjectID)
 	if err != nil {
@@ -56,6 +74,16 @@ func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx co
 		return nil, fmt.Errorf("failed to get next column: %w", err)
 	}
 
+	err = qtx.CreateColumnReassignmentActivity(ctx, db.CreateColumnReassignmentActivityParams{
+		ActorID:      null.NewInt(actorID, actorID != 0),
+		TargetColumn: nextColumnID,
+		SourceColumn: columnID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record issue moves: %w", err)
+	}
+
+	// The issues keep their order below the next column's issues, ranked while the columns are locked
 	err = qtx.ReassignAllIssuesFromColumn(ctx, db.ReassignAllIssuesFromColumnParams{
 		SourceColumn: columnID,
 		TargetColumn: nextColumnID,
@@ -73,8 +101,11 @@ func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx co
 		return nil, fmt.Errorf("failed to shift columns: %w", err)
 	}
 
-	// Delete the column
-	deletedColumn, err := qtx.DeleteProjectStatusColumn(ctx, columnID)
+	// Move the column to the trash
+	deletedColumn, err := qtx.DeleteProjectStatusColumn(ctx, db.DeleteProjectStatusColumnParams{
+		ID:          columnID,
+		IfUpdatedAt: ifUpdatedAt,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to delete column: %w", err)
 	}
@@ -85,3 +116,145 @@ func (s *ProjectStatusColumnService) DeleteProjectStatusColumnWithReorder(ctx co
 
 	return &deletedColumn, nil
 }
+
+// MoveProjectStatusColumn moves the column to the given position and renumbers the project's
+// columns from 0 in one transaction. The project's columns are locked first so concurrent
+// moves and deletes apply one after another.
+func (s *ProjectStatusColumnService) MoveProjectStatusColumn(ctx context.Context, columnID int64, position int16) ([]db.ProjectStatusColumn, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	columnInfo, err := qtx.GetProjectStatusColumnByID(ctx, columnID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows are locked in id order so concurrent transactions cannot deadlock
+	columns, err := qtx.LockProjectStatusColumnsByProjectID(ctx, columnInfo.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock project columns: %w", err)
+	}
+
+	slices.SortFunc(columns, func(a, b db.ProjectStatusColumn) int {
+		return cmp.Or(cmp.Compare(a.PositionIndex, b.PositionIndex), cmp.Compare(a.ID, b.ID))
+	})
+
+	// The column may have been deleted while waiting for the lock
+	current := slices.IndexFunc(columns, func(c db.ProjectStatusColumn) bool { return c.ID == columnID })
+	if current < 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	if int(position) >= len(columns) {
+		return nil, ErrInvalidColumnPosition
+	}
+
+	moved := columns[current]
+	columns = slices.Delete(columns, current, current+1)
+	columns = slices.Insert(columns, int(position), moved)
+
+	for i := range columns {
+		if columns[i].PositionIndex == int16(i) {
+			continue
+		}
+		err := qtx.SetProjectStatusColumnPosition(ctx, db.SetProjectStatusColumnPositionParams{
+			ID:            columns[i].ID,
+			PositionIndex: int16(i),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to update column position: %w", err)
+		}
+	}
+
+	reordered, err := qtx.GetProjectStatusColumnsByProjectID(ctx, columnInfo.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project columns: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return reordered, nil
+}
+
+// GetTransitions returns the columns issues may move to from the column, in board order.
+// An empty list means issues can move to any column.
+func (s *ProjectStatusColumnService) GetTransitions(ctx context.Context, columnID int64) ([]db.ProjectStatusColumn, error) {
+	if _, err := s.queries.GetProjectStatusColumnByID(ctx, columnID); err != nil {
+		return nil, err
+	}
+
+	columns, err := s.queries.GetAllowedTransitionColumns(ctx, columnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column transitions: %w", err)
+	}
+	if columns == nil {
+		columns = []db.ProjectStatusColumn{}
+	}
+	return columns, nil
+}
+
+// SetTransitions replaces the columns issues may move to from the column.
+// Every target must be another column of the same project.
+func (s *ProjectStatusColumnService) SetTransitions(ctx context.Context, columnID int64, toColumnIDs []int64) ([]db.ProjectStatusColumn, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	column, err := qtx.GetProjectStatusColumnByID(ctx, columnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := qtx.DeleteColumnTransitions(ctx, columnID); err != nil {
+		return nil, fmt.Errorf("failed to clear column transitions: %w", err)
+	}
+
+	for _, toColumnID := range toColumnIDs {
+		if toColumnID == columnID {
+			return nil, ErrInvalidTransition
+		}
+		target, err := qtx.GetProjectStatusColumnByID(ctx, toColumnID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, ErrInvalidTransition
+			}
+			return nil, fmt.Errorf("failed to get target column: %w", err)
+		}
+		if target.ProjectID != column.ProjectID {
+			return nil, ErrInvalidTransition
+		}
+
+		err = qtx.AddColumnTransition(ctx, db.AddColumnTransitionParams{
+			FromColumnID: columnID,
+			ToColumnID:   toColumnID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add column transition: %w", err)
+		}
+	}
+
+	columns, err := qtx.GetAllowedTransitionColumns(ctx, columnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column transitions: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if columns == nil {
+		columns = []db.ProjectStatusColumn{}
+	}
+	return columns, nil
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidKeyPrefix = errors.New("key prefix must be 2 to 10 upper-case letters and digits starting with a letter")
+	ErrKeyPrefixTaken   = errors.New("key prefix is already used by another project in the team")
+	// ErrKeyPrefixImmutable is returned when an update asks for a different key prefix. Issue keys end
+	// up in commit messages and links elsewhere, which a new prefix would break.
+	ErrKeyPrefixImmutable = errors.New("key prefix cannot be changed")
+)
+
+// keyPrefixAttempts bounds how many numbered variants of a derived key prefix Create tries
+const keyPrefixAttempts = 100
+
+type ProjectService struct {
+	queries      *db.Queries
+	labelService *IssueLabelService
+}
+
+func NewProjectService(queries *db.Queries) *ProjectService {
+	return &ProjectService{
+		queries:      queries,
+		labelService: NewIssueLabelService(queries),
+	}
+}
+
+// Create creates a project. Without an explicit key prefix one is derived from the name and numbered
+// (ACA, ACA2, ACA3, ...) until no other project of the team uses it.
+func (s *ProjectService) Create(ctx context.Context, input schemas.CreateProjectInput) (db.Project, error) {
+	if input.KeyPrefix != "" {
+		if !schemas.IsValidKeyPrefix(input.KeyPrefix) {
+			return db.Project{}, ErrInvalidKeyPrefix
+		}
+		project, err := s.queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      input.Name,
+			TeamID:    input.TeamID,
+			KeyPrefix: input.KeyPrefix,
+		})
+		if isKeyPrefixTaken(err) {
+			return db.Project{}, ErrKeyPrefixTaken
+		}
+		return project, err
+	}
+
+	base := deriveKeyPrefix(input.Name)
+	for attempt := 1; attempt <= keyPrefixAttempts; attempt++ {
+		prefix := base
+		if attempt > 1 {
+			prefix = fmt.Sprintf("%s%d", base, attempt)
+		}
+		project, err := s.queries.CreateProject(ctx, db.CreateProjectParams{
+			Name:      input.Name,
+			TeamID:    input.TeamID,
+			KeyPrefix: prefix,
+		})
+		if !isKeyPrefixTaken(err) {
+			return project, err
+		}
+	}
+	return db.Project{}, ErrKeyPrefixTaken
+}
+
+// Update renames the project and changes its WIP limit mode when given. The key prefix is fixed at
+// creation; giving a different one returns ErrKeyPrefixImmutable.
+// Returns sql.ErrNoRows when the project does not exist or, with ifUpdatedAt given, was changed since.
+func (s *ProjectService) Update(ctx context.Context, projectID int64, input schemas.UpdateProjectInput, ifUpdatedAt []time.Time) (db.Project, error) {
+	if input.KeyPrefix != "" {
+		current, err := s.queries.GetProjectByID(ctx, projectID)
+		if err != nil {
+			return db.Project{}, err
+		}
+		if input.KeyPrefix != current.KeyPrefix {
+			return db.Project{}, ErrKeyPrefixImmutable
+		}
+	}
+
+	return s.queries.UpdateProject(ctx, db.UpdateProjectParams{
+		ID:           projectID,
+		Name:         input.Name,
+		WipLimitMode: null.NewString(input.WIPLimitMode, input.WIPLimitMode != ""),
+		IfUpdatedAt:  ifUpdatedAt,
+	})
+}
+
+// GetDetails loads a project together with its columns, their WIP usage and workflow rules, its custom fields
+// and the issues matching the filter.
+// Returns sql.ErrNoRows when the project does not exist and ErrCustomFieldNotFound or a CustomFieldError
+// when the custom field filter is not valid for the project.
+func (s *ProjectService) GetDetails(ctx context.Context, projectID int64, filter schemas.ProjectIssueFilter) (*schemas.GetProjectDetailsResponse, error) {
+	project, err := s.queries.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	projectColumns, err := s.queries.GetProjectStatusColumnsByProjectID(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project columns: %w", err)
+	}
+
+	params := db.GetProjectIssuesParams{
+		ProjectID:       int32(projectID),
+		Priority:        null.NewString(filter.Priority, filter.Priority != ""),
+		LabelID:         filter.LabelID,
+		DueAfter:        filter.DueAfter,
+		DueBefore:       filter.DueBefore,
+		IncludeArchived: filter.IncludeArchived,
+	}
+	if filter.CustomFieldID.Valid {
+		if err := customFieldFilterParams(ctx, s.queries, projectID, filter, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	projectIssues, err := s.queries.GetProjectIssues(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project issues: %w", err)
+	}
+
+	issueIDs := make([]int64, 0, len(projectIssues))
+	for _, issue := range projectIssues {
+		issueIDs = append(issueIDs, issue.ID)
+	}
+	labels, err := s.labelService.GetForIssues(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := subIssueProgress(ctx, s.queries, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := blockedIssues(ctx, s.queries, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	customFields, err := customFieldValues(ctx, s.queries, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]schemas.IssueWithLabels, 0, len(projectIssues))
+	for _, issue := range projectIssues {
+		issueLabels := labels[issue.ID]
+		if issueLabels == nil {
+			issueLabels = []db.Label{}
+		}
+		issueCustomFields := customFields[issue.ID]
+		if issueCustomFields == nil {
+			issueCustomFields = []schemas.CustomFieldValue{}
+		}
+		issueWithLabels := schemas.IssueWithLabels{
+			Issue:        issue,
+			Key:          schemas.IssueKey(project.KeyPrefix, issue.Number),
+			Labels:       issueLabels,
+			CustomFields: issueCustomFields,
+			Blocked:      blocked[issue.ID],
+		}
+		if p, ok := progress[issue.ID]; ok {
+			issueWithLabels.Progress = &p
+		}
+		issues = append(issues, issueWithLabels)
+	}
+
+	columns, err := columnDetails(ctx, s.queries, projectID, projectColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.queries.GetCustomFieldsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom fields: %w", err)
+	}
+	if fields == nil {
+		fields = []db.CustomField{}
+	}
+
+	sprints, err := projectSprints(ctx, s.queries, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.GetProjectDetailsResponse{
+		Project:      project,
+		Columns:      columns,
+		CustomFields: fields,
+		Sprints:      sprints,
+		Issues:       issues,
+	}, nil
+}
+
+// deriveKeyPrefix builds a key prefix from the first three letters and digits of a project name,
+// skipping leading digits. Names with fewer than two usable characters get "PRJ".
+func deriveKeyPrefix(name string) string {
+	var prefix strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if prefix.Len() == 3 {
+			break
+		}
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if isLetter || (isDigit && prefix.Len() > 0) {
+			prefix.WriteRune(r)
+		}
+	}
+	if prefix.Len() < 2 {
+		return "PRJ"
+	}
+	return prefix.String()
+}
+
+// isKeyPrefixTaken reports whether err is a violation of the per-team unique key prefix constraint
+func isKeyPrefixTaken(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == db.PgErrUniqueViolation && pqErr.Constraint == "projects_team_id_key_prefix_key"
+}
+
+// columnDetails pairs each column with its issue count, whether it is over its WIP limit and the columns
+// issues may move to from it
+func columnDetails(ctx context.Context, q *db.Queries, projectID int64, columns []db.ProjectStatusColumn) ([]schemas.ProjectColumnDetails, error) {
+	counts, err := q.GetColumnIssueCountsByProjectID(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count column issues: %w", err)
+	}
+
+	byColumn := make(map[int64]int64, len(counts))
+	for _, count := range counts {
+		byColumn[count.ColumnID] = count.IssueCount
+	}
+
+	transitions, err := q.GetColumnTransitionsByProjectID(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get column transitions: %w", err)
+	}
+
+	allowed := make(map[int64][]int64)
+	for _, transition := range transitions {
+		allowed[transition.FromColumnID] = append(allowed[transition.FromColumnID], transition.ToColumnID)
+	}
+
+	details := make([]schemas.ProjectColumnDetails, 0, len(columns))
+	for _, column := range columns {
+		issueCount := byColumn[column.ID]
+		targets := allowed[column.ID]
+		if targets == nil {
+			targets = []int64{}
+		}
+		details = append(details, schemas.ProjectColumnDetails{
+			ProjectStatusColumn: column,
+			IssueCount:          issueCount,
+			OverWIPLimit:        column.WipLimit.Valid && issueCount > column.WipLimit.Int64,
+			AllowedTransitions:  targets,
+		})
+	}
+	return details, nil
+}
@@ -0,0 +1,316 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSprintNotFound      = errors.New("sprint not found in this project")
+	ErrSprintDates         = errors.New("a sprint cannot end before it starts")
+	ErrSprintAlreadyActive = errors.New("the project already has an active sprint")
+	ErrSprintNotPlanned    = errors.New("only a planned sprint can be started")
+	ErrSprintNotActive     = errors.New("only an active sprint can be completed")
+	ErrSprintCompleted     = errors.New("issues cannot be added to a completed sprint")
+)
+
+type SprintService struct {
+	queries *db.Queries
+	db      *sql.DB
+}
+
+func NewSprintService(queries *db.Queries, database *sql.DB) *SprintService {
+	return &SprintService{
+		queries: queries,
+		db:      database,
+	}
+}
+
+// List returns the project's sprints by start date with their issue counts
+func (s *SprintService) List(ctx context.Context, projectID int64) ([]schemas.SprintSummary, error) {
+	return projectSprints(ctx, s.queries, projectID)
+}
+
+// Create plans a sprint in the project
+func (s *SprintService) Create(ctx context.Context, projectID int64, input schemas.SprintInput) (*schemas.SprintSummary, error) {
+	if input.EndDate.Before(input.StartDate) {
+		return nil, ErrSprintDates
+	}
+
+	sprint, err := s.queries.CreateSprint(ctx, db.CreateSprintParams{
+		ProjectID: projectID,
+		Name:      input.Name,
+		Goal:      null.NewString(input.Goal, input.Goal != ""),
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sprint: %w", err)
+	}
+	return &schemas.SprintSummary{Sprint: sprint}, nil
+}
+
+// Update replaces the sprint's name, goal and dates
+func (s *SprintService) Update(ctx context.Context, projectID int64, sprintID int64, input schemas.SprintInput) (*schemas.SprintSummary, error) {
+	if input.EndDate.Before(input.StartDate) {
+		return nil, ErrSprintDates
+	}
+
+	sprint, err := s.queries.UpdateSprint(ctx, db.UpdateSprintParams{
+		Name:      input.Name,
+		Goal:      null.NewString(input.Goal, input.Goal != ""),
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		ID:        sprintID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSprintNotFound
+		}
+		return nil, fmt.Errorf("failed to update sprint: %w", err)
+	}
+	return sprintSummary(ctx, s.queries, sprint)
+}
+
+// Delete removes the sprint and returns its issues to the backlog
+func (s *SprintService) Delete(ctx context.Context, projectID int64, sprintID int64) error {
+	deleted, err := s.queries.DeleteSprint(ctx, db.DeleteSprintParams{
+		ID:        sprintID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+	if deleted == 0 {
+		return ErrSprintNotFound
+	}
+	return nil
+}
+
+// Start makes a planned sprint the project's active sprint.
+// Returns ErrSprintAlreadyActive while another sprint of the project is active.
+func (s *SprintService) Start(ctx context.Context, projectID int64, sprintID int64) (*schemas.SprintSummary, error) {
+	if _, err := s.get(ctx, s.queries, projectID, sprintID); err != nil {
+		return nil, err
+	}
+
+	sprint, err := s.queries.StartSprint(ctx, sprintID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSprintNotPlanned
+		}
+		if isActiveSprintConflict(err) {
+			return nil, ErrSprintAlreadyActive
+		}
+		return nil, fmt.Errorf("failed to start sprint: %w", err)
+	}
+	return sprintSummary(ctx, s.queries, sprint)
+}
+
+// Complete ends the active sprint. Its issues that are not in a done column move to the project's next
+// planned sprint, or to the backlog when moveTo is SprintMoveToBacklog or no sprint is planned.
+// Every moved issue records the change in its activity.
+func (s *SprintService) Complete(ctx context.Context, projectID int64, sprintID int64, actorID int64, moveTo string) (*schemas.CompletedSprint, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	if _, err := s.get(ctx, qtx, projectID, sprintID); err != nil {
+		return nil, err
+	}
+
+	sprint, err := qtx.CompleteSprint(ctx, sprintID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrSprintNotActive
+		}
+		return nil, fmt.Errorf("failed to complete sprint: %w", err)
+	}
+
+	var target null.Int
+	if moveTo != schemas.SprintMoveToBacklog {
+		next, err := qtx.GetNextPlannedSprint(ctx, db.GetNextPlannedSprintParams{
+			ProjectID: projectID,
+			SprintID:  sprintID,
+		})
+		if err != nil && err != sql.ErrNoRows {
+			return nil, fmt.Errorf("failed to get next sprint: %w", err)
+		}
+		if err == nil {
+			target = null.IntFrom(next.ID)
+		}
+	}
+
+	moved, err := qtx.MoveUnfinishedSprintIssues(ctx, db.MoveUnfinishedSprintIssuesParams{
+		TargetSprintID: target,
+		SprintID:       null.IntFrom(sprintID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to move unfinished issues: %w", err)
+	}
+
+	for _, issue := range moved {
+		before := issue
+		before.SprintID = null.IntFrom(sprintID)
+		if err := recordChanges(ctx, qtx, actorID, before, issue); err != nil {
+			return nil, err
+		}
+	}
+
+	// Counted after the move, so the summary covers the issues finished in the sprint
+	summary, err := sprintSummary(ctx, qtx, sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &schemas.CompletedSprint{
+		Sprint:          *summary,
+		MovedIssueCount: len(moved),
+		MovedToSprintID: target,
+	}, nil
+}
+
+// SetIssueSprint assigns the issue to a sprint of its project, or returns it to the backlog when sprintID
+// is null, and records the change in the issue's activity. Returns sql.ErrNoRows when the issue does not exist.
+func (s *SprintService) SetIssueSprint(ctx context.Context, issueID int64, actorID int64, sprintID null.Int) (*db.Issue, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	issue, err := qtx.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sprintID.Valid {
+		column, err := qtx.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get column: %w", err)
+		}
+		sprint, err := s.get(ctx, qtx, int64(column.ProjectID), sprintID.Int64)
+		if err != nil {
+			return nil, err
+		}
+		if sprint.Status == schemas.SprintCompleted {
+			return nil, ErrSprintCompleted
+		}
+	}
+
+	updated, err := qtx.SetIssueSprint(ctx, db.SetIssueSprintParams{
+		ID:       issueID,
+		SprintID: sprintID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set sprint: %w", err)
+	}
+
+	if err := recordChanges(ctx, qtx, actorID, issue, updated); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &updated, nil
+}
+
+// get loads a sprint of the project, returning ErrSprintNotFound when it belongs to another project
+func (s *SprintService) get(ctx context.Context, q *db.Queries, projectID int64, sprintID int64) (db.Sprint, error) {
+	sprint, err := q.GetSprintByID(ctx, db.GetSprintByIDParams{
+		ID:        sprintID,
+		ProjectID: projectID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Sprint{}, ErrSprintNotFound
+		}
+		return db.Sprint{}, fmt.Errorf("failed to get sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+// projectSprints returns the project's sprints by start date with their issue counts
+func projectSprints(ctx context.Context, q *db.Queries, projectID int64) ([]schemas.SprintSummary, error) {
+	sprints, err := q.GetSprintsByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprints: %w", err)
+	}
+
+	ids := make([]int64, 0, len(sprints))
+	for _, sprint := range sprints {
+		ids = append(ids, sprint.ID)
+	}
+	counts, err := sprintIssueCounts(ctx, q, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]schemas.SprintSummary, 0, len(sprints))
+	for _, sprint := range sprints {
+		summary := counts[sprint.ID]
+		summary.Sprint = sprint
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func sprintSummary(ctx context.Context, q *db.Queries, sprint db.Sprint) (*schemas.SprintSummary, error) {
+	counts, err := sprintIssueCounts(ctx, q, []int64{sprint.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := counts[sprint.ID]
+	summary.Sprint = sprint
+	return &summary, nil
+}
+
+// sprintIssueCounts counts the issues of several sprints in one query, keyed by sprint ID.
+// Sprints without issues are absent from the map.
+func sprintIssueCounts(ctx context.Context, q *db.Queries, sprintIDs []int64) (map[int64]schemas.SprintSummary, error) {
+	bySprint := make(map[int64]schemas.SprintSummary)
+	if len(sprintIDs) == 0 {
+		return bySprint, nil
+	}
+
+	rows, err := q.GetSprintIssueCounts(ctx, sprintIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sprint issues: %w", err)
+	}
+	for _, row := range rows {
+		bySprint[row.SprintID.Int64] = schemas.SprintSummary{
+			IssueCount:     row.Total,
+			DoneIssueCount: row.Done,
+			Points:         row.Points,
+			DonePoints:     row.DonePoints,
+		}
+	}
+	return bySprint, nil
+}
+
+// isActiveSprintConflict reports whether err is a violation of the one active sprint per project index
+func isActiveSprintConflict(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == db.PgErrUniqueViolation && pqErr.Constraint == "idx_sprints_active_sprint"
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"acacia/packages/crypto"
+	"acacia/packages/db"
+	"acacia/packages/llm"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TeamProviderResolver builds LLM provider instances using a team's stored API key
+type TeamProviderResolver struct {
+	queries           *db.Queries
+	providerRegistry  *llm.ProviderRegistry
+	encryptionService *crypto.EncryptionService
+	toolRegistry      *llm.ToolRegistry
+	logger            *logrus.Logger
+}
+
+func NewTeamProviderResolver(
+	queries *db.Queries,
+	providerRegistry *llm.ProviderRegistry,
+	encryptionService *crypto.EncryptionService,
+	toolRegistry *llm.ToolRegistry,
+	logger *logrus.Logger,
+) *TeamProviderResolver {
+	return &TeamProviderResolver{
+		queries:           queries,
+		providerRegistry:  providerRegistry,
+		encryptionService: encryptionService,
+		toolRegistry:      toolRegistry,
+		logger:            logger,
+	}
+}
+
+// Resolve looks up and decrypts the team's API key for the provider and returns a provider instance.
+// Returns ErrAPIKeyNotFound when the team has no active key for the provider.
+func (r *TeamProviderResolver) Resolve(ctx context.Context, teamID int64, providerName string) (llm.LLMResponseStreamer, error) {
+	// Get team's encrypted API key for this provider
+	apiKeyRecord, err := r.queries.GetTeamLLMAPIKeyByTeamID(ctx, db.GetTeamLLMAPIKeyByTeamIDParams{
+		TeamID:   teamID,
+		Provider: providerName,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrAPIKeyNotFound
+		}
+		r.logger.WithError(err).Error("Failed to get API key")
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	// Decrypt the API key
+	decryptedKey, err := r.encryptionService.Decrypt(apiKeyRecord.EncryptedKey)
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to decrypt API key")
+		return nil, fmt.Errorf("failed to decrypt API key: %w", err)
+	}
+
+	// Get LLM provider instance
+	provider, err := r.providerRegistry.GetProvider(providerName, decryptedKey, r.toolRegistry)
+	if err != nil {
+		r.logger.WithError(err).WithField("provider", providerName).Error("Failed to get provider")
+		return nil, fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	// Update last used timestamp for API key
+	go func() {
+		if err := r.queries.UpdateLastUsedAt(context.Background(), apiKeyRecord.ID); err != nil {
+			r.logger.WithError(err).Warn("Failed to update API key last used timestamp")
+		}
+	}()
+
+	return provider, nil
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrTimerRunning       = errors.New("a timer is already running")
+	ErrNoRunningTimer     = errors.New("no timer is running on this issue")
+	ErrTimeEntryNotFound  = errors.New("time entry not found")
+	ErrTimeEntryNotLogger = errors.New("only the user who logged time can delete it")
+)
+
+// TimerRunningError is returned when the user starts a timer while one is running, naming the issue it runs on
+type TimerRunningError struct {
+	IssueID  int64
+	IssueKey string
+}
+
+func (e *TimerRunningError) Error() string {
+	return fmt.Sprintf("a timer is already running on %s", e.IssueKey)
+}
+
+func (e *TimerRunningError) Unwrap() error {
+	return ErrTimerRunning
+}
+
+type TimeEntryService struct {
+	queries *db.Queries
+}
+
+func NewTimeEntryService(queries *db.Queries) *TimeEntryService {
+	return &TimeEntryService{
+		queries: queries,
+	}
+}
+
+// List returns the issue's time entries oldest first with its estimates and the minutes logged so far.
+// Returns sql.ErrNoRows when the issue does not exist.
+func (s *TimeEntryService) List(ctx context.Context, issueID int64) (*schemas.IssueTimeEntries, error) {
+	issue, err := s.queries.GetIssueByID(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.GetTimeEntriesByIssueID(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+
+	result := &schemas.IssueTimeEntries{
+		Entries:         make([]schemas.TimeEntry, 0, len(rows)),
+		EstimatePoints:  issue.EstimatePoints,
+		EstimateMinutes: issue.EstimateMinutes,
+	}
+	for _, row := range rows {
+		entry := db.TimeEntry{
+			ID:        row.ID,
+			IssueID:   row.IssueID,
+			UserID:    row.UserID,
+			StartedAt: row.StartedAt,
+			EndedAt:   row.EndedAt,
+			Minutes:   row.Minutes,
+			Note:      row.Note,
+			CreatedAt: row.CreatedAt,
+		}
+		user := schemas.IssueUser{ID: row.UserID, Name: row.UserName, Email: row.UserEmail}
+		result.Entries = append(result.Entries, timeEntryOf(entry, user))
+		result.LoggedMinutes += row.Minutes.Int64
+	}
+	return result, nil
+}
+
+// Log records time the user spent on the issue
+func (s *TimeEntryService) Log(ctx context.Context, issueID int64, userID int64, input schemas.CreateTimeEntryInput) (*schemas.TimeEntry, error) {
+	startedAt := input.StartedAt.Time.UTC()
+	if !input.StartedAt.Valid {
+		startedAt = time.Now().UTC().Add(-time.Duration(input.Minutes) * time.Minute)
+	}
+
+	entry, err := s.queries.CreateTimeEntry(ctx, db.CreateTimeEntryParams{
+		IssueID:   issueID,
+		UserID:    userID,
+		StartedAt: startedAt,
+		Minutes:   input.Minutes,
+		Note:      null.NewString(input.Note, input.Note != ""),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to log time: %w", err)
+	}
+	return s.build(ctx, entry)
+}
+
+// Start starts a timer on the issue. A user runs at most one timer, so a *TimerRunningError is returned
+// while one is running on any issue; it matches ErrTimerRunning.
+func (s *TimeEntryService) Start(ctx context.Context, issueID int64, userID int64, note string) (*schemas.TimeEntry, error) {
+	entry, err := s.queries.StartTimeEntry(ctx, db.StartTimeEntryParams{
+		IssueID: issueID,
+		UserID:  userID,
+		Note:    null.NewString(note, note != ""),
+	})
+	if err != nil {
+		if isTimerRunning(err) {
+			return nil, s.runningTimerError(ctx, userID)
+		}
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	return s.build(ctx, entry)
+}
+
+// Stop stops the user's timer on the issue and records the whole minutes it ran
+func (s *TimeEntryService) Stop(ctx context.Context, issueID int64, userID int64) (*schemas.TimeEntry, error) {
+	entry, err := s.queries.StopTimeEntry(ctx, db.StopTimeEntryParams{
+		UserID:  userID,
+		IssueID: issueID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNoRunningTimer
+		}
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	return s.build(ctx, entry)
+}
+
+// Delete removes a time entry, including a running timer. Only the user who logged it can delete it.
+func (s *TimeEntryService) Delete(ctx context.Context, issueID int64, entryID int64, userID int64) error {
+	entry, err := s.queries.GetTimeEntryByID(ctx, db.GetTimeEntryByIDParams{
+		ID:      entryID,
+		IssueID: issueID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrTimeEntryNotFound
+		}
+		return fmt.Errorf("failed to get time entry: %w", err)
+	}
+	if entry.UserID != userID {
+		return ErrTimeEntryNotLogger
+	}
+
+	if _, err := s.queries.DeleteTimeEntry(ctx, entryID); err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+	return nil
+}
+
+// Report groups the time logged on the project's issues between from (inclusive) and to (exclusive)
+// by user and week, and totals it together with the estimates of all the project's issues.
+func (s *TimeEntryService) Report(ctx context.Context, projectID int64, from null.Time, to null.Time) (*schemas.ProjectTimeReport, error) {
+	rows, err := s.queries.GetProjectTimeReport(ctx, db.GetProjectTimeReportParams{
+		ProjectID: int32(projectID),
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time report: %w", err)
+	}
+
+	estimates, err := s.queries.GetProjectEstimateTotals(ctx, int32(projectID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get estimate totals: %w", err)
+	}
+
+	report := &schemas.ProjectTimeReport{
+		ProjectID: projectID,
+		From:      from,
+		To:        to,
+		Users:     []schemas.TimeReportUser{},
+		Totals: schemas.ProjectTimeTotal{
+			IssueCount:          estimates.IssueCount,
+			EstimatedIssueCount: estimates.EstimatedIssueCount,
+			EstimatePoints:      estimates.EstimatePoints,
+			EstimateMinutes:     estimates.EstimateMinutes,
+		},
+	}
+
+	// Rows come ordered by week, so each user's weeks are appended oldest first
+	position := make(map[int64]int)
+	for _, row := range rows {
+		i, ok := position[row.UserID]
+		if !ok {
+			i = len(report.Users)
+			position[row.UserID] = i
+			report.Users = append(report.Users, schemas.TimeReportUser{
+				User: schemas.IssueUser{ID: row.UserID, Name: row.UserName, Email: row.UserEmail},
+			})
+		}
+		report.Users[i].Weeks = append(report.Users[i].Weeks, schemas.TimeReportWeek{
+			WeekStart: row.WeekStart.Format(time.DateOnly),
+			Minutes:   row.Minutes,
+		})
+		report.Users[i].LoggedMinutes += row.Minutes
+		report.Totals.LoggedMinutes += row.Minutes
+	}
+
+	return report, nil
+}
+
+// build loads the user of a single time entry
+func (s *TimeEntryService) build(ctx context.Context, entry db.TimeEntry) (*schemas.TimeEntry, error) {
+	user, err := s.queries.GetUserByID(ctx, entry.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry user: %w", err)
+	}
+
+	result := timeEntryOf(entry, schemas.IssueUser{ID: user.ID, Name: user.Name, Email: user.Email})
+	return &result, nil
+}
+
+func timeEntryOf(entry db.TimeEntry, user schemas.IssueUser) schemas.TimeEntry {
+	return schemas.TimeEntry{
+		ID:        entry.ID,
+		IssueID:   entry.IssueID,
+		User:      user,
+		StartedAt: entry.StartedAt,
+		EndedAt:   entry.EndedAt,
+		Minutes:   entry.Minutes,
+		Note:      entry.Note,
+		Running:   !entry.EndedAt.Valid,
+	}
+}
+
+// runningTimerError names the issue of the user's running timer. When it has been stopped in the meantime
+// or cannot be looked up, the plain ErrTimerRunning is returned.
+func (s *TimeEntryService) runningTimerError(ctx context.Context, userID int64) error {
+	running, err := s.queries.GetRunningTimeEntry(ctx, userID)
+	if err != nil {
+		return ErrTimerRunning
+	}
+	key, err := s.queries.GetIssueKey(ctx, running.IssueID)
+	if err != nil {
+		return ErrTimerRunning
+	}
+	return &TimerRunningError{IssueID: running.IssueID, IssueKey: key}
+}
+
+// isTimerRunning reports whether err is a violation of the one running timer per user index
+func isTimerRunning(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == db.PgErrUniqueViolation && pqErr.Constraint == "idx_time_entries_running_timer"
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guregu/null"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultTrashRetentionDays is how long deleted items stay in the trash when no retention is configured
+const DefaultTrashRetentionDays = 30
+
+var (
+	ErrTrashItemNotFound = errors.New("item not found in the team's trash")
+	ErrTrashParentGone   = errors.New("the item's project or column is deleted; restore it first")
+)
+
+// IssueFileStorage removes the serialized description and comment bodies stored for an issue
+type IssueFileStorage interface {
+	DeleteIssueFiles(ctx context.Context, issueID int64) error
+}
+
+type TrashService struct {
+	queries       *db.Queries
+	db            *sql.DB
+	storage       IssueFileStorage
+	retentionDays int
+	logger        *logrus.Logger
+}
+
+// NewTrashService creates a TrashService purging items deleted more than retentionDays ago.
+// A retention of zero or less uses DefaultTrashRetentionDays.
+func NewTrashService(
+	queries *db.Queries,
+	database *sql.DB,
+	storage IssueFileStorage,
+	retentionDays int,
+	logger *logrus.Logger,
+) *TrashService {
+	if retentionDays <= 0 {
+		retentionDays = DefaultTrashRetentionDays
+	}
+	return &TrashService{
+		queries:       queries,
+		db:            database,
+		storage:       storage,
+		retentionDays: retentionDays,
+		logger:        logger,
+	}
+}
+
+// List returns the team's trash. Columns and issues are only listed while their project is not deleted.
+func (s *TrashService) List(ctx context.Context, teamID int64) (*schemas.Trash, error) {
+	projects, err := s.queries.GetDeletedProjectsByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted projects: %w", err)
+	}
+	columns, err := s.queries.GetDeletedColumnsByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted columns: %w", err)
+	}
+	issues, err := s.queries.GetDeletedIssuesByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deleted issues: %w", err)
+	}
+
+	trash := &schemas.Trash{
+		RetentionDays: s.retentionDays,
+		Projects:      make([]schemas.TrashedProject, 0, len(projects)),
+		Columns:       make([]schemas.TrashedColumn, 0, len(columns)),
+		Issues:        make([]schemas.TrashedIssue, 0, len(issues)),
+	}
+	for _, project := range projects {
+		trash.Projects = append(trash.Projects, schemas.TrashedProject{
+			Project:   project,
+			DeletedAt: project.DeletedAt.Time,
+			PurgeAt:   s.purgeAt(project.DeletedAt),
+		})
+	}
+	for _, column := range columns {
+		trash.Columns = append(trash.Columns, schemas.TrashedColumn{
+			ProjectStatusColumn: column,
+			DeletedAt:           column.DeletedAt.Time,
+			PurgeAt:             s.purgeAt(column.DeletedAt),
+		})
+	}
+	for _, issue := range issues {
+		trash.Issues = append(trash.Issues, schemas.TrashedIssue{
+			Issue:     issue,
+			DeletedAt: issue.DeletedAt.Time,
+			PurgeAt:   s.purgeAt(issue.DeletedAt),
+		})
+	}
+	return trash, nil
+}
+
+// RestoreProject takes a deleted project of the team out of the trash together with its columns and issues.
+// Returns ErrTrashItemNotFound when the team has no such deleted project.
+func (s *TrashService) RestoreProject(ctx context.Context, teamID int64, projectID int64) (db.Project, error) {
+	project, err := s.queries.GetDeletedProject(ctx, db.GetDeletedProjectParams{ID: projectID, TeamID: teamID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Project{}, ErrTrashItemNotFound
+		}
+		return db.Project{}, err
+	}
+
+	project, err = s.queries.RestoreProject(ctx, project.ID)
+	if err != nil {
+		return db.Project{}, fmt.Errorf("failed to restore project: %w", err)
+	}
+	return project, nil
+}
+
+// RestoreColumn takes a deleted column of the team out of the trash. The column goes back to the position
+// it had, or to the end when the project now has fewer columns, and the columns from there on move right.
+// Returns ErrTrashItemNotFound when the team has no such deleted column, ErrTrashParentGone when its
+// project is deleted and auth.ErrProjectArchived when its project is archived.
+func (s *TrashService) RestoreColumn(ctx context.Context, teamID int64, columnID int64) (db.ProjectStatusColumn, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return db.ProjectStatusColumn{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	column, err := qtx.GetDeletedColumn(ctx, db.GetDeletedColumnParams{ID: columnID, TeamID: teamID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.ProjectStatusColumn{}, ErrTrashItemNotFound
+		}
+		return db.ProjectStatusColumn{}, err
+	}
+
+	if _, err := qtx.GetProjectByID(ctx, int64(column.ProjectID)); err != nil {
+		if err == sql.ErrNoRows {
+			return db.ProjectStatusColumn{}, ErrTrashParentGone
+		}
+		return db.ProjectStatusColumn{}, err
+	}
+	if err := auth.CheckProjectWritable(ctx, qtx, int64(column.ProjectID)); err != nil {
+		return db.ProjectStatusColumn{}, err
+	}
+
+	// Serialise with concurrent moves and deletes in the same project
+	liveColumns, err := qtx.LockProjectStatusColumnsByProjectID(ctx, column.ProjectID)
+	if err != nil {
+		return db.ProjectStatusColumn{}, fmt.Errorf("failed to lock project columns: %w", err)
+	}
+
+	position := min(column.PositionIndex, int16(len(liveColumns)))
+	err = qtx.ShiftColumnsRight(ctx, db.ShiftColumnsRightParams{
+		ProjectID:     column.ProjectID,
+		PositionIndex: position,
+	})
+	if err != nil {
+		return db.ProjectStatusColumn{}, fmt.Errorf("failed to shift columns: %w", err)
+	}
+
+	column, err = qtx.RestoreProjectStatusColumn(ctx, db.RestoreProjectStatusColumnParams{
+		ID:            column.ID,
+		PositionIndex: position,
+	})
+	if err != nil {
+		return db.ProjectStatusColumn{}, fmt.Errorf("failed to restore column: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return db.ProjectStatusColumn{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return column, nil
+}
+
+// GetIssue returns a deleted issue of the team, or ErrTrashItemNotFound when its trash has no such issue
+func (s *TrashService) GetIssue(ctx context.Context, teamID int64, issueID int64) (db.Issue, error) {
+	issue, err := s.queries.GetDeletedIssue(ctx, db.GetDeletedIssueParams{ID: issueID, TeamID: teamID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return db.Issue{}, ErrTrashItemNotFound
+		}
+		return db.Issue{}, err
+	}
+	return issue, nil
+}
+
+// RestoreIssue takes a deleted issue of the team out of the trash and records the restore in its activity.
+// The issue counts towards its column's WIP limit again unless it is archived.
+// Returns ErrTrashItemNotFound when the team has no such deleted issue, ErrTrashParentGone when its
+// column or project is deleted, auth.ErrProjectArchived when its project is archived and ErrWIPLimitExceeded
+// when the column is at its hard WIP limit.
+func (s *TrashService) RestoreIssue(ctx context.Context, teamID int64, issueID int64, actorID int64) (*schemas.IssueWithWIPWarning, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	issue, err := qtx.GetDeletedIssue(ctx, db.GetDeletedIssueParams{ID: issueID, TeamID: teamID})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTrashItemNotFound
+		}
+		return nil, err
+	}
+
+	column, err := qtx.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+	if err == nil {
+		_, err = qtx.GetProjectByID(ctx, int64(column.ProjectID))
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrTrashParentGone
+		}
+		return nil, err
+	}
+	if err := auth.CheckProjectWritable(ctx, qtx, int64(column.ProjectID)); err != nil {
+		return nil, err
+	}
+
+	var warning *schemas.WIPLimitWarning
+	if !issue.ArchivedAt.Valid {
+		warning, err = checkWIPLimit(ctx, qtx, issue.ColumnID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issue, err = qtx.RestoreIssue(ctx, issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore issue: %w", err)
+	}
+
+	err = recordActivity(ctx, qtx, issue.ID, actorID, schemas.IssueActivityRestored, "", null.String{}, null.StringFrom(issue.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &schemas.IssueWithWIPWarning{Issue: issue, WIPWarning: warning}, nil
+}
+
+// Purge permanently deletes the projects, columns and issues that have been in the trash for longer than
+// the retention, together with the issues' stored descriptions and comment bodies. Files are removed first;
+// when that fails nothing is deleted and the next run tries again.
+func (s *TrashService) Purge(ctx context.Context) error {
+	retentionDays := int32(s.retentionDays)
+
+	issueIDs, err := s.queries.GetExpiredIssueIDs(ctx, retentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to get expired issues: %w", err)
+	}
+
+	for _, issueID := range issueIDs {
+		if err := s.storage.DeleteIssueFiles(ctx, issueID); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	qtx := s.queries.WithTx(tx)
+
+	// Issues reference their column and columns their project, so they go first. Every delete checks the
+	// retention again in case an item was restored in the meantime.
+	issueCount, err := qtx.PurgeIssues(ctx, db.PurgeIssuesParams{
+		IssueIds:      issueIDs,
+		RetentionDays: retentionDays,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge issues: %w", err)
+	}
+	columnCount, err := qtx.PurgeProjectStatusColumns(ctx, retentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to purge columns: %w", err)
+	}
+	projectCount, err := qtx.PurgeProjects(ctx, retentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to purge projects: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if issueCount+columnCount+projectCount > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"issues":   issueCount,
+			"columns":  columnCount,
+			"projects": projectCount,
+		}).Info("Purged expired trash")
+	}
+	return nil
+}
+
+// RunPurge purges the trash once and then at every interval until ctx is done
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Purge(ctx); err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Error("Failed to purge trash")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeAt is when an item deleted at deletedAt will be purged
+func (s *TrashService) purgeAt(deletedAt null.Time) time.Time {
+	return deletedAt.Time.AddDate(0, 0, s.retentionDays)
+}
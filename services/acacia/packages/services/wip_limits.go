@@ -0,0 +1,37 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrWIPLimitExceeded = errors.New("column has reached its WIP limit")
+
+// checkWIPLimit locks the column and verifies that one more issue fits under its WIP limit.
+// The lock is held until the transaction ends, so concurrent changes cannot both take the last slot.
+// Over the limit, projects with hard limits get ErrWIPLimitExceeded and projects with soft limits
+// get a warning; the caller then goes ahead with the change.
+func checkWIPLimit(ctx context.Context, q *db.Queries, columnID int64) (*schemas.WIPLimitWarning, error) {
+	status, err := q.LockColumnForWIPCheck(ctx, columnID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock column: %w", err)
+	}
+
+	if !status.WipLimit.Valid || status.IssueCount < status.WipLimit.Int64 {
+		return nil, nil
+	}
+
+	if status.WipLimitMode == schemas.WIPLimitModeHard {
+		return nil, ErrWIPLimitExceeded
+	}
+
+	return &schemas.WIPLimitWarning{
+		ColumnID:   columnID,
+		WIPLimit:   status.WipLimit.Int64,
+		IssueCount: status.IssueCount + 1,
+		Message:    fmt.Sprintf("Column is over its WIP limit of %d", status.WipLimit.Int64),
+	}, nil
+}
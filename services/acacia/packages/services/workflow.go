@@ -0,0 +1,114 @@
+package services
+
+import (
+	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrTransitionNotAllowed  = errors.New("transition between columns is not allowed")
+	ErrRequiredFieldsMissing = errors.New("issue is missing fields required by the column")
+)
+
+// WorkflowViolation explains why an issue cannot enter a column.
+// It unwraps to ErrTransitionNotAllowed or ErrRequiredFieldsMissing and its message is meant for end users.
+type WorkflowViolation struct {
+	Err     error
+	Message string
+}
+
+func (v *WorkflowViolation) Error() string {
+	return v.Message
+}
+
+func (v *WorkflowViolation) Unwrap() error {
+	return v.Err
+}
+
+// checkTransition verifies that the source column's transition rules allow moving an issue to the target column.
+// A column without rules allows every move, and moving within a column is always allowed.
+func checkTransition(ctx context.Context, q *db.Queries, fromColumnID int64, toColumnID int64) error {
+	if fromColumnID == toColumnID {
+		return nil
+	}
+
+	allowed, err := q.GetAllowedTransitionColumns(ctx, fromColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column transitions: %w", err)
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(allowed))
+	for _, column := range allowed {
+		if column.ID == toColumnID {
+			return nil
+		}
+		names = append(names, fmt.Sprintf("%q", column.Name))
+	}
+
+	from, err := q.GetProjectStatusColumnByID(ctx, fromColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column: %w", err)
+	}
+	to, err := q.GetProjectStatusColumnByID(ctx, toColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get target column: %w", err)
+	}
+
+	return &WorkflowViolation{
+		Err:     ErrTransitionNotAllowed,
+		Message: fmt.Sprintf("Issues cannot move from %q to %q; allowed targets are %s", from.Name, to.Name, strings.Join(names, ", ")),
+	}
+}
+
+// checkRequiredFields verifies that the issue has every field its column requires.
+// Call it after all changes to the issue have been written so assignees and labels are included.
+func checkRequiredFields(ctx context.Context, q *db.Queries, issue db.Issue) error {
+	column, err := q.GetProjectStatusColumnByID(ctx, issue.ColumnID)
+	if err != nil {
+		return fmt.Errorf("failed to get column: %w", err)
+	}
+
+	var missing []string
+	for _, field := range column.RequiredFields {
+		present := true
+		switch field {
+		case schemas.ColumnRequiredFieldAssignee:
+			assignees, err := q.GetIssueAssignees(ctx, issue.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get issue assignees: %w", err)
+			}
+			present = len(assignees) > 0
+		case schemas.ColumnRequiredFieldDescription:
+			present = strings.TrimSpace(issue.Description.String) != ""
+		case schemas.ColumnRequiredFieldDueDate:
+			present = issue.DueDate.Valid
+		case schemas.ColumnRequiredFieldLabels:
+			labels, err := q.GetIssueLabels(ctx, issue.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get issue labels: %w", err)
+			}
+			present = len(labels) > 0
+		case schemas.ColumnRequiredFieldPriority:
+			present = issue.Priority != schemas.IssuePriorityNone
+		}
+		if !present {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return &WorkflowViolation{
+		Err:     ErrRequiredFieldsMissing,
+		Message: fmt.Sprintf("Issues in %q must have: %s", column.Name, strings.Join(missing, ", ")),
+	}
+}
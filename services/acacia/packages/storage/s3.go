@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/sirupsen/logrus"
 )
 
@@ -122,3 +123,118 @@ func (s *S3Storage) GetDescription(ctx context.Context, issueID int64) (string,
 func (s *S3Storage) getDescriptionKey(issueID int64) string {
 	return fmt.Sprintf("issues/%d/description.json", issueID)
 }
+
+// UploadCommentBody uploads a comment's serialized body to S3, next to the issue's description
+func (s *S3Storage) UploadCommentBody(ctx context.Context, issueID int64, commentID int64, content string) error {
+	key := s.getCommentBodyKey(issueID, commentID)
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader([]byte(content)),
+		ContentType: aws.String("application/json"),
+	})
+
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"issue_id":   issueID,
+			"comment_id": commentID,
+			"key":        key,
+		}).Error("Failed to upload comment body to S3")
+		return fmt.Errorf("failed to upload comment body to S3: %w", err)
+	}
+
+	return nil
+}
+
+// GetCommentBody retrieves a comment's serialized body from S3
+func (s *S3Storage) GetCommentBody(ctx context.Context, issueID int64, commentID int64) (string, error) {
+	key := s.getCommentBodyKey(issueID, commentID)
+
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"issue_id":   issueID,
+			"comment_id": commentID,
+			"key":        key,
+		}).Error("Failed to get comment body from S3")
+		return "", fmt.Errorf("failed to get comment body from S3: %w", err)
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to read S3 object body")
+		return "", fmt.Errorf("failed to read S3 object body: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// DeleteCommentBody removes a comment's serialized body from S3
+func (s *S3Storage) DeleteCommentBody(ctx context.Context, issueID int64, commentID int64) error {
+	key := s.getCommentBodyKey(issueID, commentID)
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"issue_id":   issueID,
+			"comment_id": commentID,
+			"key":        key,
+		}).Error("Failed to delete comment body from S3")
+		return fmt.Errorf("failed to delete comment body from S3: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteIssueFiles removes every object stored for an issue, i.e. its description and comment bodies
+func (s *S3Storage) DeleteIssueFiles(ctx context.Context, issueID int64) error {
+	prefix := fmt.Sprintf("issues/%d/", issueID)
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			s.logger.WithError(err).WithField("issue_id", issueID).Error("Failed to list issue files in S3")
+			return fmt.Errorf("failed to list issue files in S3: %w", err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(page.Contents))
+		for _, object := range page.Contents {
+			objects = append(objects, types.ObjectIdentifier{Key: object.Key})
+		}
+		output, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err == nil && len(output.Errors) > 0 {
+			err = fmt.Errorf("%s: %s", aws.ToString(output.Errors[0].Key), aws.ToString(output.Errors[0].Message))
+		}
+		if err != nil {
+			s.logger.WithError(err).WithField("issue_id", issueID).Error("Failed to delete issue files from S3")
+			return fmt.Errorf("failed to delete issue files from S3: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getCommentBodyKey generates the S3 key for a comment's serialized body
+func (s *S3Storage) getCommentBodyKey(issueID int64, commentID int64) string {
+	return fmt.Sprintf("issues/%d/comments/%d.json", issueID, commentID)
+}
@@ -97,8 +97,8 @@ func TestExampleUsage(t *testing.T) {
 
 		// For example, insert some test data
 		_, err = testDB.DB.ExecContext(ctx,
-			"INSERT INTO projects (name, team_id, created_at, updated_at) VALUES ($1, $2, NOW(), NOW())",
-			"Test Project", 1)
+			"INSERT INTO projects (name, team_id, key_prefix, created_at, updated_at) VALUES ($1, $2, $3, NOW(), NOW())",
+			"Test Project", 1, "TST")
 
 		if err != nil {
 			t.Fatalf("Failed to insert test data: %v", err)
@@ -0,0 +1,269 @@
+package testutils
+
+import (
+	"acacia/packages/llm"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FakeLLMProviderName is the provider name the scripted provider is registered under in integration tests
+const FakeLLMProviderName = "fake"
+
+// FakeToolCall is a tool call the scripted model makes
+type FakeToolCall struct {
+	Name string
+	Args map[string]any
+}
+
+// FakeLLMTurn is one model response within a reply.
+// Text deltas are streamed first, then the tool calls are executed against the real ToolRegistry.
+// A turn without tool calls ends the reply.
+type FakeLLMTurn struct {
+	TextDeltas []string
+	ToolCalls  []FakeToolCall
+	// Err is sent as a stream error instead of the turn's output
+	Err error
+}
+
+// FakeToolResult records the outcome of a scripted tool call
+type FakeToolResult struct {
+	Name   string
+	Args   map[string]any
+	Result string
+	Err    error
+}
+
+// FakeLLMRequest records what the application sent to the scripted provider
+type FakeLLMRequest struct {
+	APIKey      string
+	Model       string
+	Messages    []llm.Message
+	WithTools   bool
+	Schema      *llm.StructuredOutputSchema
+	ToolResults []FakeToolResult
+}
+
+// FakeLLM is a deterministic llm.ProviderFactory for tests.
+// Every streamed reply replays the configured turns from the start, and every structured
+// completion consumes the next configured document.
+type FakeLLM struct {
+	mu         sync.Mutex
+	turns      []FakeLLMTurn
+	structured []string
+	requests   []FakeLLMRequest
+}
+
+func NewFakeLLM() *FakeLLM {
+	return &FakeLLM{}
+}
+
+// Script sets the turns replayed by every streamed reply
+func (f *FakeLLM) Script(turns ...FakeLLMTurn) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.turns = turns
+}
+
+// ScriptStructured queues raw JSON documents returned by structured completions, in order
+func (f *FakeLLM) ScriptStructured(documents ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.structured = append(f.structured, documents...)
+}
+
+// Requests returns a copy of everything sent to the provider so far
+func (f *FakeLLM) Requests() []FakeLLMRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	requests := make([]FakeLLMRequest, len(f.requests))
+	for i, request := range f.requests {
+		request.Messages = append([]llm.Message(nil), request.Messages...)
+		request.ToolResults = append([]FakeToolResult(nil), request.ToolResults...)
+		requests[i] = request
+	}
+	return requests
+}
+
+// New implements llm.ProviderFactory
+func (f *FakeLLM) New(apiKey string, logger *logrus.Logger, tools *llm.ToolRegistry) llm.LLMResponseStreamer {
+	return &fakeLLMProvider{
+		fake:   f,
+		apiKey: apiKey,
+		logger: logger,
+		tools:  tools,
+	}
+}
+
+func (f *FakeLLM) record(request FakeLLMRequest) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, request)
+	return len(f.requests) - 1
+}
+
+func (f *FakeLLM) recordToolResult(index int, result FakeToolResult) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests[index].ToolResults = append(f.requests[index].ToolResults, result)
+}
+
+func (f *FakeLLM) scriptedTurns() []FakeLLMTurn {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeLLMTurn(nil), f.turns...)
+}
+
+func (f *FakeLLM) nextStructured() (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.structured) == 0 {
+		return "", false
+	}
+	document := f.structured[0]
+	f.structured = f.structured[1:]
+	return document, true
+}
+
+// fakeLLMProvider is the per-request provider instance created by FakeLLM
+type fakeLLMProvider struct {
+	fake   *FakeLLM
+	apiKey string
+	logger *logrus.Logger
+	tools  *llm.ToolRegistry
+}
+
+func (p *fakeLLMProvider) GetProviderName() string {
+	return FakeLLMProviderName
+}
+
+func (p *fakeLLMProvider) StreamCompletion(ctx context.Context, messages []llm.Message, model string) (<-chan llm.StreamChunk, error) {
+	return p.stream(ctx, messages, model, false), nil
+}
+
+func (p *fakeLLMProvider) StreamCompletionWithTools(ctx context.Context, messages []llm.Message, model string) (<-chan llm.StreamChunk, error) {
+	return p.stream(ctx, messages, model, true), nil
+}
+
+// CompleteStructured implements llm.StructuredCompleter
+func (p *fakeLLMProvider) CompleteStructured(ctx context.Context, messages []llm.Message, model string, schema llm.StructuredOutputSchema) (string, error) {
+	p.fake.record(FakeLLMRequest{
+		APIKey:   p.apiKey,
+		Model:    model,
+		Messages: append([]llm.Message(nil), messages...),
+		Schema:   &schema,
+	})
+
+	document, ok := p.fake.nextStructured()
+	if !ok {
+		return "", errors.New("fake llm: no structured output scripted")
+	}
+	return document, nil
+}
+
+func (p *fakeLLMProvider) stream(ctx context.Context, messages []llm.Message, model string, withTools bool) <-chan llm.StreamChunk {
+	index := p.fake.record(FakeLLMRequest{
+		APIKey:    p.apiKey,
+		Model:     model,
+		Messages:  append([]llm.Message(nil), messages...),
+		WithTools: withTools,
+	})
+	turns := p.fake.scriptedTurns()
+
+	out := make(chan llm.StreamChunk)
+
+	go func() {
+		defer close(out)
+
+		send := func(chunk llm.StreamChunk) bool {
+			select {
+			case out <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, turn := range turns {
+			if turn.Err != nil {
+				send(llm.StreamChunk{Done: true, Error: turn.Err})
+				return
+			}
+
+			for _, delta := range turn.TextDeltas {
+				if !send(llm.StreamChunk{Content: delta}) {
+					return
+				}
+			}
+
+			// Without tools the model can only answer with text
+			if !withTools || len(turn.ToolCalls) == 0 {
+				send(llm.StreamChunk{Done: true})
+				return
+			}
+
+			for _, call := range turn.ToolCalls {
+				result := p.executeToolCall(ctx, call)
+				p.fake.recordToolResult(index, result)
+				if result.Err != nil {
+					send(llm.StreamChunk{Done: true, Error: result.Err})
+					return
+				}
+			}
+		}
+
+		send(llm.StreamChunk{Done: true})
+	}()
+
+	return out
+}
+
+func (p *fakeLLMProvider) executeToolCall(ctx context.Context, call FakeToolCall) FakeToolResult {
+	result := FakeToolResult{Name: call.Name, Args: call.Args}
+
+	if p.tools == nil {
+		result.Err = errors.New("fake llm: no tool registry")
+		return result
+	}
+
+	tool, ok := p.tools.GetTool(call.Name)
+	if !ok {
+		result.Err = fmt.Errorf("unknown tool: %s", call.Name)
+		return result
+	}
+
+	// Round-trip the arguments through JSON so tools see the same types as with a real model
+	args := map[string]any{}
+	encoded, err := json.Marshal(call.Args)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if err := json.Unmarshal(encoded, &args); err != nil {
+		result.Err = err
+		return result
+	}
+
+	output, err := tool.Execute(ctx, args)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if s, ok := output.(string); ok {
+		result.Result = s
+		return result
+	}
+	encoded, err = json.Marshal(output)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Result = string(encoded)
+	return result
+}
@@ -105,8 +105,8 @@ func returnPort(port int) error {
 }
 
 // NewTestServer creates a new test server with automatic port allocation
-// Accepts the same parameters as config.NewServer: db.Queries and logrus.Logger
-func NewTestServer(d *config.Database, l *logrus.Logger) (*TestServer, error) {
+// Accepts the same parameters as config.NewServer: db.Queries, logrus.Logger and server options
+func NewTestServer(d *config.Database, l *logrus.Logger, opts ...config.ServerOption) (*TestServer, error) {
 	// Create test environment
 	// Generate a 32-byte encryption key for testing
 	encryptionKey := []byte("test-encryption-key-32-bytes!!!!")
@@ -119,7 +119,7 @@ func NewTestServer(d *config.Database, l *logrus.Logger) (*TestServer, error) {
 		EncryptionKey: encryptionKey,
 	}
 
-	server := config.NewServer(d, l, env)
+	server := config.NewServer(d, l, env, opts...)
 	port, err := getPort()
 
 	if err != nil {
@@ -173,6 +173,8 @@ type IntegrationTestSetup struct {
 	Server  *TestServer
 	DB      *TestDatabase
 	Queries *db.Queries
+	// LLM is the scripted provider registered as FakeLLMProviderName
+	LLM     *FakeLLM
 	Cleanup func()
 }
 
@@ -196,7 +198,8 @@ func WithIntegrationTestSetup(ctx context.Context, t *testing.T) *IntegrationTes
 	}
 	logger := logrus.New()
 	logger.SetLevel(logrus.ErrorLevel)
-	server, err := NewTestServer(database, logger)
+	fakeLLM := NewFakeLLM()
+	server, err := NewTestServer(database, logger, config.WithLLMProvider(FakeLLMProviderName, fakeLLM))
 	require.NoError(t, err)
 
 	// Start server
@@ -216,6 +219,7 @@ func WithIntegrationTestSetup(ctx context.Context, t *testing.T) *IntegrationTes
 		Server:  server,
 		DB:      testDB,
 		Queries: queries,
+		LLM:     fakeLLM,
 		Cleanup: cleanup,
 	}
 }
@@ -297,3 +301,18 @@ func CreateTeamAndAddUser(t *testing.T, ctx context.Context, setup *IntegrationT
 
 	return team.ID
 }
+
+// CreateTeamLLMAPIKey stores an API key for the provider through the API so it is encrypted like a real key
+func CreateTeamLLMAPIKey(t *testing.T, setup *IntegrationTestSetup, client *http.Client, teamID int64, provider string) {
+	reqBody, err := json.Marshal(schemas.CreateTeamLLMAPIKeyInput{
+		Provider: provider,
+		APIKey:   "test-api-key-" + provider,
+	})
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("%s/teams/%d/llm-api-keys", setup.Server.GetURL(), teamID)
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(reqBody))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+}
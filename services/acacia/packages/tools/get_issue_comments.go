@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"acacia/packages/auth"
+	"acacia/packages/db"
+	"acacia/packages/services"
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GetIssueCommentsTool returns the discussion on a specific issue
+type GetIssueCommentsTool struct {
+	queries        *db.Queries
+	logger         *logrus.Logger
+	commentService *services.IssueCommentService
+	keyService     *services.IssueKeyService
+}
+
+// NewGetIssueCommentsTool creates a new GetIssueCommentsTool
+func NewGetIssueCommentsTool(queries *db.Queries, database *sql.DB, logger *logrus.Logger) *GetIssueCommentsTool {
+	return &GetIssueCommentsTool{
+		queries:        queries,
+		logger:         logger,
+		commentService: services.NewIssueCommentService(queries, database),
+		keyService:     services.NewIssueKeyService(queries),
+	}
+}
+
+func (t *GetIssueCommentsTool) Name() string {
+	return "get_issue_comments"
+}
+
+func (t *GetIssueCommentsTool) Description() string {
+	return "Get the comments on a specific issue, oldest first, with replies nested under the comment they answer. " +
+		"Each comment includes its author and the team members it mentions. Requires the issue ID or its key, e.g. ACA-142."
+}
+
+func (t *GetIssueCommentsTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": issueArgProperties("whose comments to retrieve"),
+	}
+}
+
+func (t *GetIssueCommentsTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+	t.logger.WithField("args", args).Info("[GET_ISSUE_COMMENTS] Tool called")
+
+	issueID, err := issueIDFromArgs(ctx, t.keyService, args)
+	if err != nil {
+		t.logger.WithError(err).Error("[GET_ISSUE_COMMENTS] Invalid issue argument")
+		return nil, err
+	}
+
+	// Same authorization as GET /issues/{id}/comments
+	if err := auth.CheckIssueAccess(ctx, t.queries, issueID); err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_COMMENTS] Authorization failed")
+		return nil, err
+	}
+
+	comments, err := t.commentService.List(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_COMMENTS] Failed to fetch comments")
+		return nil, err
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"issue_id": issueID,
+		"count":    len(comments),
+	}).Info("[GET_ISSUE_COMMENTS] Successfully fetched comments")
+	return comments, nil
+}
@@ -3,23 +3,37 @@ package tools
 import (
 	"acacia/packages/auth"
 	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
 	"context"
-	"fmt"
+	"database/sql"
 
 	"github.com/sirupsen/logrus"
 )
 
 // GetIssueDetailsTool returns detailed information about a specific issue
 type GetIssueDetailsTool struct {
-	queries *db.Queries
-	logger  *logrus.Logger
+	queries          *db.Queries
+	logger           *logrus.Logger
+	assigneeService  *services.IssueAssigneeService
+	labelService     *services.IssueLabelService
+	hierarchyService *services.IssueHierarchyService
+	linkService      *services.IssueLinkService
+	keyService       *services.IssueKeyService
+	fieldService     *services.CustomFieldService
 }
 
 // NewGetIssueDetailsTool creates a new GetIssueDetailsTool
-func NewGetIssueDetailsTool(queries *db.Queries, logger *logrus.Logger) *GetIssueDetailsTool {
+func NewGetIssueDetailsTool(queries *db.Queries, database *sql.DB, logger *logrus.Logger) *GetIssueDetailsTool {
 	return &GetIssueDetailsTool{
-		queries: queries,
-		logger:  logger,
+		queries:          queries,
+		logger:           logger,
+		assigneeService:  services.NewIssueAssigneeService(queries),
+		labelService:     services.NewIssueLabelService(queries),
+		hierarchyService: services.NewIssueHierarchyService(queries, database),
+		linkService:      services.NewIssueLinkService(queries, database),
+		keyService:       services.NewIssueKeyService(queries),
+		fieldService:     services.NewCustomFieldService(queries, database),
 	}
 }
 
@@ -28,32 +42,29 @@ func (t *GetIssueDetailsTool) Name() string {
 }
 
 func (t *GetIssueDetailsTool) Description() string {
-	return "Get detailed information about a specific issue. Requires the issue ID."
+	return "Get detailed information about a specific issue, including its priority, due date, estimates, labels, reporter, assignees " +
+		"and the values of the project's custom fields. " +
+		"Also lists its parent issue ID, its sub-issues and how many of them are in a done column, and its links to other issues " +
+		"(blocks, blocked_by, relates, duplicates, duplicated_by, clones, cloned_by). " +
+		"blocked is true while a blocking issue is not in a done column. Requires the issue ID or its key, e.g. ACA-142."
 }
 
 func (t *GetIssueDetailsTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"issue_id": map[string]interface{}{
-				"type":        "number",
-				"description": "The ID of the issue to retrieve",
-			},
-		},
-		"required": []string{"issue_id"},
+		"type":       "object",
+		"properties": issueArgProperties("to retrieve"),
 	}
 }
 
 func (t *GetIssueDetailsTool) Execute(ctx context.Context, args map[string]interface{}) (interface{}, error) {
 	t.logger.WithField("args", args).Info("[GET_ISSUE_DETAILS] Tool called")
 
-	// Extract issue ID from arguments
-	issueIDFloat, ok := args["issue_id"].(float64)
-	if !ok {
-		t.logger.Error("[GET_ISSUE_DETAILS] Invalid issue_id argument")
-		return nil, fmt.Errorf("invalid issue_id: expected number")
+	// Extract issue ID, or resolve the issue key, from arguments
+	issueID, err := issueIDFromArgs(ctx, t.keyService, args)
+	if err != nil {
+		t.logger.WithError(err).Error("[GET_ISSUE_DETAILS] Invalid issue argument")
+		return nil, err
 	}
-	issueID := int64(issueIDFloat)
 
 	t.logger.WithField("issue_id", issueID).Info("[GET_ISSUE_DETAILS] Checking issue access")
 
@@ -73,6 +84,63 @@ func (t *GetIssueDetailsTool) Execute(ctx context.Context, args map[string]inter
 		return nil, err
 	}
 
+	reporter, assignees, err := t.assigneeService.GetPeople(ctx, issue)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch reporter and assignees")
+		return nil, err
+	}
+
+	labels, err := t.labelService.Get(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch labels")
+		return nil, err
+	}
+
+	children, err := t.hierarchyService.GetChildren(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch sub-issues")
+		return nil, err
+	}
+
+	links, err := t.linkService.List(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch links")
+		return nil, err
+	}
+
+	blocked, err := t.linkService.IsBlocked(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to check blocking issues")
+		return nil, err
+	}
+
+	key, err := t.keyService.Get(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch issue key")
+		return nil, err
+	}
+
+	customFields, err := t.fieldService.GetValues(ctx, issueID)
+	if err != nil {
+		t.logger.WithError(err).WithField("issue_id", issueID).Error("[GET_ISSUE_DETAILS] Failed to fetch custom fields")
+		return nil, err
+	}
+
+	details := schemas.IssueDetails{
+		Issue:        issue,
+		Key:          key,
+		Reporter:     reporter,
+		Assignees:    assignees,
+		Labels:       labels,
+		CustomFields: customFields,
+		Children:     children.Children,
+		Links:        links,
+		Blocked:      blocked,
+	}
+	if children.Progress.Total > 0 {
+		details.Progress = &children.Progress
+	}
+
 	t.logger.WithField("issue_id", issueID).Info("[GET_ISSUE_DETAILS] Successfully fetched issue details")
-	return issue, nil
+	return details, nil
 }
@@ -3,23 +3,29 @@ package tools
 import (
 	"acacia/packages/auth"
 	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
 	"context"
 	"fmt"
+	"maps"
 
+	"github.com/guregu/null"
 	"github.com/sirupsen/logrus"
 )
 
 // GetProjectDetailsTool returns detailed information about a specific project
 type GetProjectDetailsTool struct {
-	queries *db.Queries
-	logger  *logrus.Logger
+	queries        *db.Queries
+	logger         *logrus.Logger
+	projectService *services.ProjectService
 }
 
 // NewGetProjectDetailsTool creates a new GetProjectDetailsTool
 func NewGetProjectDetailsTool(queries *db.Queries, logger *logrus.Logger) *GetProjectDetailsTool {
 	return &GetProjectDetailsTool{
-		queries: queries,
-		logger:  logger,
+		queries:        queries,
+		logger:         logger,
+		projectService: services.NewProjectService(queries),
 	}
 }
 
@@ -28,19 +34,36 @@ func (t *GetProjectDetailsTool) Name() string {
 }
 
 func (t *GetProjectDetailsTool) Description() string {
-	return "Get detailed information about a specific project, including its columns and issues. Requires the project ID."
+	return "Get detailed information about a specific project, including its columns and issues with their labels. Requires the project ID. " +
+		"Each column lists its WIP limit, the column IDs issues may move to (empty means any) and the fields an issue needs before entering it. " +
+		"Issues carry their parent issue ID, and issues with sub-issues report how many are in a done column. " +
+		"blocked is true while an issue is blocked by an issue that is not in a done column. " +
+		"custom_fields lists the project's custom field definitions and every issue carries its custom field values. " +
+		"sprints lists the project's planned, active and completed sprints with their issue and point counts; issues carry their sprint_id, null for the backlog. " +
+		"Issues can be filtered by priority, label, due date and the value of one custom field."
 }
 
 func (t *GetProjectDetailsTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"project_id": map[string]interface{}{
-				"type":        "number",
-				"description": "The ID of the project to retrieve",
-			},
+	properties := map[string]interface{}{
+		"project_id": map[string]interface{}{
+			"type":        "number",
+			"description": "The ID of the project to retrieve",
 		},
-		"required": []string{"project_id"},
+	}
+	maps.Copy(properties, issueFilterProperties())
+	properties["custom_field_id"] = map[string]interface{}{
+		"type":        "number",
+		"description": "Only return issues with a matching value for this custom field of the project; requires custom_field_value",
+	}
+	properties["custom_field_value"] = map[string]interface{}{
+		"type":        "string",
+		"description": "The custom field value to match: text (case-insensitive), a number, a YYYY-MM-DD date, a user ID or a select option",
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"project_id"},
 	}
 }
 
@@ -55,6 +78,17 @@ func (t *GetProjectDetailsTool) Execute(ctx context.Context, args map[string]int
 	}
 	projectID := int64(projectIDFloat)
 
+	issueFilter, err := parseIssueFilterArgs(args)
+	if err != nil {
+		t.logger.WithError(err).Error("[GET_PROJECT_DETAILS] Invalid filter arguments")
+		return nil, err
+	}
+	filter := schemas.ProjectIssueFilter{IssueFilter: issueFilter}
+	if fieldID, ok := args["custom_field_id"].(float64); ok {
+		filter.CustomFieldID = null.IntFrom(int64(fieldID))
+		filter.CustomFieldValue, _ = args["custom_field_value"].(string)
+	}
+
 	t.logger.WithField("project_id", projectID).Info("[GET_PROJECT_DETAILS] Checking project access")
 
 	// Check authorization using shared resource checker
@@ -67,34 +101,24 @@ func (t *GetProjectDetailsTool) Execute(ctx context.Context, args map[string]int
 	t.logger.WithField("project_id", projectID).Info("[GET_PROJECT_DETAILS] Authorization passed, fetching project details")
 
 	// User is authorized - fetch project details
-	project, err := t.queries.GetProjectByID(ctx, projectID)
-	if err != nil {
-		t.logger.WithError(err).WithField("project_id", projectID).Error("[GET_PROJECT_DETAILS] Failed to fetch project")
-		return nil, err
-	}
-
-	columns, err := t.queries.GetProjectStatusColumnsByProjectID(ctx, int32(projectID))
-	if err != nil {
-		t.logger.WithError(err).WithField("project_id", projectID).Error("[GET_PROJECT_DETAILS] Failed to fetch columns")
-		return nil, err
-	}
-
-	issues, err := t.queries.GetProjectIssues(ctx, int32(projectID))
+	details, err := t.projectService.GetDetails(ctx, projectID, filter)
 	if err != nil {
-		t.logger.WithError(err).WithField("project_id", projectID).Error("[GET_PROJECT_DETAILS] Failed to fetch issues")
+		t.logger.WithError(err).WithField("project_id", projectID).Error("[GET_PROJECT_DETAILS] Failed to fetch project details")
 		return nil, err
 	}
 
 	t.logger.WithFields(logrus.Fields{
 		"project_id":   projectID,
-		"column_count": len(columns),
-		"issue_count":  len(issues),
+		"column_count": len(details.Columns),
+		"issue_count":  len(details.Issues),
 	}).Info("[GET_PROJECT_DETAILS] Successfully fetched project details")
 
 	// Return structured response
 	return map[string]interface{}{
-		"project": project,
-		"columns": columns,
-		"issues":  issues,
+		"project":       details.Project,
+		"columns":       details.Columns,
+		"custom_fields": details.CustomFields,
+		"sprints":       details.Sprints,
+		"issues":        details.Issues,
 	}, nil
 }
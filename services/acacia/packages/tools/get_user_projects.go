@@ -28,14 +28,19 @@ func (t *GetUserProjectsTool) Name() string {
 }
 
 func (t *GetUserProjectsTool) Description() string {
-	return "Get all projects that the user has access to. Returns a list of projects with their basic information."
+	return "Get all projects that the user has access to. Returns a list of projects with their basic information. Archived projects are left out unless include_archived is set."
 }
 
 func (t *GetUserProjectsTool) InputSchema() map[string]interface{} {
 	return map[string]interface{}{
-		"type":       "object",
-		"properties": map[string]interface{}{},
-		"required":   []string{},
+		"type": "object",
+		"properties": map[string]interface{}{
+			"include_archived": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also return archived projects",
+			},
+		},
+		"required": []string{},
 	}
 }
 
@@ -52,7 +57,11 @@ func (t *GetUserProjectsTool) Execute(ctx context.Context, args map[string]inter
 	t.logger.WithField("user_id", userID).Info("[GET_USER_PROJECTS] Fetching projects for user")
 
 	// Get user's projects (via team membership)
-	projects, err := t.queries.GetProjects(ctx, userID)
+	includeArchived, _ := args["include_archived"].(bool)
+	projects, err := t.queries.GetProjects(ctx, db.GetProjectsParams{
+		UserID:          userID,
+		IncludeArchived: includeArchived,
+	})
 	if err != nil {
 		t.logger.WithError(err).Error("[GET_USER_PROJECTS] Failed to fetch projects")
 		return nil, err
@@ -0,0 +1,40 @@
+package tools
+
+import (
+	"acacia/packages/auth"
+	"acacia/packages/services"
+	"context"
+	"fmt"
+)
+
+// issueArgProperties describes the two ways a tool can be pointed at an issue
+func issueArgProperties(purpose string) map[string]interface{} {
+	return map[string]interface{}{
+		"issue_id": map[string]interface{}{
+			"type":        "number",
+			"description": "The ID of the issue " + purpose,
+		},
+		"issue_key": map[string]interface{}{
+			"type":        "string",
+			"description": "The key of the issue " + purpose + ", e.g. ACA-142. Used instead of issue_id",
+		},
+	}
+}
+
+// issueIDFromArgs reads the issue from issue_key when given, resolved among the user's teams, and
+// from issue_id otherwise
+func issueIDFromArgs(ctx context.Context, keys *services.IssueKeyService, args map[string]interface{}) (int64, error) {
+	if key, ok := args["issue_key"].(string); ok && key != "" {
+		userID, ok := ctx.Value(auth.UserIDKey).(int64)
+		if !ok {
+			return 0, fmt.Errorf("unauthorized: user not authenticated")
+		}
+		return keys.Resolve(ctx, userID, key)
+	}
+
+	issueIDFloat, ok := args["issue_id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("invalid issue_id: expected number, or an issue_key")
+	}
+	return int64(issueIDFloat), nil
+}
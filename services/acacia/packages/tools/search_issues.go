@@ -3,24 +3,33 @@ package tools
 import (
 	"acacia/packages/auth"
 	"acacia/packages/db"
+	"acacia/packages/schemas"
+	"acacia/packages/services"
 	"context"
 	"fmt"
-	"strings"
+	"maps"
+	"time"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/guregu/null"
 	"github.com/sirupsen/logrus"
 )
 
 // SearchIssuesTool searches for issues across all projects the user has access to
 type SearchIssuesTool struct {
-	queries *db.Queries
-	logger  *logrus.Logger
+	queries       *db.Queries
+	logger        *logrus.Logger
+	validator     *validator.Validate
+	searchService *services.IssueSearchService
 }
 
 // NewSearchIssuesTool creates a new SearchIssuesTool
 func NewSearchIssuesTool(queries *db.Queries, logger *logrus.Logger) *SearchIssuesTool {
 	return &SearchIssuesTool{
-		queries: queries,
-		logger:  logger,
+		queries:       queries,
+		logger:        logger,
+		validator:     validator.New(),
+		searchService: services.NewIssueSearchService(queries),
 	}
 }
 
@@ -29,19 +38,70 @@ func (t *SearchIssuesTool) Name() string {
 }
 
 func (t *SearchIssuesTool) Description() string {
-	return "Search for issues across all projects the user has access to. Returns issues matching the search query."
+	return "Full-text search for issues across all projects the user has access to. Supports web-search style queries (quoted phrases, OR, -exclusion), " +
+		"optional project, column, assignee, priority, label, due date and date filters, and returns matching issues with highlighted snippets. " +
+		"Pass next_cursor from a previous result as cursor to fetch the next page."
 }
 
 func (t *SearchIssuesTool) InputSchema() map[string]interface{} {
-	return map[string]interface{}{
-		"type": "object",
-		"properties": map[string]interface{}{
-			"query": map[string]interface{}{
-				"type":        "string",
-				"description": "The search query to find matching issues",
-			},
+	properties := map[string]interface{}{
+		"query": map[string]interface{}{
+			"type":        "string",
+			"description": "The search query to find matching issues",
+		},
+		"project_id": map[string]interface{}{
+			"type":        "number",
+			"description": "Only return issues from this project",
+		},
+		"column_id": map[string]interface{}{
+			"type":        "number",
+			"description": "Only return issues in this status column",
+		},
+		"created_after": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues created at or after this RFC 3339 timestamp",
+		},
+		"created_before": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues created before this RFC 3339 timestamp",
+		},
+		"updated_after": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues updated at or after this RFC 3339 timestamp",
+		},
+		"updated_before": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues updated before this RFC 3339 timestamp",
+		},
+		"assigned_to_me": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Only return issues assigned to the current user",
+		},
+		"sort": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{schemas.IssueSearchSortRelevance, schemas.IssueSearchSortCreatedAt, schemas.IssueSearchSortUpdatedAt},
+			"description": "Sort field, defaults to relevance",
+		},
+		"order": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{schemas.IssueSearchOrderAsc, schemas.IssueSearchOrderDesc},
+			"description": "Sort direction, defaults to desc",
+		},
+		"limit": map[string]interface{}{
+			"type":        "number",
+			"description": "Maximum number of issues to return (1-100, default 20)",
+		},
+		"cursor": map[string]interface{}{
+			"type":        "string",
+			"description": "Pagination cursor returned as next_cursor by a previous search",
 		},
-		"required": []string{"query"},
+	}
+	maps.Copy(properties, issueFilterProperties())
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   []string{"query"},
 	}
 }
 
@@ -62,51 +122,140 @@ func (t *SearchIssuesTool) Execute(ctx context.Context, args map[string]interfac
 		return nil, fmt.Errorf("invalid query: expected string")
 	}
 
+	input := schemas.SearchIssuesInput{Query: query}
+
+	if projectID, ok := args["project_id"].(float64); ok {
+		input.ProjectID = null.IntFrom(int64(projectID))
+	}
+	if columnID, ok := args["column_id"].(float64); ok {
+		input.ColumnID = null.IntFrom(int64(columnID))
+	}
+
+	dateArgs := map[string]*null.Time{
+		"created_after":  &input.CreatedAfter,
+		"created_before": &input.CreatedBefore,
+		"updated_after":  &input.UpdatedAfter,
+		"updated_before": &input.UpdatedBefore,
+	}
+	for name, target := range dateArgs {
+		value, ok := args[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: expected RFC 3339 timestamp", name)
+		}
+		*target = null.TimeFrom(parsed.UTC())
+	}
+
+	filter, err := parseIssueFilterArgs(args)
+	if err != nil {
+		t.logger.WithError(err).Error("[SEARCH_ISSUES] Invalid filter arguments")
+		return nil, err
+	}
+	input.IssueFilter = filter
+
+	if assignedToMe, ok := args["assigned_to_me"].(bool); ok {
+		input.AssignedToMe = assignedToMe
+	}
+	if sort, ok := args["sort"].(string); ok {
+		input.Sort = sort
+	}
+	if order, ok := args["order"].(string); ok {
+		input.Order = order
+	}
+	if cursor, ok := args["cursor"].(string); ok {
+		input.Cursor = cursor
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		input.Limit = min(max(int(limit), 1), 100)
+	}
+
+	if err := t.validator.Struct(&input); err != nil {
+		t.logger.WithError(err).Error("[SEARCH_ISSUES] Invalid search arguments")
+		return nil, schemas.HandleIssueSearchValidationErrors(err)
+	}
+
 	t.logger.WithFields(logrus.Fields{
 		"user_id": userID,
 		"query":   query,
-	}).Info("[SEARCH_ISSUES] Fetching user projects")
+	}).Info("[SEARCH_ISSUES] Searching issues")
 
-	queryLower := strings.ToLower(query)
-
-	// Get all projects the user has access to
-	projects, err := t.queries.GetProjects(ctx, userID)
+	result, err := t.searchService.Search(ctx, userID, input)
 	if err != nil {
-		t.logger.WithError(err).Error("[SEARCH_ISSUES] Failed to fetch projects")
+		t.logger.WithError(err).Error("[SEARCH_ISSUES] Search failed")
 		return nil, err
 	}
 
-	t.logger.WithField("project_count", len(projects)).Info("[SEARCH_ISSUES] Searching issues across projects")
+	t.logger.WithFields(logrus.Fields{
+		"query":       query,
+		"match_count": len(result.Issues),
+	}).Info("[SEARCH_ISSUES] Search completed successfully")
+
+	return result, nil
+}
 
-	// Search issues in each project
-	var matchingIssues []db.Issue
-	for _, project := range projects {
-		issues, err := t.queries.GetProjectIssues(ctx, int32(project.ID))
-		if err != nil {
-			t.logger.WithError(err).WithField("project_id", project.ID).Warn("[SEARCH_ISSUES] Failed to fetch issues for project, skipping")
-			// Skip projects with errors, continue searching
-			continue
+// issueFilterProperties describes the triage filter arguments shared by tools that list issues
+func issueFilterProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"priority": map[string]interface{}{
+			"type":        "string",
+			"enum":        schemas.IssuePriorities,
+			"description": "Only return issues with this priority",
+		},
+		"label_id": map[string]interface{}{
+			"type":        "number",
+			"description": "Only return issues with this label",
+		},
+		"due_after": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues due on or after this YYYY-MM-DD date",
+		},
+		"due_before": map[string]interface{}{
+			"type":        "string",
+			"description": "Only return issues due before this YYYY-MM-DD date",
+		},
+		"include_archived": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Also return archived issues and issues of archived projects",
+		},
+	}
+}
+
+// parseIssueFilterArgs reads the arguments described by issueFilterProperties
+func parseIssueFilterArgs(args map[string]interface{}) (schemas.IssueFilter, error) {
+	var filter schemas.IssueFilter
+
+	if priority, ok := args["priority"].(string); ok && priority != "" {
+		if !schemas.IsValidIssuePriority(priority) {
+			return filter, fmt.Errorf("invalid priority: expected one of none, low, medium, high, urgent")
 		}
+		filter.Priority = priority
+	}
+	if labelID, ok := args["label_id"].(float64); ok {
+		filter.LabelID = null.IntFrom(int64(labelID))
+	}
 
-		// Filter issues by query (simple text matching)
-		for _, issue := range issues {
-			titleMatch := strings.Contains(strings.ToLower(issue.Name), queryLower)
-			descMatch := false
-			if issue.Description.Valid {
-				descMatch = strings.Contains(strings.ToLower(issue.Description.String), queryLower)
-			}
-
-			if titleMatch || descMatch {
-				matchingIssues = append(matchingIssues, issue)
-			}
+	dateArgs := map[string]*null.Time{
+		"due_after":  &filter.DueAfter,
+		"due_before": &filter.DueBefore,
+	}
+	for name, target := range dateArgs {
+		value, ok := args[name].(string)
+		if !ok || value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: expected YYYY-MM-DD date", name)
 		}
+		*target = null.TimeFrom(parsed)
 	}
 
-	t.logger.WithFields(logrus.Fields{
-		"query":        query,
-		"match_count":  len(matchingIssues),
-		"searched_projects": len(projects),
-	}).Info("[SEARCH_ISSUES] Search completed successfully")
+	if includeArchived, ok := args["include_archived"].(bool); ok {
+		filter.IncludeArchived = includeArchived
+	}
 
-	return matchingIssues, nil
+	return filter, nil
 }
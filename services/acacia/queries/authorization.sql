@@ -8,17 +8,35 @@ SELECT EXISTS (
 -- name: GetTeamIDByProject :one
 SELECT team_id
 FROM projects
-WHERE id = $1;
+WHERE id = $1 AND deleted_at IS NULL;
 
 -- name: GetTeamIDByProjectStatusColumn :one
 SELECT p.team_id
 FROM project_status_columns psc
 JOIN projects p ON psc.project_id = p.id
+WHERE psc.id = $1 AND psc.deleted_at IS NULL AND p.deleted_at IS NULL;
+
+-- name: IsProjectArchived :one
+SELECT archived_at IS NOT NULL AS archived
+FROM projects
+WHERE id = $1;
+
+-- name: IsColumnProjectArchived :one
+SELECT p.archived_at IS NOT NULL AS archived
+FROM project_status_columns psc
+JOIN projects p ON psc.project_id = p.id
 WHERE psc.id = $1;
 
+-- name: IsIssueProjectArchived :one
+SELECT p.archived_at IS NOT NULL AS archived
+FROM issues i
+JOIN project_status_columns psc ON i.column_id = psc.id
+JOIN projects p ON psc.project_id = p.id
+WHERE i.id = $1;
+
 -- name: GetTeamIDByIssue :one
 SELECT p.team_id
 FROM issues i
 JOIN project_status_columns psc ON i.column_id = psc.id
 JOIN projects p ON psc.project_id = p.id
-WHERE i.id = $1;
+WHERE i.id = $1 AND i.deleted_at IS NULL AND psc.deleted_at IS NULL AND p.deleted_at IS NULL;
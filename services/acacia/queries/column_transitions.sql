@@ -0,0 +1,36 @@
+-- name: AddColumnTransition :exec
+INSERT INTO column_transitions (from_column_id, to_column_id, created_at)
+    VALUES ($1, $2, NOW())
+ON CONFLICT (from_column_id, to_column_id)
+    DO NOTHING;
+
+-- name: DeleteColumnTransitions :exec
+DELETE FROM column_transitions
+WHERE from_column_id = $1;
+
+-- name: GetAllowedTransitionColumns :many
+SELECT
+    psc.*
+FROM
+    column_transitions ct
+    JOIN project_status_columns psc ON psc.id = ct.to_column_id
+WHERE
+    ct.from_column_id = $1
+    AND psc.deleted_at IS NULL
+ORDER BY
+    psc.position_index;
+
+-- name: GetColumnTransitionsByProjectID :many
+SELECT
+    ct.*
+FROM
+    column_transitions ct
+    JOIN project_status_columns psc ON psc.id = ct.from_column_id
+    JOIN project_status_columns target ON target.id = ct.to_column_id
+WHERE
+    psc.project_id = $1
+    AND psc.deleted_at IS NULL
+    AND target.deleted_at IS NULL
+ORDER BY
+    ct.from_column_id,
+    ct.to_column_id;
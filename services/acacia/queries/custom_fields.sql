@@ -0,0 +1,108 @@
+-- name: CreateCustomField :one
+INSERT INTO custom_fields (project_id, name, field_type, options)
+    VALUES ($1, $2, $3, $4)
+RETURNING
+    *;
+
+-- name: GetCustomFieldByID :one
+SELECT
+    *
+FROM
+    custom_fields
+WHERE
+    id = $1
+    AND project_id = $2;
+
+-- name: GetCustomFieldsByProjectID :many
+SELECT
+    *
+FROM
+    custom_fields
+WHERE
+    project_id = $1
+ORDER BY
+    name;
+
+-- name: UpdateCustomField :one
+UPDATE
+    custom_fields
+SET
+    name = @name,
+    options = @options,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND project_id = @project_id
+RETURNING
+    *;
+
+-- name: DeleteCustomField :execrows
+DELETE FROM custom_fields
+WHERE id = $1
+    AND project_id = $2;
+
+-- name: GetCustomFieldValuesByIssueIDs :many
+SELECT
+    v.issue_id,
+    v.field_id,
+    f.name,
+    f.field_type,
+    v.text_value,
+    v.number_value,
+    v.date_value,
+    v.user_id,
+    v.option_values,
+    u.name AS user_name,
+    u.email AS user_email
+FROM
+    issue_custom_field_values v
+    JOIN custom_fields f ON f.id = v.field_id
+    LEFT JOIN users u ON u.id = v.user_id
+WHERE
+    v.issue_id = ANY (@issue_ids::bigint[])
+ORDER BY
+    v.issue_id,
+    f.name;
+
+-- name: SetIssueCustomFieldValue :exec
+INSERT INTO issue_custom_field_values (issue_id, field_id, text_value, number_value, date_value, user_id, option_values)
+    VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (issue_id, field_id)
+    DO UPDATE SET
+        text_value = EXCLUDED.text_value,
+        number_value = EXCLUDED.number_value,
+        date_value = EXCLUDED.date_value,
+        user_id = EXCLUDED.user_id,
+        option_values = EXCLUDED.option_values;
+
+-- name: DeleteIssueCustomFieldValue :exec
+DELETE FROM issue_custom_field_values
+WHERE issue_id = $1
+    AND field_id = $2;
+
+-- name: RemoveDeletedCustomFieldOptions :exec
+UPDATE
+    issue_custom_field_values v
+SET
+    option_values = ARRAY (
+        SELECT
+            o
+        FROM
+            unnest(v.option_values) o
+        WHERE
+            o = ANY (f.options))
+FROM
+    custom_fields f
+WHERE
+    f.id = v.field_id
+    AND v.field_id = $1
+    AND NOT v.option_values <@ f.options;
+
+-- name: DeleteEmptyCustomFieldValues :exec
+DELETE FROM issue_custom_field_values
+WHERE field_id = $1
+    AND option_values = '{}';
+
+-- name: DeleteIssueCustomFieldValues :exec
+DELETE FROM issue_custom_field_values
+WHERE issue_id = $1;
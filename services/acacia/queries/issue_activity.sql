@@ -0,0 +1,31 @@
+-- name: CreateColumnReassignmentActivity :exec
+INSERT INTO issue_activity (issue_id, actor_id, action, field, old_value, new_value)
+SELECT
+    id,
+    sqlc.narg('actor_id')::bigint,
+    'moved',
+    'column_id',
+    column_id::text,
+    @target_column::bigint::text
+FROM
+    issues
+WHERE
+    column_id = @source_column;
+
+-- name: CreateIssueActivity :exec
+INSERT INTO issue_activity (issue_id, actor_id, action, field, old_value, new_value, created_at)
+    VALUES ($1, $2, $3, $4, $5, $6, NOW());
+
+-- name: GetIssueActivity :many
+SELECT
+    a.*,
+    u.name AS actor_name,
+    u.email AS actor_email
+FROM
+    issue_activity a
+    LEFT JOIN users u ON u.id = a.actor_id
+WHERE
+    a.issue_id = $1
+ORDER BY
+    a.created_at,
+    a.id;
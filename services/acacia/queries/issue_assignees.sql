@@ -0,0 +1,36 @@
+-- name: AddIssueAssignee :execrows
+INSERT INTO issue_assignees (issue_id, user_id)
+    VALUES ($1, $2)
+ON CONFLICT (issue_id, user_id)
+    DO NOTHING;
+
+-- name: GetIssueAssignees :many
+SELECT
+    u.id,
+    u.name,
+    u.email
+FROM
+    issue_assignees ia
+    JOIN users u ON u.id = ia.user_id
+WHERE
+    ia.issue_id = $1
+ORDER BY
+    ia.created_at,
+    u.id;
+
+-- name: RemoveIssueAssignee :execrows
+DELETE FROM issue_assignees
+WHERE issue_id = $1
+    AND user_id = $2;
+
+-- name: RemoveIssueAssigneesOutsideTeam :exec
+DELETE FROM issue_assignees ia
+WHERE ia.issue_id = @issue_id
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            team_members tm
+        WHERE
+            tm.team_id = @team_id
+            AND tm.user_id = ia.user_id);
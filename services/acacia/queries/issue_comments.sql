@@ -0,0 +1,69 @@
+-- name: AddCommentMention :exec
+INSERT INTO issue_comment_mentions (comment_id, user_id)
+    VALUES ($1, $2)
+ON CONFLICT (comment_id, user_id)
+    DO NOTHING;
+
+-- name: CreateIssueComment :one
+INSERT INTO issue_comments (issue_id, parent_id, author_id, body, has_serialized_body, created_at, updated_at)
+    VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+RETURNING
+    *;
+
+-- name: DeleteCommentMentions :exec
+DELETE FROM issue_comment_mentions
+WHERE comment_id = $1;
+
+-- name: DeleteIssueComment :execrows
+DELETE FROM issue_comments
+WHERE id = $1;
+
+-- name: GetIssueCommentByID :one
+SELECT
+    *
+FROM
+    issue_comments
+WHERE
+    id = $1
+    AND issue_id = $2;
+
+-- name: GetIssueComments :many
+SELECT
+    c.*,
+    u.name AS author_name,
+    u.email AS author_email
+FROM
+    issue_comments c
+    LEFT JOIN users u ON u.id = c.author_id
+WHERE
+    c.issue_id = $1
+ORDER BY
+    c.created_at,
+    c.id;
+
+-- name: GetMentionsByCommentIDs :many
+SELECT
+    m.comment_id,
+    u.id,
+    u.name,
+    u.email
+FROM
+    issue_comment_mentions m
+    JOIN users u ON u.id = m.user_id
+WHERE
+    m.comment_id = ANY (@comment_ids::bigint[])
+ORDER BY
+    m.comment_id,
+    u.name;
+
+-- name: UpdateIssueComment :one
+UPDATE
+    issue_comments
+SET
+    body = $2,
+    has_serialized_body = $3,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    *;
@@ -0,0 +1,45 @@
+-- name: AddIssueLabel :execrows
+INSERT INTO issue_labels (issue_id, label_id)
+    VALUES ($1, $2)
+ON CONFLICT (issue_id, label_id)
+    DO NOTHING;
+
+-- name: GetIssueLabels :many
+SELECT
+    l.*
+FROM
+    issue_labels il
+    JOIN labels l ON l.id = il.label_id
+WHERE
+    il.issue_id = $1
+ORDER BY
+    l.name;
+
+-- name: GetLabelsByIssueIDs :many
+SELECT
+    il.issue_id,
+    l.id,
+    l.team_id,
+    l.name,
+    l.color,
+    l.created_at,
+    l.updated_at
+FROM
+    issue_labels il
+    JOIN labels l ON l.id = il.label_id
+WHERE
+    il.issue_id = ANY (@issue_ids::bigint[])
+ORDER BY
+    il.issue_id,
+    l.name;
+
+-- name: RemoveIssueLabel :execrows
+DELETE FROM issue_labels
+WHERE issue_id = $1
+    AND label_id = $2;
+
+-- name: RemoveIssueLabelsOutsideTeam :exec
+DELETE FROM issue_labels il USING labels l
+WHERE l.id = il.label_id
+    AND il.issue_id = @issue_id
+    AND l.team_id <> @team_id;
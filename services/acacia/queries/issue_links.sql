@@ -0,0 +1,65 @@
+-- name: CreateIssueLink :one
+INSERT INTO issue_links (issue_id, linked_issue_id, link_type)
+    VALUES ($1, $2, $3)
+RETURNING
+    *;
+
+-- name: DeleteIssueLink :one
+DELETE FROM issue_links
+WHERE id = $1
+    AND (issue_id = $2
+        OR linked_issue_id = $2)
+RETURNING
+    *;
+
+-- name: GetBlockedIssueIDs :many
+SELECT DISTINCT
+    l.linked_issue_id
+FROM
+    issue_links l
+    JOIN issues i ON i.id = l.issue_id
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    l.link_type = 'blocks'
+    AND l.linked_issue_id = ANY (@issue_ids::bigint[])
+    AND NOT c.is_done
+    AND i.deleted_at IS NULL
+    AND i.archived_at IS NULL;
+
+-- name: GetIssueLinks :many
+SELECT
+    l.*,
+    i.id AS other_issue_id,
+    i.name AS other_issue_name,
+    i.column_id AS other_column_id,
+    c.project_id AS other_project_id,
+    c.is_done AS other_is_done
+FROM
+    issue_links l
+    JOIN issues i ON i.id = CASE WHEN l.issue_id = @issue_id THEN
+        l.linked_issue_id
+    ELSE
+        l.issue_id
+    END
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE (l.issue_id = @issue_id
+    OR l.linked_issue_id = @issue_id)
+AND i.deleted_at IS NULL
+ORDER BY
+    l.created_at,
+    l.id;
+
+-- name: RemoveIssueLinksOutsideTeam :many
+DELETE FROM issue_links l USING issues i, project_status_columns c, projects p
+WHERE i.id = CASE WHEN l.issue_id = @issue_id THEN
+        l.linked_issue_id
+    ELSE
+        l.issue_id
+    END
+    AND c.id = i.column_id
+    AND p.id = c.project_id
+    AND (l.issue_id = @issue_id
+        OR l.linked_issue_id = @issue_id)
+    AND p.team_id <> @team_id
+RETURNING
+    i.id;
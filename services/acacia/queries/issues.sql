@@ -5,8 +5,10 @@ FROM
     issues
 WHERE
     column_id = $1
+    AND deleted_at IS NULL
 ORDER BY
-    created_at DESC;
+    rank,
+    id;
 
 -- name: GetIssueByID :one
 SELECT
@@ -14,11 +16,35 @@ SELECT
 FROM
     issues
 WHERE
-    id = $1;
+    id = $1
+    AND deleted_at IS NULL;
 
 -- name: CreateIssue :one
-INSERT INTO issues (name, column_id, description, created_at, updated_at)
-    VALUES ($1, $2, $3, NOW(), NOW())
+WITH allocated AS (
+    UPDATE
+        projects
+    SET
+        next_issue_number = next_issue_number + 1
+    WHERE
+        id = (
+            SELECT
+                project_id
+            FROM
+                project_status_columns
+            WHERE
+                id = @column_id)
+        RETURNING
+            next_issue_number - 1 AS number)
+INSERT INTO issues (name, column_id, description, reporter_id, priority, due_date, parent_id, estimate_points, estimate_minutes, number, rank, created_at, updated_at)
+    VALUES (@name, @column_id, @description, @reporter_id, COALESCE(sqlc.narg('priority')::text, 'none'), @due_date, @parent_id, @estimate_points, @estimate_minutes, (
+            SELECT
+                number
+            FROM allocated), COALESCE((
+            SELECT
+                floor(MIN(rank)) - 1
+            FROM issues
+            WHERE
+                column_id = @column_id), 0), NOW(), NOW())
 RETURNING
     *;
 
@@ -28,7 +54,113 @@ UPDATE
 SET
     name = COALESCE(@name, name),
     description = COALESCE(@description, description),
-    column_id = COALESCE(@column_id, column_id),
+    column_id = COALESCE(NULLIF(@column_id::bigint, 0), column_id),
+    rank = CASE WHEN @column_id::bigint IN (0, column_id) THEN
+        rank
+    ELSE
+        COALESCE((
+            SELECT
+                floor(MAX(i.rank)) + 1
+            FROM issues i
+            WHERE
+                i.column_id = @column_id::bigint), 0)
+    END,
+    priority = COALESCE(sqlc.narg('priority')::text, priority),
+    due_date = CASE WHEN @clear_due_date::boolean THEN
+        NULL
+    ELSE
+        COALESCE(sqlc.narg('due_date')::date, due_date)
+    END,
+    estimate_points = CASE WHEN @clear_estimate::boolean THEN
+        NULL
+    ELSE
+        COALESCE(sqlc.narg('estimate_points')::integer, estimate_points)
+    END,
+    estimate_minutes = CASE WHEN @clear_estimate::boolean THEN
+        NULL
+    ELSE
+        COALESCE(sqlc.narg('estimate_minutes')::integer, estimate_minutes)
+    END,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]))
+RETURNING
+    *;
+
+-- name: GetIssueRankInColumn :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    id = @id
+    AND column_id = @column_id
+    AND deleted_at IS NULL;
+
+-- name: GetPreviousIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = @column_id
+    AND id <> @issue_id
+    AND deleted_at IS NULL
+    AND (rank < @rank
+        OR (rank = @rank
+            AND id < @neighbour_id))
+ORDER BY
+    rank DESC,
+    id DESC
+LIMIT 1;
+
+-- name: GetNextIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = @column_id
+    AND id <> @issue_id
+    AND deleted_at IS NULL
+    AND (rank > @rank
+        OR (rank = @rank
+            AND id > @neighbour_id))
+ORDER BY
+    rank,
+    id
+LIMIT 1;
+
+-- name: GetLastIssueRank :one
+SELECT
+    rank
+FROM
+    issues
+WHERE
+    column_id = @column_id
+    AND id <> @issue_id
+    AND deleted_at IS NULL
+ORDER BY
+    rank DESC
+LIMIT 1;
+
+-- name: MoveIssue :one
+UPDATE
+    issues
+SET
+    column_id = @column_id,
+    rank = CASE WHEN sqlc.narg('lower_rank')::numeric IS NULL
+        AND sqlc.narg('upper_rank')::numeric IS NULL THEN
+        0
+    WHEN sqlc.narg('upper_rank')::numeric IS NULL THEN
+        floor(sqlc.narg('lower_rank')::numeric) + 1
+    WHEN sqlc.narg('lower_rank')::numeric IS NULL THEN
+        floor(sqlc.narg('upper_rank')::numeric) - 1
+    ELSE
+        (sqlc.narg('lower_rank')::numeric + sqlc.narg('upper_rank')::numeric) * 0.5
+    END,
     updated_at = NOW()
 WHERE
     id = @id
@@ -39,11 +171,387 @@ RETURNING
 UPDATE
     issues
 SET
-    column_id = @target_column
+    column_id = @target_column,
+    rank = moved.rank
+FROM (
+    SELECT
+        i.id,
+        COALESCE((
+            SELECT
+                floor(MAX(t.rank))
+            FROM issues t
+            WHERE
+                t.column_id = @target_column), 0) + row_number() OVER (ORDER BY i.rank, i.id) AS rank
+    FROM
+        issues i
+    WHERE
+        i.column_id = @source_column) moved
+WHERE
+    issues.id = moved.id;
+
+-- name: RenumberColumnRanks :exec
+UPDATE
+    issues
+SET
+    rank = ordered.position
+FROM (
+    SELECT
+        id,
+        row_number() OVER (ORDER BY rank, id) AS position
+    FROM
+        issues
+    WHERE
+        column_id = @column_id) ordered
+WHERE
+    issues.id = ordered.id;
+
+-- name: DeleteIssue :execrows
+UPDATE
+    issues
+SET
+    deleted_at = NOW()
+WHERE
+    id = @id
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]));
+
+-- name: SearchIssues :many
+WITH matches AS (
+    SELECT
+        i.id,
+        i.name,
+        i.description,
+        i.column_id,
+        psc.project_id,
+        i.priority,
+        i.due_date,
+        i.created_at,
+        i.updated_at,
+        ts_rank(i.search_vector, websearch_to_tsquery('english', @query::text))::float8 AS rank
+    FROM
+        issues i
+        JOIN project_status_columns psc ON psc.id = i.column_id
+        JOIN projects p ON p.id = psc.project_id
+        JOIN team_members tm ON tm.team_id = p.team_id
+    WHERE
+        i.deleted_at IS NULL
+        AND psc.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        AND tm.user_id = @user_id
+        AND (@query::text = ''
+            OR i.search_vector @@ websearch_to_tsquery('english', @query::text))
+        AND (sqlc.narg('project_id')::bigint IS NULL
+            OR psc.project_id = sqlc.narg('project_id')::bigint)
+        AND (sqlc.narg('column_id')::bigint IS NULL
+            OR i.column_id = sqlc.narg('column_id')::bigint)
+        AND (sqlc.narg('created_after')::timestamp IS NULL
+            OR i.created_at >= sqlc.narg('created_after')::timestamp)
+        AND (sqlc.narg('created_before')::timestamp IS NULL
+            OR i.created_at < sqlc.narg('created_before')::timestamp)
+        AND (sqlc.narg('updated_after')::timestamp IS NULL
+            OR i.updated_at >= sqlc.narg('updated_after')::timestamp)
+        AND (sqlc.narg('updated_before')::timestamp IS NULL
+            OR i.updated_at < sqlc.narg('updated_before')::timestamp)
+        AND (sqlc.narg('assignee_id')::bigint IS NULL
+            OR EXISTS (
+                SELECT
+                    1
+                FROM
+                    issue_assignees ia
+                WHERE
+                    ia.issue_id = i.id
+                    AND ia.user_id = sqlc.narg('assignee_id')::bigint))
+        AND (sqlc.narg('priority')::text IS NULL
+            OR i.priority = sqlc.narg('priority')::text)
+        AND (sqlc.narg('label_id')::bigint IS NULL
+            OR EXISTS (
+                SELECT
+                    1
+                FROM
+                    issue_labels il
+                WHERE
+                    il.issue_id = i.id
+                    AND il.label_id = sqlc.narg('label_id')::bigint))
+        AND (sqlc.narg('due_after')::date IS NULL
+            OR i.due_date >= sqlc.narg('due_after')::date)
+        AND (sqlc.narg('due_before')::date IS NULL
+            OR i.due_date < sqlc.narg('due_before')::date)
+        AND (@include_archived::boolean
+            OR (i.archived_at IS NULL
+                AND p.archived_at IS NULL))
+),
+keyed AS (
+    SELECT
+        id,
+        name,
+        description,
+        column_id,
+        project_id,
+        priority,
+        due_date,
+        created_at,
+        updated_at,
+        rank,
+        (
+            CASE @sort_by::text
+            WHEN 'created_at' THEN
+                EXTRACT(EPOCH FROM created_at)::float8
+            WHEN 'updated_at' THEN
+                EXTRACT(EPOCH FROM updated_at)::float8
+            ELSE
+                rank
+            END)::float8 AS sort_key
+    FROM
+        matches
+)
+SELECT
+    id,
+    name,
+    description,
+    column_id,
+    project_id,
+    priority,
+    due_date,
+    created_at,
+    updated_at,
+    rank,
+    sort_key,
+    ts_headline('english', replace(replace(replace(replace(replace(name, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), websearch_to_tsquery('english', @query::text), 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+    ts_headline('english', replace(replace(replace(replace(replace(COALESCE(description, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;'), websearch_to_tsquery('english', @query::text), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=8, MaxWords=25') AS snippet
+FROM
+    keyed
+WHERE
+    sqlc.narg('cursor_id')::bigint IS NULL
+    OR (@sort_desc::boolean
+        AND (sort_key, id) < (sqlc.narg('cursor_key')::float8, sqlc.narg('cursor_id')::bigint))
+    OR (NOT @sort_desc::boolean
+        AND (sort_key, id) > (sqlc.narg('cursor_key')::float8, sqlc.narg('cursor_id')::bigint))
+ORDER BY
+    CASE WHEN @sort_desc::boolean THEN
+        sort_key
+    END DESC,
+    CASE WHEN @sort_desc::boolean THEN
+        id
+    END DESC,
+    CASE WHEN NOT @sort_desc::boolean THEN
+        sort_key
+    END ASC,
+    CASE WHEN NOT @sort_desc::boolean THEN
+        id
+    END ASC
+LIMIT @page_limit;
+
+-- name: FindSimilarIssuesInProject :many
+WITH q AS (
+    SELECT
+        replace(plainto_tsquery('english', @content::text)::text, ' & ', ' | ')::tsquery AS query
+)
+SELECT
+    i.id,
+    i.name,
+    i.description,
+    i.column_id,
+    ts_rank(i.search_vector, q.query, 32)::float8 AS score
+FROM
+    issues i
+    JOIN project_status_columns psc ON psc.id = i.column_id
+    CROSS JOIN q
+WHERE
+    psc.project_id = @project_id
+    AND i.search_vector @@ q.query
+    AND i.deleted_at IS NULL
+ORDER BY
+    score DESC,
+    i.id DESC
+LIMIT @candidate_limit;
+
+-- name: GetIssueChildren :many
+SELECT
+    *
+FROM
+    issues
+WHERE
+    parent_id = $1
+    AND deleted_at IS NULL
+ORDER BY
+    created_at,
+    id;
+
+-- name: GetSubIssueProgress :many
+SELECT
+    i.parent_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE c.is_done) AS done
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    i.parent_id = ANY (@parent_ids::bigint[])
+    AND i.deleted_at IS NULL
+GROUP BY
+    i.parent_id;
+
+-- name: IsIssueAncestor :one
+WITH RECURSIVE ancestors AS (
+    SELECT
+        id,
+        parent_id
+    FROM
+        issues
+    WHERE
+        id = @issue_id
+    UNION
+    SELECT
+        i.id,
+        i.parent_id
+    FROM
+        issues i
+        JOIN ancestors a ON i.id = a.parent_id
+)
+SELECT
+    EXISTS (
+        SELECT
+            1
+        FROM
+            ancestors
+        WHERE
+            id = @ancestor_id);
+
+-- name: SetIssueParent :one
+UPDATE
+    issues
+SET
+    parent_id = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    *;
+
+-- name: SetIssueSprint :one
+UPDATE
+    issues
+SET
+    sprint_id = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    *;
+
+-- name: SetIssueMilestone :one
+UPDATE
+    issues
+SET
+    milestone_id = $2,
+    updated_at = NOW()
 WHERE
-    column_id = @source_column;
+    id = $1
+RETURNING
+    *;
 
--- name: DeleteIssue :exec
-DELETE FROM issues
-WHERE id = $1;
+-- name: ArchiveIssue :one
+UPDATE
+    issues
+SET
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    *;
 
+-- name: TouchIssues :exec
+UPDATE
+    issues
+SET
+    updated_at = NOW()
+WHERE
+    id = ANY (@issue_ids::bigint[]);
+
+-- name: UnarchiveIssue :one
+UPDATE
+    issues
+SET
+    archived_at = NULL,
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    *;
+
+-- name: MoveIssueToProject :one
+WITH allocated AS (
+    UPDATE
+        projects
+    SET
+        next_issue_number = next_issue_number + 1
+    WHERE
+        id = (
+            SELECT
+                project_id
+            FROM
+                project_status_columns
+            WHERE
+                id = @column_id)
+        RETURNING
+            next_issue_number - 1 AS number)
+UPDATE
+    issues
+SET
+    column_id = @column_id,
+    number = (
+        SELECT
+            number
+        FROM
+            allocated),
+    rank = COALESCE((
+        SELECT
+            floor(MAX(rank)) + 1
+        FROM issues
+        WHERE
+            column_id = @column_id), 0),
+    parent_id = NULL,
+    sprint_id = NULL,
+    milestone_id = NULL,
+    updated_at = NOW()
+WHERE
+    id = @id
+RETURNING
+    *;
+
+-- name: DetachIssueChildren :exec
+UPDATE
+    issues
+SET
+    parent_id = NULL,
+    updated_at = NOW()
+WHERE
+    parent_id = $1;
+
+-- name: GetIssueIDsByKey :many
+SELECT
+    i.id
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+    JOIN team_members tm ON tm.team_id = p.team_id
+WHERE
+    tm.user_id = @user_id
+    AND p.key_prefix = @key_prefix
+    AND i.number = @number
+    AND i.deleted_at IS NULL
+    AND p.deleted_at IS NULL;
+
+-- name: GetIssueKey :one
+SELECT
+    (p.key_prefix || '-' || i.number)::text AS key
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+WHERE
+    i.id = $1;
@@ -0,0 +1,41 @@
+-- name: CreateLabel :one
+INSERT INTO labels (team_id, name, color)
+    VALUES ($1, $2, $3)
+RETURNING
+    *;
+
+-- name: GetLabelByID :one
+SELECT
+    *
+FROM
+    labels
+WHERE
+    id = $1;
+
+-- name: GetLabelsByTeamID :many
+SELECT
+    *
+FROM
+    labels
+WHERE
+    team_id = $1
+ORDER BY
+    name;
+
+-- name: UpdateLabel :one
+UPDATE
+    labels
+SET
+    name = @name,
+    color = @color,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND team_id = @team_id
+RETURNING
+    *;
+
+-- name: DeleteLabel :execrows
+DELETE FROM labels
+WHERE id = $1
+    AND team_id = $2;
@@ -0,0 +1,71 @@
+-- name: CreateMilestone :one
+INSERT INTO milestones (project_id, name, description, target_date)
+    VALUES ($1, $2, $3, $4)
+RETURNING
+    *;
+
+-- name: GetMilestoneByID :one
+SELECT
+    *
+FROM
+    milestones
+WHERE
+    id = $1
+    AND project_id = $2;
+
+-- name: GetMilestonesByProjectID :many
+SELECT
+    *
+FROM
+    milestones
+WHERE
+    project_id = $1
+ORDER BY
+    target_date NULLS LAST,
+    name;
+
+-- name: UpdateMilestone :one
+UPDATE
+    milestones
+SET
+    name = @name,
+    description = @description,
+    target_date = @target_date,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND project_id = @project_id
+RETURNING
+    *;
+
+-- name: DeleteMilestone :execrows
+DELETE FROM milestones
+WHERE id = $1
+    AND project_id = $2;
+
+-- name: GetMilestoneIssueCounts :many
+SELECT
+    i.milestone_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE c.is_done) AS done
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    i.milestone_id = ANY (@milestone_ids::bigint[])
+    AND i.deleted_at IS NULL
+GROUP BY
+    i.milestone_id;
+
+-- name: GetMilestoneDoneIssues :many
+SELECT
+    i.*
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    i.milestone_id = $1
+    AND c.is_done
+    AND i.deleted_at IS NULL
+ORDER BY
+    i.number;
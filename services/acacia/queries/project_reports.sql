@@ -0,0 +1,25 @@
+-- name: CreateProjectReport :one
+INSERT INTO project_reports (project_id, created_by, provider, model, period_start, period_end, summary, done, in_progress, blocked, risks)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING
+    *;
+
+-- name: GetProjectReportByID :one
+SELECT
+    *
+FROM
+    project_reports
+WHERE
+    id = $1
+    AND project_id = $2;
+
+-- name: GetProjectReportsByProjectID :many
+SELECT
+    *
+FROM
+    project_reports
+WHERE
+    project_id = $1
+ORDER BY
+    created_at DESC,
+    id DESC;
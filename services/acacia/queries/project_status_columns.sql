@@ -3,6 +3,8 @@ SELECT
     *
 FROM
     project_status_columns
+WHERE
+    deleted_at IS NULL
 ORDER BY
     project_id,
     position_index;
@@ -13,7 +15,64 @@ SELECT
 FROM
     project_status_columns
 WHERE
-    id = $1;
+    id = $1
+    AND deleted_at IS NULL;
+
+-- name: LockProjectStatusColumn :one
+SELECT
+    id
+FROM
+    project_status_columns
+WHERE
+    id = $1
+FOR UPDATE;
+
+-- name: LockColumnForWIPCheck :one
+SELECT
+    psc.wip_limit,
+    p.wip_limit_mode,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            issues
+        WHERE
+            issues.column_id = psc.id
+            AND issues.deleted_at IS NULL
+            AND issues.archived_at IS NULL) AS issue_count
+FROM
+    project_status_columns psc
+    JOIN projects p ON p.id = psc.project_id
+WHERE
+    psc.id = $1
+FOR UPDATE OF psc;
+
+-- name: GetColumnIssueCountsByProjectID :many
+SELECT
+    psc.id AS column_id,
+    COUNT(issues.id) AS issue_count
+FROM
+    project_status_columns psc
+    LEFT JOIN issues ON issues.column_id = psc.id
+        AND issues.deleted_at IS NULL
+        AND issues.archived_at IS NULL
+WHERE
+    psc.project_id = $1
+    AND psc.deleted_at IS NULL
+GROUP BY
+    psc.id;
+
+-- name: LockProjectStatusColumnsByProjectID :many
+SELECT
+    *
+FROM
+    project_status_columns
+WHERE
+    project_id = $1
+    AND deleted_at IS NULL
+ORDER BY
+    id
+FOR UPDATE;
 
 -- name: GetProjectStatusColumnsByProjectID :many
 SELECT
@@ -22,6 +81,7 @@ FROM
     project_status_columns
 WHERE
     project_id = $1
+    AND deleted_at IS NULL
 ORDER BY
     position_index;
 
@@ -31,16 +91,18 @@ SELECT
 FROM
     project_status_columns
 WHERE
-    project_id = $1;
+    project_id = $1
+    AND deleted_at IS NULL;
 
 -- name: CreateProjectStatusColumn :one
-INSERT INTO project_status_columns (project_id, name, position_index, created_at, updated_at)
+INSERT INTO project_status_columns (project_id, name, position_index, wip_limit, required_fields, is_done, created_at, updated_at)
     VALUES ($1, $2, COALESCE((
             SELECT
                 MAX(position_index + 1)
             FROM project_status_columns
             WHERE
-                project_id = $1), 0), NOW(), NOW())
+                project_id = $1
+                AND deleted_at IS NULL), 0), $3, COALESCE($4::text[], '{}'), $5, NOW(), NOW())
 RETURNING
     *;
 
@@ -48,14 +110,28 @@ RETURNING
 UPDATE
     project_status_columns
 SET
-    name = $2,
-    position_index = $3,
+    name = @name,
+    position_index = @position_index,
+    wip_limit = @wip_limit,
+    required_fields = COALESCE(@required_fields::text[], '{}'),
+    is_done = @is_done,
     updated_at = NOW()
 WHERE
-    id = $1
+    id = @id
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]))
 RETURNING
     *;
 
+-- name: SetProjectStatusColumnPosition :exec
+UPDATE
+    project_status_columns
+SET
+    position_index = $2,
+    updated_at = NOW()
+WHERE
+    id = $1;
+
 -- name: GetNextColumnForReassignment :one
 SELECT
     id
@@ -64,6 +140,7 @@ FROM
 WHERE
     project_id = $1
     AND position_index = @position_index + 1
+    AND deleted_at IS NULL
 LIMIT 1;
 
 -- name: ShiftColumnsLeft :exec
@@ -73,11 +150,29 @@ SET
     position_index = position_index - 1
 WHERE
     project_id = $1
-    AND position_index > $2;
+    AND position_index > $2
+    AND deleted_at IS NULL;
+
+-- name: ShiftColumnsRight :exec
+UPDATE
+    project_status_columns
+SET
+    position_index = position_index + 1
+WHERE
+    project_id = $1
+    AND position_index >= $2
+    AND deleted_at IS NULL;
 
 -- name: DeleteProjectStatusColumn :one
-DELETE FROM project_status_columns
-WHERE id = $1
+UPDATE
+    project_status_columns
+SET
+    deleted_at = NOW()
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]))
 RETURNING
     *;
 
@@ -5,7 +5,10 @@ FROM
     projects p
     JOIN team_members tm ON p.team_id = tm.team_id
 WHERE
-    tm.user_id = $1;
+    tm.user_id = @user_id
+    AND p.deleted_at IS NULL
+    AND (@include_archived::boolean
+        OR p.archived_at IS NULL);
 
 -- name: GetProjectByID :one
 SELECT
@@ -13,11 +16,12 @@ SELECT
 FROM
     projects
 WHERE
-    id = $1;
+    id = $1
+    AND deleted_at IS NULL;
 
 -- name: CreateProject :one
-INSERT INTO projects (name, team_id, created_at, updated_at)
-    VALUES ($1, $2, NOW(), NOW())
+INSERT INTO projects (name, team_id, key_prefix, created_at, updated_at)
+    VALUES ($1, $2, $3, NOW(), NOW())
 RETURNING
     *;
 
@@ -25,16 +29,50 @@ RETURNING
 UPDATE
     projects
 SET
-    name = $2,
+    name = @name,
+    wip_limit_mode = COALESCE(sqlc.narg('wip_limit_mode')::text, wip_limit_mode),
     updated_at = NOW()
 WHERE
-    id = $1
+    id = @id
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]))
 RETURNING
     *;
 
 -- name: DeleteProject :one
-DELETE FROM projects
-WHERE id = $1
+UPDATE
+    projects
+SET
+    deleted_at = NOW()
+WHERE
+    id = @id
+    AND deleted_at IS NULL
+    AND (sqlc.narg('if_updated_at')::timestamp[] IS NULL
+        OR updated_at = ANY (sqlc.narg('if_updated_at')::timestamp[]))
+RETURNING
+    *;
+
+-- name: ArchiveProject :one
+UPDATE
+    projects
+SET
+    archived_at = COALESCE(archived_at, NOW()),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
+RETURNING
+    *;
+
+-- name: UnarchiveProject :one
+UPDATE
+    projects
+SET
+    archived_at = NULL,
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND deleted_at IS NULL
 RETURNING
     *;
 
@@ -45,5 +83,41 @@ FROM
     project_status_columns
     JOIN issues ON project_status_columns.id = issues.column_id
 WHERE
-    project_id = $1;
+    project_id = @project_id
+    AND issues.deleted_at IS NULL
+    AND (sqlc.narg('priority')::text IS NULL
+        OR issues.priority = sqlc.narg('priority')::text)
+    AND (sqlc.narg('label_id')::bigint IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                issue_labels il
+            WHERE
+                il.issue_id = issues.id
+                AND il.label_id = sqlc.narg('label_id')::bigint))
+    AND (sqlc.narg('due_after')::date IS NULL
+        OR issues.due_date >= sqlc.narg('due_after')::date)
+    AND (sqlc.narg('due_before')::date IS NULL
+        OR issues.due_date < sqlc.narg('due_before')::date)
+    AND (sqlc.narg('custom_field_id')::bigint IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                issue_custom_field_values v
+            WHERE
+                v.issue_id = issues.id
+                AND v.field_id = sqlc.narg('custom_field_id')::bigint
+                AND (lower(v.text_value) = lower(sqlc.narg('custom_field_text')::text)
+                    OR v.number_value = sqlc.narg('custom_field_number')::float8
+                    OR v.date_value = sqlc.narg('custom_field_date')::date
+                    OR v.user_id = sqlc.narg('custom_field_user_id')::bigint
+                    OR v.option_values @> ARRAY[sqlc.narg('custom_field_option')::text])))
+    AND (@include_archived::boolean
+        OR issues.archived_at IS NULL)
+ORDER BY
+    project_status_columns.position_index,
+    issues.rank,
+    issues.id;
 
@@ -0,0 +1,117 @@
+-- name: CreateSprint :one
+INSERT INTO sprints (project_id, name, goal, start_date, end_date)
+    VALUES ($1, $2, $3, $4, $5)
+RETURNING
+    *;
+
+-- name: GetSprintByID :one
+SELECT
+    *
+FROM
+    sprints
+WHERE
+    id = $1
+    AND project_id = $2;
+
+-- name: GetSprintsByProjectID :many
+SELECT
+    *
+FROM
+    sprints
+WHERE
+    project_id = $1
+ORDER BY
+    start_date,
+    id;
+
+-- name: GetNextPlannedSprint :one
+SELECT
+    *
+FROM
+    sprints
+WHERE
+    project_id = @project_id
+    AND status = 'planned'
+    AND id <> @sprint_id
+ORDER BY
+    start_date,
+    id
+LIMIT 1;
+
+-- name: UpdateSprint :one
+UPDATE
+    sprints
+SET
+    name = @name,
+    goal = @goal,
+    start_date = @start_date,
+    end_date = @end_date,
+    updated_at = NOW()
+WHERE
+    id = @id
+    AND project_id = @project_id
+RETURNING
+    *;
+
+-- name: DeleteSprint :execrows
+DELETE FROM sprints
+WHERE id = $1
+    AND project_id = $2;
+
+-- name: StartSprint :one
+UPDATE
+    sprints
+SET
+    status = 'active',
+    started_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND status = 'planned'
+RETURNING
+    *;
+
+-- name: CompleteSprint :one
+UPDATE
+    sprints
+SET
+    status = 'completed',
+    completed_at = NOW(),
+    updated_at = NOW()
+WHERE
+    id = $1
+    AND status = 'active'
+RETURNING
+    *;
+
+-- name: MoveUnfinishedSprintIssues :many
+UPDATE
+    issues i
+SET
+    sprint_id = sqlc.narg('target_sprint_id'),
+    updated_at = NOW()
+FROM
+    project_status_columns c
+WHERE
+    c.id = i.column_id
+    AND i.sprint_id = @sprint_id
+    AND NOT c.is_done
+    AND i.deleted_at IS NULL
+RETURNING
+    i.*;
+
+-- name: GetSprintIssueCounts :many
+SELECT
+    i.sprint_id,
+    COUNT(*) AS total,
+    COUNT(*) FILTER (WHERE c.is_done) AS done,
+    COALESCE(SUM(i.estimate_points), 0)::bigint AS points,
+    COALESCE(SUM(i.estimate_points) FILTER (WHERE c.is_done), 0)::bigint AS done_points
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    i.sprint_id = ANY (@sprint_ids::bigint[])
+    AND i.deleted_at IS NULL
+GROUP BY
+    i.sprint_id;
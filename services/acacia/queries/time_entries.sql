@@ -0,0 +1,113 @@
+-- name: CreateTimeEntry :one
+INSERT INTO time_entries (issue_id, user_id, started_at, ended_at, minutes, note, created_at)
+    VALUES (@issue_id, @user_id, @started_at, @started_at::timestamp + make_interval(mins => @minutes::integer), @minutes, @note, NOW())
+RETURNING
+    *;
+
+-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries
+WHERE id = $1;
+
+-- name: GetProjectEstimateTotals :one
+SELECT
+    COUNT(*) AS issue_count,
+    COUNT(i.estimate_points) AS estimated_issue_count,
+    COALESCE(SUM(i.estimate_points), 0)::bigint AS estimate_points,
+    COALESCE(SUM(i.estimate_minutes), 0)::bigint AS estimate_minutes
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+WHERE
+    c.project_id = $1
+    AND i.deleted_at IS NULL;
+
+-- name: GetProjectTimeReport :many
+SELECT
+    u.id AS user_id,
+    u.name AS user_name,
+    u.email AS user_email,
+    date_trunc('week', t.started_at)::date AS week_start,
+    SUM(t.minutes)::bigint AS minutes
+FROM
+    time_entries t
+    JOIN issues i ON i.id = t.issue_id
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN users u ON u.id = t.user_id
+WHERE
+    c.project_id = @project_id
+    AND t.minutes IS NOT NULL
+    AND i.deleted_at IS NULL
+    AND (sqlc.narg('from')::date IS NULL
+        OR t.started_at >= sqlc.narg('from')::date)
+    AND (sqlc.narg('to')::date IS NULL
+        OR t.started_at < sqlc.narg('to')::date)
+GROUP BY
+    u.id,
+    u.name,
+    u.email,
+    week_start
+ORDER BY
+    week_start,
+    u.name,
+    u.id;
+
+-- name: GetRunningTimeEntry :one
+SELECT
+    *
+FROM
+    time_entries
+WHERE
+    user_id = $1
+    AND ended_at IS NULL;
+
+-- name: GetTimeEntriesByIssueID :many
+SELECT
+    t.*,
+    u.name AS user_name,
+    u.email AS user_email
+FROM
+    time_entries t
+    JOIN users u ON u.id = t.user_id
+WHERE
+    t.issue_id = $1
+ORDER BY
+    t.started_at,
+    t.id;
+
+-- name: GetTimeEntryByID :one
+SELECT
+    *
+FROM
+    time_entries
+WHERE
+    id = $1
+    AND issue_id = $2;
+
+-- name: StartTimeEntry :one
+INSERT INTO time_entries (issue_id, user_id, started_at, note, created_at)
+    VALUES ($1, $2, NOW(), $3, NOW())
+RETURNING
+    *;
+
+-- name: StopIssueTimeEntries :exec
+UPDATE
+    time_entries
+SET
+    ended_at = NOW(),
+    minutes = GREATEST(ROUND(EXTRACT(EPOCH FROM NOW() - started_at) / 60), 0)::integer
+WHERE
+    issue_id = $1
+    AND ended_at IS NULL;
+
+-- name: StopTimeEntry :one
+UPDATE
+    time_entries
+SET
+    ended_at = NOW(),
+    minutes = GREATEST(ROUND(EXTRACT(EPOCH FROM NOW() - started_at) / 60), 0)::integer
+WHERE
+    user_id = $1
+    AND issue_id = $2
+    AND ended_at IS NULL
+RETURNING
+    *;
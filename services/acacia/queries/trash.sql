@@ -0,0 +1,139 @@
+-- name: GetDeletedProjectsByTeamID :many
+SELECT
+    *
+FROM
+    projects
+WHERE
+    team_id = $1
+    AND deleted_at IS NOT NULL
+ORDER BY
+    deleted_at DESC,
+    id DESC;
+
+-- name: GetDeletedColumnsByTeamID :many
+SELECT
+    psc.*
+FROM
+    project_status_columns psc
+    JOIN projects p ON p.id = psc.project_id
+WHERE
+    p.team_id = $1
+    AND psc.deleted_at IS NOT NULL
+    AND p.deleted_at IS NULL
+ORDER BY
+    psc.deleted_at DESC,
+    psc.id DESC;
+
+-- name: GetDeletedIssuesByTeamID :many
+SELECT
+    i.*
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+WHERE
+    p.team_id = $1
+    AND i.deleted_at IS NOT NULL
+    AND c.deleted_at IS NULL
+    AND p.deleted_at IS NULL
+ORDER BY
+    i.deleted_at DESC,
+    i.id DESC;
+
+-- name: GetDeletedProject :one
+SELECT
+    *
+FROM
+    projects
+WHERE
+    id = $1
+    AND team_id = $2
+    AND deleted_at IS NOT NULL;
+
+-- name: GetDeletedColumn :one
+SELECT
+    psc.*
+FROM
+    project_status_columns psc
+    JOIN projects p ON p.id = psc.project_id
+WHERE
+    psc.id = $1
+    AND p.team_id = $2
+    AND psc.deleted_at IS NOT NULL;
+
+-- name: GetDeletedIssue :one
+SELECT
+    i.*
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+WHERE
+    i.id = $1
+    AND p.team_id = $2
+    AND i.deleted_at IS NOT NULL;
+
+-- name: RestoreProject :one
+UPDATE
+    projects
+SET
+    deleted_at = NULL
+WHERE
+    id = $1
+RETURNING
+    *;
+
+-- name: RestoreProjectStatusColumn :one
+UPDATE
+    project_status_columns
+SET
+    deleted_at = NULL,
+    position_index = $2,
+    updated_at = NOW()
+WHERE
+    id = $1
+RETURNING
+    *;
+
+-- name: RestoreIssue :one
+UPDATE
+    issues
+SET
+    deleted_at = NULL
+WHERE
+    id = $1
+RETURNING
+    *;
+
+-- name: GetExpiredIssueIDs :many
+SELECT
+    i.id
+FROM
+    issues i
+    JOIN project_status_columns c ON c.id = i.column_id
+    JOIN projects p ON p.id = c.project_id
+WHERE
+    i.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+    OR c.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+    OR p.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+ORDER BY
+    i.id;
+
+-- name: PurgeIssues :execrows
+DELETE FROM issues i USING project_status_columns c, projects p
+WHERE c.id = i.column_id
+    AND p.id = c.project_id
+    AND i.id = ANY (@issue_ids::bigint[])
+    AND (i.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+        OR c.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+        OR p.deleted_at < NOW() - make_interval(days => @retention_days::integer));
+
+-- name: PurgeProjectStatusColumns :execrows
+DELETE FROM project_status_columns c USING projects p
+WHERE p.id = c.project_id
+    AND (c.deleted_at < NOW() - make_interval(days => @retention_days::integer)
+        OR p.deleted_at < NOW() - make_interval(days => @retention_days::integer));
+
+-- name: PurgeProjects :execrows
+DELETE FROM projects
+WHERE deleted_at < NOW() - make_interval(days => @retention_days::integer);